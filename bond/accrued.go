@@ -0,0 +1,129 @@
+package bond
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/meenmo/molib/utils"
+)
+
+// AccrualOptions are the inputs to AccruedInterestWithOptions that the cash flows alone do
+// not carry.
+type AccrualOptions struct {
+	// IssueDate starts the first coupon period. It is needed when no cash flow in the slice
+	// falls on or before settlement and the first coupon is irregular (short or long).
+	IssueDate time.Time
+	// ExDividend marks settlement inside the next coupon's ex-dividend period: the seller
+	// keeps that coupon and the accrued is negative, the interest from settlement to it.
+	ExDividend bool
+}
+
+// AccruedInterest returns the coupon accrued at settlement, per 100 face, for a bond
+// paying couponRate (annual, in percent) frequency times a year. It is
+// AccruedInterestWithOptions with no issue date and settlement outside any ex-dividend
+// period.
+func AccruedInterest(settlement time.Time, cashflows []Cashflow, couponRate float64, frequency int, dayCount string) (float64, error) {
+	return AccruedInterestWithOptions(settlement, cashflows, couponRate, frequency, dayCount, AccrualOptions{})
+}
+
+// AccruedInterestWithOptions returns the coupon accrued at settlement, per 100 face.
+//
+// The current coupon period ends on the first cash flow after settlement. It starts on the
+// latest cash flow on or before settlement, so settling on a coupon date accrues nothing;
+// without one, on opts.IssueDate, or else on the regular coupon date before the period end
+// (stepped back from the final cash flow, see couponDateBefore). A regular start after
+// settlement means a long first coupon, and is an error without opts.IssueDate.
+//
+// dayCount "ACT/ACT" (or empty) accrues ACT/ACT ICMA: couponRate/frequency × days accrued
+// / days in period, summed over the regular quasi-coupon periods an irregular first coupon
+// spans. Any other convention accrues couponRate × utils.YearFraction.
+func AccruedInterestWithOptions(settlement time.Time, cashflows []Cashflow, couponRate float64, frequency int, dayCount string, opts AccrualOptions) (float64, error) {
+	if settlement.IsZero() {
+		return 0, fmt.Errorf("AccruedInterest: settlement is required")
+	}
+	if frequency <= 0 || 12%frequency != 0 {
+		return 0, fmt.Errorf("AccruedInterest: unsupported frequency %d", frequency)
+	}
+	months := 12 / frequency
+
+	var prev, next, anchor time.Time
+	for _, cf := range cashflows {
+		if cf.Date.After(settlement) {
+			if next.IsZero() || cf.Date.Before(next) {
+				next = cf.Date
+			}
+		} else if cf.Date.After(prev) {
+			prev = cf.Date
+		}
+		if cf.Date.After(anchor) {
+			anchor = cf.Date
+		}
+	}
+	if next.IsZero() {
+		return 0, fmt.Errorf("AccruedInterest: no cashflow after settlement %s", settlement.Format("2006-01-02"))
+	}
+	if opts.ExDividend {
+		return -accrual(settlement, next, next, anchor, couponRate, frequency, dayCount), nil
+	}
+
+	start := prev
+	switch {
+	case !start.IsZero():
+	case !opts.IssueDate.IsZero():
+		if opts.IssueDate.After(settlement) {
+			return 0, fmt.Errorf("AccruedInterest: issue date %s after settlement %s",
+				opts.IssueDate.Format("2006-01-02"), settlement.Format("2006-01-02"))
+		}
+		start = opts.IssueDate
+	default:
+		start = couponDateBefore(anchor, next, months)
+		if start.After(settlement) {
+			return 0, fmt.Errorf("AccruedInterest: regular period to %s starts %s, after settlement %s; a long first coupon needs IssueDate",
+				next.Format("2006-01-02"), start.Format("2006-01-02"), settlement.Format("2006-01-02"))
+		}
+	}
+	return accrual(start, settlement, next, anchor, couponRate, frequency, dayCount), nil
+}
+
+// accrual returns the coupon accrued over [from, to] in the coupon period ending on
+// periodEnd, per 100 face. ACT/ACT ICMA splits [from, to] across the regular quasi-coupon
+// periods back from periodEnd, each accruing its days over its own length.
+func accrual(from, to, periodEnd, anchor time.Time, couponRate float64, frequency int, dayCount string) float64 {
+	if dayCount != "" && dayCount != "ACT/ACT" {
+		return couponRate * utils.YearFraction(from, to, dayCount)
+	}
+	periods := 0.0
+	for end := periodEnd; end.After(from); {
+		start := couponDateBefore(anchor, end, 12/frequency)
+		lo, hi := start, end
+		if from.After(lo) {
+			lo = from
+		}
+		if to.Before(hi) {
+			hi = to
+		}
+		if hi.After(lo) {
+			periods += float64(daysBetween(lo, hi)) / float64(daysBetween(start, end))
+		}
+		end = start
+	}
+	return couponRate / float64(frequency) * periods
+}
+
+// DirtyToClean returns the clean price for a dirty price, both per 100 face.
+func DirtyToClean(dirty float64, settlement time.Time, cashflows []Cashflow, couponRate float64, frequency int, dayCount string) (float64, error) {
+	ai, err := AccruedInterest(settlement, cashflows, couponRate, frequency, dayCount)
+	if err != nil {
+		return 0, err
+	}
+	return dirty - ai, nil
+}
+
+// CleanToDirty returns the dirty price for a clean price, both per 100 face.
+func CleanToDirty(clean float64, settlement time.Time, cashflows []Cashflow, couponRate float64, frequency int, dayCount string) (float64, error) {
+	ai, err := AccruedInterest(settlement, cashflows, couponRate, frequency, dayCount)
+	if err != nil {
+		return 0, err
+	}
+	return clean + ai, nil
+}
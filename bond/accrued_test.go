@@ -0,0 +1,83 @@
+package bond_test
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/meenmo/molib/bond"
+)
+
+func TestAccruedInterest(t *testing.T) {
+	t.Parallel()
+
+	d := func(y int, m time.Month, day int) time.Time { return time.Date(y, m, day, 0, 0, 0, 0, time.UTC) }
+	// A 4% semi-annual bond paying 15 Feb / 15 Aug.
+	cfs := []bond.Cashflow{
+		{Date: d(2026, 2, 15), Coupon: 2},
+		{Date: d(2026, 8, 15), Coupon: 2},
+		{Date: d(2027, 2, 15), Coupon: 2, Principal: 100},
+	}
+
+	// A 4% bond paying on the last day of February and August.
+	monthEnd := []bond.Cashflow{
+		{Date: d(2026, 2, 28), Coupon: 2},
+		{Date: d(2026, 8, 31), Coupon: 2},
+		{Date: d(2027, 2, 28), Coupon: 2, Principal: 100},
+	}
+	// Issued 15 Jan 2025 with a long first coupon on 30 Sep 2025, then 31 Mar / 30 Sep.
+	longFirst := []bond.Cashflow{
+		{Date: d(2025, 9, 30), Coupon: 2 * (1 + 75.0/182)},
+		{Date: d(2026, 3, 31), Coupon: 2},
+		{Date: d(2026, 9, 30), Coupon: 2, Principal: 100},
+	}
+
+	cases := []struct {
+		name       string
+		settlement time.Time
+		cfs        []bond.Cashflow
+		dayCount   string
+		opts       bond.AccrualOptions
+		want       float64
+	}{
+		// 15 Feb → 12 Mar is 25 of 181 days.
+		{"ACT/ACT", d(2026, 3, 12), cfs, "ACT/ACT", bond.AccrualOptions{}, 2.0 * 25 / 181},
+		// 30/360: 15 Feb → 12 Mar is 27 days.
+		{"30/360", d(2026, 3, 12), cfs, "30/360", bond.AccrualOptions{}, 4.0 * 27 / 360},
+		{"coupon date", d(2026, 8, 15), cfs, "ACT/ACT", bond.AccrualOptions{}, 0},
+		// Ex-dividend on 10 Aug: the 15 Aug coupon goes to the seller, so the buyer pays
+		// back 5 of the 181 days in [15 Feb, 15 Aug].
+		{"ex-dividend", d(2026, 8, 10), cfs, "ACT/ACT", bond.AccrualOptions{ExDividend: true}, -2.0 * 5 / 181},
+		// The period before 28 Feb 2026 starts on 31 Aug 2025, not 28 Aug.
+		{"month-end coupon date", d(2025, 8, 31), monthEnd, "ACT/ACT", bond.AccrualOptions{}, 0},
+		{"month-end", d(2025, 9, 30), monthEnd, "ACT/ACT", bond.AccrualOptions{}, 2.0 * 30 / 181},
+		// 15 Jan → 15 Mar is 59 days of the quasi-period 30 Sep 2024 → 31 Mar 2025 (182 days).
+		{"long first coupon", d(2025, 3, 15), longFirst, "ACT/ACT", bond.AccrualOptions{IssueDate: d(2025, 1, 15)}, 2.0 * 59 / 182},
+	}
+	for _, tc := range cases {
+		got, err := bond.AccruedInterestWithOptions(tc.settlement, tc.cfs, 4, 2, tc.dayCount, tc.opts)
+		if err != nil {
+			t.Fatalf("%s: AccruedInterest: %v", tc.name, err)
+		}
+		if math.Abs(got-tc.want) > 1e-12 {
+			t.Errorf("%s: accrued %.12f, want %.12f", tc.name, got, tc.want)
+		}
+	}
+
+	// Without the issue date the long first coupon's start is unknown.
+	if _, err := bond.AccruedInterest(d(2025, 3, 15), longFirst, 4, 2, "ACT/ACT"); err == nil {
+		t.Errorf("long first coupon without IssueDate: expected error")
+	}
+
+	dirty, err := bond.CleanToDirty(99.5, d(2026, 3, 12), cfs, 4, 2, "ACT/ACT")
+	if err != nil {
+		t.Fatalf("CleanToDirty: %v", err)
+	}
+	clean, err := bond.DirtyToClean(dirty, d(2026, 3, 12), cfs, 4, 2, "ACT/ACT")
+	if err != nil {
+		t.Fatalf("DirtyToClean: %v", err)
+	}
+	if math.Abs(dirty-(99.5+2.0*25/181)) > 1e-12 || math.Abs(clean-99.5) > 1e-12 {
+		t.Errorf("clean 99.5 → dirty %.12f → clean %.12f", dirty, clean)
+	}
+}
@@ -35,12 +35,31 @@ type ASWInput struct {
 	// "PAR-PAR" (default): PV01 uses par notional.
 	// "mms": PV01 uses dirty price as notional (Matched-Maturity Spread).
 	ASWType ASWType
+
+	// AlignSwapMaturity ends the swap leg's final period (accrual end and payment) on the
+	// bond's final cashflow date as given, instead of the business-day adjusted maturity.
+	// Use it when the bond matures on a holiday so both legs accrue to the same date.
+	AlignSwapMaturity bool
 }
 
 type ASWResult struct {
 	SpreadBP float64
 	PVBondRF float64
 	PV01     float64
+
+	// FloatNotional is the notional the floating leg accrues on: par for PAR-PAR,
+	// dirty price for MMS.
+	FloatNotional float64
+	// FloatPeriods is the synthetic floating leg schedule behind PV01, including
+	// periods paid before settlement (which carry no PV).
+	FloatPeriods []ASWPeriod
+}
+
+// ASWPeriod is one period of the synthetic floating leg used by ComputeASWSpread.
+type ASWPeriod struct {
+	swap.SchedulePeriod
+	Accrual float64 // year fraction under the float leg day count
+	DF      float64 // discount factor to PayDate
 }
 
 // ComputeASWSpread computes the asset swap spread (in bp) using the approximation:
@@ -84,15 +103,24 @@ func ComputeASWSpread(in ASWInput) (ASWResult, error) {
 	if err != nil {
 		return ASWResult{}, fmt.Errorf("ComputeASWSpread: float leg schedule: %w", err)
 	}
+	if in.AlignSwapMaturity && len(periods) > 0 {
+		last := &periods[len(periods)-1]
+		last.EndDate = maturity
+		last.PayDate = maturity
+		last.AccrualDays = int(utils.Days(last.StartDate, maturity))
+	}
 
 	// Compute annuity factor (sum of discounted accruals).
 	annuityFactor := 0.0
+	floatPeriods := make([]ASWPeriod, 0, len(periods))
 	for _, p := range periods {
+		accrual := utils.YearFraction(p.StartDate, p.EndDate, string(in.FloatLeg.DayCount))
+		df := in.DiscountCurve.DF(p.PayDate)
+		floatPeriods = append(floatPeriods, ASWPeriod{SchedulePeriod: p, Accrual: accrual, DF: df})
 		if p.PayDate.Before(in.SettlementDate) {
 			continue
 		}
-		accrual := utils.YearFraction(p.StartDate, p.EndDate, string(in.FloatLeg.DayCount))
-		annuityFactor += accrual * in.DiscountCurve.DF(p.PayDate)
+		annuityFactor += accrual * df
 	}
 	if annuityFactor == 0 {
 		return ASWResult{}, fmt.Errorf("ComputeASWSpread: annuity factor is zero")
@@ -110,8 +138,10 @@ func ComputeASWSpread(in ASWInput) (ASWResult, error) {
 	spreadBP := (pvBondRF - in.DirtyPrice) / pv01
 
 	return ASWResult{
-		SpreadBP: spreadBP,
-		PVBondRF: pvBondRF,
-		PV01:     pv01,
+		SpreadBP:      spreadBP,
+		PVBondRF:      pvBondRF,
+		PV01:          pv01,
+		FloatNotional: notionalForPV01,
+		FloatPeriods:  floatPeriods,
 	}, nil
 }
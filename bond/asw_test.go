@@ -270,38 +270,17 @@ func buildCurveFromConvention(settlement time.Time, quotes map[string]float64, c
 
 func floatLegFromFixture(fixture aswFixture) (market.LegConvention, error) {
 	if fixture.FloatingSwapLeg != "" {
-		return floatLegFromString(fixture.FloatingSwapLeg)
+		return swaps.LegByName(fixture.FloatingSwapLeg)
 	}
 	if fixture.FloatLegConvention != "" {
-		return floatLegFromString(fixture.FloatLegConvention)
+		return swaps.LegByName(fixture.FloatLegConvention)
 	}
 	if fixture.CurveFloatIndex != "" {
-		return floatLegFromString(fixture.CurveFloatIndex)
+		return swaps.LegByName(fixture.CurveFloatIndex)
 	}
 	return market.LegConvention{}, fmt.Errorf("swap floating leg is required (set floating_swap_leg)")
 }
 
-func floatLegFromString(value string) (market.LegConvention, error) {
-	switch value {
-	case "ESTR", "ESTRFloating":
-		return swaps.ESTRFloating, nil
-	case "EURIBOR3M", "EURIBOR3MFloating":
-		return swaps.EURIBOR3MFloating, nil
-	case "EURIBOR6M", "EURIBOR6MFloating":
-		return swaps.EURIBOR6MFloating, nil
-	case "KRXCD91DFloating":
-		return swaps.KRXCD91DFloating, nil
-	case "TIBOR3M", "TIBOR3MFloating":
-		return swaps.TIBOR3MFloating, nil
-	case "TIBOR6M", "TIBOR6MFloating":
-		return swaps.TIBOR6MFloating, nil
-	case "TONAR", "TONARFloating":
-		return swaps.TONARFloating, nil
-	default:
-		return market.LegConvention{}, fmt.Errorf("unknown float leg %q", value)
-	}
-}
-
 func buildKRXCurve(settlement time.Time, quotes []curveQuote) (*krxch.Curve, error) {
 	if settlement.IsZero() {
 		return nil, fmt.Errorf("settlement date is required")
@@ -486,3 +465,67 @@ func TestASW_MMS_AtPar(t *testing.T) {
 
 	t.Logf("At par: Par-Par ASW=%.6f bp, MMS ASW=%.6f bp", parParResult.SpreadBP, mmsResult.SpreadBP)
 }
+
+func TestASW_AlignSwapMaturityOnHoliday(t *testing.T) {
+	t.Parallel()
+
+	settlement := time.Date(2026, 6, 15, 0, 0, 0, 0, time.UTC)
+	// Annual coupons on business days; the final date is a Saturday the issuer did not adjust.
+	dates := []time.Time{
+		time.Date(2027, 6, 15, 0, 0, 0, 0, time.UTC),
+		time.Date(2028, 6, 15, 0, 0, 0, 0, time.UTC),
+		time.Date(2029, 6, 15, 0, 0, 0, 0, time.UTC),
+		time.Date(2030, 6, 15, 0, 0, 0, 0, time.UTC),
+	}
+	quotes := map[string]float64{"1Y": 2.2, "2Y": 2.3, "5Y": 2.5}
+	disc := curve.BuildCurve(settlement, quotes, calendar.TARGET, 1)
+
+	notional := 1_000_000.0
+	cashflows := make([]bond.Cashflow, len(dates))
+	for i, d := range dates {
+		cashflows[i] = bond.Cashflow{Date: d, Coupon: 0.03 * notional}
+	}
+	cashflows[len(cashflows)-1].Principal = notional
+
+	// Price the bond at a known spread over a floating leg accruing on the bond's own dates.
+	const spreadBP = 50.0
+	pvRF, annuity := 0.0, 0.0
+	prev := settlement
+	for _, cf := range cashflows {
+		df := disc.DF(cf.Date)
+		pvRF += cf.Amount() * df
+		annuity += utils.YearFraction(prev, cf.Date, "ACT/360") * df
+		prev = cf.Date
+	}
+	dirty := pvRF - spreadBP*1e-4*notional*annuity
+
+	floatLeg := swaps.EURIBOR6MFloating
+	floatLeg.PayFrequency = market.FreqAnnual
+
+	compute := func(align bool) float64 {
+		t.Helper()
+		res, err := bond.ComputeASWSpread(bond.ASWInput{
+			SettlementDate:    settlement,
+			DirtyPrice:        dirty,
+			Notional:          notional,
+			Cashflows:         cashflows,
+			FloatLeg:          floatLeg,
+			DiscountCurve:     disc,
+			AlignSwapMaturity: align,
+		})
+		if err != nil {
+			t.Fatalf("ComputeASWSpread(align=%v): %v", align, err)
+		}
+		return res.SpreadBP
+	}
+
+	adjustedErr := math.Abs(compute(false) - spreadBP)
+	alignedErr := math.Abs(compute(true) - spreadBP)
+	if alignedErr > 1e-8 {
+		t.Fatalf("aligned ASW should recover %.1f bp exactly, error %.3g bp", spreadBP, alignedErr)
+	}
+	if adjustedErr <= alignedErr {
+		t.Fatalf("expected alignment to tighten agreement: adjusted error %.3g bp, aligned %.3g bp", adjustedErr, alignedErr)
+	}
+	t.Logf("ASW error vs %.1f bp: adjusted maturity %.4f bp, aligned %.2g bp", spreadBP, adjustedErr, alignedErr)
+}
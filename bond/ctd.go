@@ -0,0 +1,126 @@
+package bond
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// DeliverableBond is one bond in a futures delivery basket.
+type DeliverableBond struct {
+	// ID identifies the bond in the result table (e.g. an ISIN).
+	ID string
+	// Cashflows are the remaining cash flows after SettlementDate, in per-100 terms.
+	Cashflows []Cashflow
+	// CouponRate is the annual coupon in percent; CouponFrequency is coupons per year.
+	CouponRate      float64
+	CouponFrequency int
+	// ConversionFactor maps the futures price to this bond's invoice price.
+	ConversionFactor float64
+	// DirtyPrice is the bond's full price at SettlementDate (per-100).
+	DirtyPrice float64
+}
+
+// CTDInput holds a futures delivery basket and the market needed to rank it.
+type CTDInput struct {
+	// SettlementDate is the spot settlement date the bonds are bought for.
+	SettlementDate time.Time
+	// DeliveryDate is the futures delivery date.
+	DeliveryDate time.Time
+	// FuturesPrice is the clean futures price (e.g. 128.20).
+	FuturesPrice float64
+	// RepoRate is the financing rate to delivery in percent, ACT/360 simple. It enters the
+	// net basis only; implied repo does not depend on it.
+	RepoRate float64
+	Bonds    []DeliverableBond
+}
+
+// CTDRow is the basis analysis of one deliverable bond.
+type CTDRow struct {
+	// Index is the bond's position in CTDInput.Bonds.
+	Index int
+	ID    string
+	// InvoicePrice is futures × conversion factor + accrued at delivery, as in
+	// ComputeForwardYield.
+	InvoicePrice float64
+	// Coupons is the coupon income paid between settlement and delivery.
+	Coupons float64
+	// GrossBasis is the clean price at settlement minus futures × conversion factor.
+	GrossBasis float64
+	// NetBasis is the cost of buying and financing the bond at RepoRate to delivery,
+	// less coupons received and the invoice price. The CTD has the lowest net basis.
+	NetBasis float64
+	// ImpliedRepo (percent, ACT/360) is the financing rate at which the net basis is zero.
+	ImpliedRepo float64
+}
+
+// CTDResult ranks a delivery basket; Table is sorted by net basis, cheapest first.
+type CTDResult struct {
+	// CTDIndex is the index in CTDInput.Bonds of the cheapest-to-deliver bond.
+	CTDIndex int
+	Table    []CTDRow
+}
+
+// SelectCTD computes the net basis and implied repo of each deliverable bond and returns
+// the cheapest to deliver: the bond whose purchase at SettlementDate, financed to
+// DeliveryDate and delivered into the futures, costs the least.
+func SelectCTD(in CTDInput) (CTDResult, error) {
+	if in.SettlementDate.IsZero() || in.DeliveryDate.IsZero() {
+		return CTDResult{}, fmt.Errorf("SelectCTD: SettlementDate and DeliveryDate are required")
+	}
+	if !in.DeliveryDate.After(in.SettlementDate) {
+		return CTDResult{}, fmt.Errorf("SelectCTD: delivery %s must be after settlement %s",
+			in.DeliveryDate.Format("2006-01-02"), in.SettlementDate.Format("2006-01-02"))
+	}
+	if len(in.Bonds) == 0 {
+		return CTDResult{}, fmt.Errorf("SelectCTD: Bonds are required")
+	}
+
+	tau := float64(daysBetween(in.SettlementDate, in.DeliveryDate)) / 360.0
+	table := make([]CTDRow, 0, len(in.Bonds))
+	for i, b := range in.Bonds {
+		if b.CouponFrequency <= 0 {
+			return CTDResult{}, fmt.Errorf("SelectCTD: bond %d (%s): CouponFrequency must be positive", i, b.ID)
+		}
+		var coupons float64
+		var afterDelivery []Cashflow
+		for _, cf := range b.Cashflows {
+			switch {
+			case !cf.Date.After(in.SettlementDate):
+			case !cf.Date.After(in.DeliveryDate):
+				coupons += cf.Amount()
+			default:
+				afterDelivery = append(afterDelivery, cf)
+			}
+		}
+		if len(afterDelivery) == 0 {
+			return CTDResult{}, fmt.Errorf("SelectCTD: bond %d (%s) matures before delivery", i, b.ID)
+		}
+
+		invoice, _, _ := futuresInvoice(ForwardYieldInput{
+			SettlementDate:   in.DeliveryDate,
+			FuturesPrice:     in.FuturesPrice,
+			ConversionFactor: b.ConversionFactor,
+			CouponRate:       b.CouponRate,
+			CouponFrequency:  b.CouponFrequency,
+			Cashflows:        afterDelivery,
+		})
+		clean, err := DirtyToClean(b.DirtyPrice, in.SettlementDate, b.Cashflows, b.CouponRate, b.CouponFrequency, "ACT/ACT")
+		if err != nil {
+			return CTDResult{}, fmt.Errorf("SelectCTD: bond %d (%s): %w", i, b.ID, err)
+		}
+
+		table = append(table, CTDRow{
+			Index:        i,
+			ID:           b.ID,
+			InvoicePrice: invoice,
+			Coupons:      coupons,
+			GrossBasis:   clean - in.FuturesPrice*b.ConversionFactor,
+			NetBasis:     b.DirtyPrice*(1+in.RepoRate/100*tau) - coupons - invoice,
+			ImpliedRepo:  (invoice + coupons - b.DirtyPrice) / (b.DirtyPrice * tau) * 100,
+		})
+	}
+
+	sort.SliceStable(table, func(i, j int) bool { return table[i].NetBasis < table[j].NetBasis })
+	return CTDResult{CTDIndex: table[0].Index, Table: table}, nil
+}
@@ -0,0 +1,76 @@
+package bond_test
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/meenmo/molib/bond"
+)
+
+func TestSelectCTD_LowestNetBasis(t *testing.T) {
+	t.Parallel()
+
+	d := func(y int, m time.Month, day int) time.Time { return time.Date(y, m, day, 0, 0, 0, 0, time.UTC) }
+	annual := func(couponPct float64, first time.Time, n int) []bond.Cashflow {
+		cfs := make([]bond.Cashflow, n)
+		for i := range cfs {
+			cfs[i] = bond.Cashflow{Date: first.AddDate(i, 0, 0), Coupon: couponPct}
+		}
+		cfs[n-1].Principal = 100
+		return cfs
+	}
+
+	settlement, delivery := d(2026, 1, 12), d(2026, 3, 10)
+	in := bond.CTDInput{
+		SettlementDate: settlement,
+		DeliveryDate:   delivery,
+		FuturesPrice:   128.20,
+		RepoRate:       2.0,
+		Bonds: []bond.DeliverableBond{
+			// The second bond pays a coupon before delivery.
+			{ID: "A", Cashflows: annual(2.5, d(2026, 8, 15), 9), CouponRate: 2.5, CouponFrequency: 1, ConversionFactor: 0.7710, DirtyPrice: 100.40},
+			{ID: "B", Cashflows: annual(2.6, d(2026, 2, 15), 10), CouponRate: 2.6, CouponFrequency: 1, ConversionFactor: 0.7700, DirtyPrice: 101.10},
+			{ID: "C", Cashflows: annual(2.2, d(2026, 6, 15), 10), CouponRate: 2.2, CouponFrequency: 1, ConversionFactor: 0.7400, DirtyPrice: 96.00},
+		},
+	}
+
+	res, err := bond.SelectCTD(in)
+	if err != nil {
+		t.Fatalf("SelectCTD: %v", err)
+	}
+	if len(res.Table) != 3 {
+		t.Fatalf("table has %d rows, want 3", len(res.Table))
+	}
+	for i := 1; i < len(res.Table); i++ {
+		if res.Table[i].NetBasis < res.Table[i-1].NetBasis {
+			t.Fatalf("table not sorted by net basis: %+v", res.Table)
+		}
+		if res.Table[i].ImpliedRepo > res.Table[i-1].ImpliedRepo {
+			t.Errorf("implied repo should fall as net basis rises: %+v", res.Table)
+		}
+	}
+	best := res.Table[0]
+	if res.CTDIndex != best.Index {
+		t.Fatalf("CTDIndex %d, cheapest row is bond %d", res.CTDIndex, best.Index)
+	}
+	if best.ID != "C" {
+		t.Errorf("CTD %s, want C; table %+v", best.ID, res.Table)
+	}
+	if last := res.Table[2]; last.ID != "A" {
+		t.Errorf("most expensive %s, want A", last.ID)
+	}
+	if b := res.Table[1]; b.ID != "B" || b.Coupons != 2.6 {
+		t.Errorf("middle row %s with coupons %.2f, want B with its 2.6 coupon before delivery", b.ID, b.Coupons)
+	}
+
+	// At the CTD's implied repo its net basis is zero.
+	in.RepoRate = best.ImpliedRepo
+	res, err = bond.SelectCTD(in)
+	if err != nil {
+		t.Fatalf("SelectCTD: %v", err)
+	}
+	if nb := res.Table[0].NetBasis; math.Abs(nb) > 1e-10 {
+		t.Errorf("net basis at implied repo %.12f, want 0", nb)
+	}
+}
@@ -0,0 +1,110 @@
+package bond
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// DurationInput holds the parameters needed to compute a bond's duration and convexity.
+type DurationInput struct {
+	// SettlementDate is the date the bond is priced for.
+	SettlementDate time.Time
+	// Cashflows are the remaining cash flows after settlement, in date order. A short or
+	// long first coupon is measured against the regular period ending on its date.
+	Cashflows []Cashflow
+	// CouponFrequency is coupons per year (1 = annual, 2 = semi-annual); yields
+	// compound at this frequency.
+	CouponFrequency int
+	// Yield is the annualised yield in percent. If nil, it is solved from DirtyPrice.
+	Yield *float64
+	// DirtyPrice is the full price, in the same units as Cashflows. Used only when
+	// Yield is nil.
+	DirtyPrice float64
+}
+
+// DurationResult is the output of Duration.
+type DurationResult struct {
+	// Yield is the annualised yield in percent the measures were computed at.
+	Yield float64
+	// Price is the dirty price at Yield.
+	Price float64
+	// MacaulayDuration is the present-value-weighted time to the cash flows, in years.
+	MacaulayDuration float64
+	// ModifiedDuration is −(1/P)·dP/dy, in years.
+	ModifiedDuration float64
+	// Convexity is (1/P)·d²P/dy², in years squared.
+	Convexity float64
+}
+
+// Duration returns the Macaulay and modified durations and the convexity of a bond,
+// discounting as YieldToMaturity: the k-th cash flow is t_1 + (k − 1) coupon periods
+// away, with t_1 the ACT/ACT ICMA periods to the first cash flow.
+//
+// A zero-coupon bond (a single cash flow) is discounted the same way, so its Macaulay
+// duration is its time to maturity; a yield solved from its price is compounded at
+// CouponFrequency rather than quoted money-market as in YieldToMaturity.
+func Duration(in DurationInput) (DurationResult, error) {
+	if in.SettlementDate.IsZero() {
+		return DurationResult{}, fmt.Errorf("Duration: SettlementDate is required")
+	}
+	if len(in.Cashflows) == 0 {
+		return DurationResult{}, fmt.Errorf("Duration: Cashflows are required")
+	}
+	if in.CouponFrequency <= 0 {
+		return DurationResult{}, fmt.Errorf("Duration: CouponFrequency must be positive")
+	}
+	if !in.Cashflows[0].Date.After(in.SettlementDate) {
+		return DurationResult{}, fmt.Errorf("Duration: first cashflow %s is not after settlement %s",
+			in.Cashflows[0].Date.Format("2006-01-02"), in.SettlementDate.Format("2006-01-02"))
+	}
+
+	freq := float64(in.CouponFrequency)
+	t1 := firstPeriodFraction(in.SettlementDate, in.Cashflows, 12/in.CouponFrequency, "")
+
+	var y float64 // decimal
+	switch {
+	case in.Yield != nil:
+		y = *in.Yield / 100.0
+	case in.DirtyPrice <= 0:
+		return DurationResult{}, fmt.Errorf("Duration: Yield or a positive DirtyPrice is required")
+	case len(in.Cashflows) == 1:
+		y = (math.Pow(in.Cashflows[0].Amount()/in.DirtyPrice, 1/t1) - 1) * freq
+	default:
+		res, err := YieldToMaturity(YTMInput{
+			SettlementDate:  in.SettlementDate,
+			DirtyPrice:      in.DirtyPrice,
+			Cashflows:       in.Cashflows,
+			CouponFrequency: in.CouponFrequency,
+		})
+		if err != nil {
+			return DurationResult{}, fmt.Errorf("Duration: %w", err)
+		}
+		y = res.Yield / 100.0
+	}
+
+	r := y / freq
+	if r <= -1 {
+		return DurationResult{}, fmt.Errorf("Duration: yield %g%% is below -100%% per period", y*100)
+	}
+	var price, weighted, curvature float64
+	for i, cf := range in.Cashflows {
+		t := t1 + float64(i) // coupon periods
+		pv := cf.Amount() / math.Pow(1+r, t)
+		price += pv
+		weighted += t * pv
+		curvature += t * (t + 1) * pv
+	}
+	if price == 0 {
+		return DurationResult{}, fmt.Errorf("Duration: cash flows have zero present value")
+	}
+
+	macaulay := weighted / price / freq
+	return DurationResult{
+		Yield:            y * 100.0,
+		Price:            price,
+		MacaulayDuration: macaulay,
+		ModifiedDuration: macaulay / (1 + r),
+		Convexity:        curvature / price / (freq * freq * (1 + r) * (1 + r)),
+	}, nil
+}
@@ -0,0 +1,107 @@
+package bond_test
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/meenmo/molib/bond"
+)
+
+func TestDuration_TenYearAnnualPar(t *testing.T) {
+	t.Parallel()
+
+	// 10Y 3% annual bond settling on a coupon date, at a 3% yield (par).
+	settlement := time.Date(2026, 3, 15, 0, 0, 0, 0, time.UTC)
+	cfs := make([]bond.Cashflow, 10)
+	for i := range cfs {
+		cfs[i] = bond.Cashflow{Date: settlement.AddDate(i+1, 0, 0), Coupon: 3}
+	}
+	cfs[9].Principal = 100
+
+	y := 3.0
+	res, err := bond.Duration(bond.DurationInput{
+		SettlementDate:  settlement,
+		Cashflows:       cfs,
+		CouponFrequency: 1,
+		Yield:           &y,
+	})
+	if err != nil {
+		t.Fatalf("Duration: %v", err)
+	}
+
+	// At par, modified duration is the annuity factor (1 − (1+y)^−n) / y.
+	want := (1 - math.Pow(1.03, -10)) / 0.03
+	if math.Abs(res.ModifiedDuration-want) > 1e-4 {
+		t.Errorf("modified duration %.6f, want %.6f", res.ModifiedDuration, want)
+	}
+	if math.Abs(res.Price-100) > 1e-9 {
+		t.Errorf("price %.10f, want 100", res.Price)
+	}
+	if math.Abs(res.MacaulayDuration-res.ModifiedDuration*1.03) > 1e-12 {
+		t.Errorf("Macaulay %.6f != modified × (1+y) %.6f", res.MacaulayDuration, res.ModifiedDuration*1.03)
+	}
+
+	// Duration and convexity match central differences of the price.
+	const h = 1e-4 // in percent
+	price := func(yPct float64) float64 {
+		r, err := bond.Duration(bond.DurationInput{SettlementDate: settlement, Cashflows: cfs, CouponFrequency: 1, Yield: &yPct})
+		if err != nil {
+			t.Fatalf("Duration: %v", err)
+		}
+		return r.Price
+	}
+	up, down := price(y+h), price(y-h)
+	dy := h / 100
+	if fd := -(up - down) / (2 * dy) / res.Price; math.Abs(fd-res.ModifiedDuration) > 1e-6 {
+		t.Errorf("modified duration %.8f, finite difference %.8f", res.ModifiedDuration, fd)
+	}
+	if fd := (up - 2*res.Price + down) / (dy * dy) / res.Price; math.Abs(fd-res.Convexity) > 1e-3 {
+		t.Errorf("convexity %.6f, finite difference %.6f", res.Convexity, fd)
+	}
+}
+
+func TestDuration_ZeroCouponAndStubFromPrice(t *testing.T) {
+	t.Parallel()
+
+	settlement := time.Date(2026, 3, 12, 0, 0, 0, 0, time.UTC)
+
+	// A 5Y zero-coupon bond: Macaulay duration is its time to maturity.
+	zero := []bond.Cashflow{{Date: time.Date(2031, 3, 12, 0, 0, 0, 0, time.UTC), Principal: 100}}
+	res, err := bond.Duration(bond.DurationInput{
+		SettlementDate:  settlement,
+		Cashflows:       zero,
+		CouponFrequency: 1,
+		DirtyPrice:      100 / math.Pow(1.025, 5),
+	})
+	if err != nil {
+		t.Fatalf("zero coupon: %v", err)
+	}
+	if math.Abs(res.Yield-2.5) > 1e-9 || math.Abs(res.MacaulayDuration-5) > 1e-9 {
+		t.Errorf("zero coupon: yield %.10f%% Macaulay %.10f, want 2.5%% and 5", res.Yield, res.MacaulayDuration)
+	}
+
+	// A semi-annual bond with a short first coupon (2 of 6 months' accrual).
+	stub := []bond.Cashflow{{Date: time.Date(2026, 5, 15, 0, 0, 0, 0, time.UTC), Coupon: 4.0 / 2 / 3}}
+	for i := 1; i <= 6; i++ {
+		stub = append(stub, bond.Cashflow{Date: time.Date(2026, 5, 15, 0, 0, 0, 0, time.UTC).AddDate(0, 6*i, 0), Coupon: 2})
+	}
+	stub[len(stub)-1].Principal = 100
+
+	y := 3.7
+	atYield, err := bond.Duration(bond.DurationInput{SettlementDate: settlement, Cashflows: stub, CouponFrequency: 2, Yield: &y})
+	if err != nil {
+		t.Fatalf("stub at yield: %v", err)
+	}
+	fromPrice, err := bond.Duration(bond.DurationInput{SettlementDate: settlement, Cashflows: stub, CouponFrequency: 2, DirtyPrice: atYield.Price})
+	if err != nil {
+		t.Fatalf("stub from price: %v", err)
+	}
+	if math.Abs(fromPrice.Yield-y) > 1e-8 || math.Abs(fromPrice.ModifiedDuration-atYield.ModifiedDuration) > 1e-8 {
+		t.Errorf("stub: from price yield %.10f%% duration %.8f, at yield %.10f%% duration %.8f",
+			fromPrice.Yield, fromPrice.ModifiedDuration, y, atYield.ModifiedDuration)
+	}
+	if atYield.MacaulayDuration <= 0 || atYield.MacaulayDuration >= 3.2 {
+		t.Errorf("stub: Macaulay duration %.6f outside (0, 3.2)", atYield.MacaulayDuration)
+	}
+}
@@ -5,6 +5,8 @@ import (
 	"math"
 	"sort"
 	"sync"
+
+	"github.com/meenmo/molib/utils"
 )
 
 // normalizeCurvePoints sorts and validates curve points.
@@ -131,21 +133,13 @@ func (z *zeroCurve) discountAt(tenor float64) float64 {
 
 // parYieldAt linearly interpolates par yield at a given tenor.
 func parYieldAt(points []CurvePoint, tenor float64) float64 {
-	if tenor <= points[0].Tenor {
-		return points[0].ParYield
-	}
-	last := len(points) - 1
-	if tenor >= points[last].Tenor {
-		return points[last].ParYield
-	}
-	idx := sort.Search(len(points), func(i int) bool { return points[i].Tenor >= tenor })
-	if idx < len(points) && math.Abs(points[idx].Tenor-tenor) < 1e-12 {
-		return points[idx].ParYield
+	tenors := make([]float64, len(points))
+	yields := make([]float64, len(points))
+	for i, p := range points {
+		tenors[i] = p.Tenor
+		yields[i] = p.ParYield
 	}
-	left := points[idx-1]
-	right := points[idx]
-	weight := (tenor - left.Tenor) / (right.Tenor - left.Tenor)
-	return left.ParYield + weight*(right.ParYield-left.ParYield)
+	return utils.Interp1D(tenors, yields, tenor, true)
 }
 
 // waveShift computes the Bloomberg triangle wave shift at a given tenor.
@@ -7,6 +7,7 @@ import (
 
 	"github.com/meenmo/molib/bond/ktb"
 	"github.com/meenmo/molib/calendar"
+	"github.com/meenmo/molib/utils"
 )
 
 // OnTheRunBond describes an on-the-run KTB used to build the indicator curve
@@ -277,24 +278,13 @@ func interpFlat(pts []indicatorPoint, tenor float64) float64 {
 	if len(pts) == 0 {
 		return 0
 	}
-	if tenor <= pts[0].Tenor {
-		return pts[0].Yield
+	tenors := make([]float64, len(pts))
+	yields := make([]float64, len(pts))
+	for i, p := range pts {
+		tenors[i] = p.Tenor
+		yields[i] = p.Yield
 	}
-	last := len(pts) - 1
-	if tenor >= pts[last].Tenor {
-		return pts[last].Yield
-	}
-	for i := 0; i < last; i++ {
-		a, b := pts[i], pts[i+1]
-		if tenor >= a.Tenor && tenor <= b.Tenor {
-			if b.Tenor == a.Tenor {
-				return a.Yield
-			}
-			w := (tenor - a.Tenor) / (b.Tenor - a.Tenor)
-			return a.Yield + w*(b.Yield-a.Yield)
-		}
-	}
-	return pts[last].Yield
+	return utils.Interp1D(tenors, yields, tenor, true)
 }
 
 // yearsBetween returns (to - from) in years using a 365-day denominator,
@@ -52,17 +52,7 @@ func ComputeForwardYield(in ForwardYieldInput) (ForwardYieldResult, error) {
 		return ForwardYieldResult{}, fmt.Errorf("ComputeForwardYield: CouponFrequency must be positive")
 	}
 
-	// Derive previous coupon date: first cashflow minus one coupon period.
-	monthsPerPeriod := 12 / in.CouponFrequency
-	prevCoupon := in.Cashflows[0].Date.AddDate(0, -monthsPerPeriod, 0)
-
-	// Accrued interest: coupon × (days from last coupon to settlement) / (days in period).
-	daysAccrued := daysBetween(prevCoupon, in.SettlementDate)
-	daysPeriod := daysBetween(prevCoupon, in.Cashflows[0].Date)
-	accruedInterest := in.CouponRate * float64(daysAccrued) / float64(daysPeriod)
-
-	// Invoice price: futures × CF + AI.
-	invoicePrice := in.FuturesPrice*in.ConversionFactor + accruedInterest
+	invoicePrice, accruedInterest, prevCoupon := futuresInvoice(in)
 
 	// Newton-Raphson: find y s.t. dirtyPrice(y) = invoicePrice.
 	yield, iterations, err := solveYield(invoicePrice, in.SettlementDate, prevCoupon, in.Cashflows)
@@ -78,6 +68,23 @@ func ComputeForwardYield(in ForwardYieldInput) (ForwardYieldResult, error) {
 	}, nil
 }
 
+// futuresInvoice returns the invoice price (futures × conversion factor + accrued) and
+// the accrued interest at in.SettlementDate, per 100, with the previous coupon date the
+// accrual runs from.
+func futuresInvoice(in ForwardYieldInput) (invoice, accrued float64, prevCoupon time.Time) {
+	// Derive previous coupon date: first cashflow minus one coupon period.
+	monthsPerPeriod := 12 / in.CouponFrequency
+	prevCoupon = in.Cashflows[0].Date.AddDate(0, -monthsPerPeriod, 0)
+
+	// Accrued interest: coupon × (days from last coupon to settlement) / (days in period).
+	daysAccrued := daysBetween(prevCoupon, in.SettlementDate)
+	daysPeriod := daysBetween(prevCoupon, in.Cashflows[0].Date)
+	accrued = in.CouponRate * float64(daysAccrued) / float64(daysPeriod)
+
+	// Invoice price: futures × CF + AI.
+	return in.FuturesPrice*in.ConversionFactor + accrued, accrued, prevCoupon
+}
+
 // ---------------------------------------------------------------------------
 // Newton-Raphson solver (unexported)
 // ---------------------------------------------------------------------------
@@ -0,0 +1,162 @@
+package bond
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/meenmo/molib/utils"
+)
+
+// YTMInput holds the parameters needed to compute a bond's yield to maturity.
+type YTMInput struct {
+	// SettlementDate is the date the dirty price is paid.
+	SettlementDate time.Time
+	// DirtyPrice is the full price, in the same units as Cashflows (e.g. per-100).
+	DirtyPrice float64
+	// Cashflows are the remaining cash flows after settlement, in date order.
+	Cashflows []Cashflow
+	// CouponFrequency is coupons per year (1 = annual, 2 = semi-annual).
+	CouponFrequency int
+	// DayCount measures the fractional first period. Empty or "ACT/ACT" is ACT/ACT ICMA
+	// (days over days in the coupon period); otherwise it is the ratio of utils.YearFraction
+	// values, which also annualizes a money-market yield.
+	DayCount string
+}
+
+// YTMResult is the output of YieldToMaturity.
+type YTMResult struct {
+	// Yield is the annualised yield in percent, compounded CouponFrequency times a year.
+	Yield float64
+	// Iterations is the number of solver steps taken (0 for a money-market yield).
+	Iterations int
+	// Residual is the price at Yield minus DirtyPrice.
+	Residual float64
+}
+
+// YieldToMaturity solves for the yield y such that
+//
+//	DirtyPrice = Σ CF_k / (1 + y/f)^t_k,   t_k = t_1 + (k − 1)
+//
+// where f is CouponFrequency and t_1 is the number of coupon periods to the first cash
+// flow (the fraction of the current period left at settlement, plus whole periods for a
+// long first coupon). With f = 1 this is the discounting of ComputeForwardYield.
+//
+// A single remaining cash flow is quoted as a simple money-market yield instead:
+// y = (CF / DirtyPrice − 1) / τ with τ the DayCount year fraction to payment.
+//
+// The solver uses Newton-Raphson with analytic first derivative, falling back to
+// bisection when a step leaves the bracket, so deep-discount prices with yields well
+// beyond the ComputeForwardYield range still converge.
+func YieldToMaturity(in YTMInput) (YTMResult, error) {
+	if in.SettlementDate.IsZero() {
+		return YTMResult{}, fmt.Errorf("YieldToMaturity: SettlementDate is required")
+	}
+	if len(in.Cashflows) == 0 {
+		return YTMResult{}, fmt.Errorf("YieldToMaturity: Cashflows are required")
+	}
+	if in.CouponFrequency <= 0 {
+		return YTMResult{}, fmt.Errorf("YieldToMaturity: CouponFrequency must be positive")
+	}
+	if in.DirtyPrice <= 0 {
+		return YTMResult{}, fmt.Errorf("YieldToMaturity: DirtyPrice must be positive, got %g", in.DirtyPrice)
+	}
+	if !in.Cashflows[0].Date.After(in.SettlementDate) {
+		return YTMResult{}, fmt.Errorf("YieldToMaturity: first cashflow %s is not after settlement %s",
+			in.Cashflows[0].Date.Format("2006-01-02"), in.SettlementDate.Format("2006-01-02"))
+	}
+
+	if len(in.Cashflows) == 1 {
+		cf := in.Cashflows[0]
+		tau := utils.YearFraction(in.SettlementDate, cf.Date, in.DayCount)
+		return YTMResult{Yield: (cf.Amount()/in.DirtyPrice - 1) / tau * 100.0}, nil
+	}
+
+	freq := float64(in.CouponFrequency)
+	t1 := firstPeriodFraction(in.SettlementDate, in.Cashflows, 12/in.CouponFrequency, in.DayCount)
+	f := func(y float64) (float64, float64) {
+		price, deriv := periodPriceAndDeriv(y/freq, t1, in.Cashflows)
+		return price - in.DirtyPrice, deriv / freq
+	}
+
+	// Price falls in y; widen the upper bound until it brackets the target.
+	lo, hi := -0.5*freq, 1.0
+	for iter := 0; ; iter++ {
+		if v, _ := f(hi); v < 0 {
+			break
+		}
+		if iter == yieldMaxIter {
+			return YTMResult{}, fmt.Errorf("YieldToMaturity: no yield below %g%% reprices %g", hi*100, in.DirtyPrice)
+		}
+		lo, hi = hi, hi*2
+	}
+
+	y := clamp(0.025, lo, hi)
+	for iter := 1; iter <= yieldMaxIter; iter++ {
+		v, dv := f(y)
+		if math.Abs(v) < yieldTolerance {
+			return YTMResult{Yield: y * 100.0, Iterations: iter, Residual: v}, nil
+		}
+		if v > 0 {
+			lo = y
+		} else {
+			hi = y
+		}
+		next := y - v/dv
+		if dv == 0 || math.IsNaN(next) || next <= lo || next >= hi {
+			next = 0.5 * (lo + hi)
+		}
+		y = next
+	}
+	v, _ := f(y)
+	return YTMResult{}, fmt.Errorf("YieldToMaturity: did not converge after %d iterations (residual %g)", yieldMaxIter, v)
+}
+
+// firstPeriodFraction returns the number of coupon periods from settlement to the first
+// cash flow: whole regular periods back from it (see couponDateBefore), plus the fraction
+// of the period containing settlement, ACT/ACT ICMA unless another day count is given.
+func firstPeriodFraction(settlement time.Time, cfs []Cashflow, monthsPerPeriod int, dayCount string) float64 {
+	anchor := cfs[len(cfs)-1].Date
+	next := cfs[0].Date
+	prev := couponDateBefore(anchor, next, monthsPerPeriod)
+	whole := 0
+	for prev.After(settlement) {
+		whole++
+		next, prev = prev, couponDateBefore(anchor, prev, monthsPerPeriod)
+	}
+	if dayCount == "" || dayCount == "ACT/ACT" {
+		return float64(whole) + float64(daysBetween(settlement, next))/float64(daysBetween(prev, next))
+	}
+	return float64(whole) + utils.YearFraction(settlement, next, dayCount)/utils.YearFraction(prev, next, dayCount)
+}
+
+// couponDateBefore returns the latest regular coupon date strictly before d, stepping
+// whole periods of monthsPerPeriod back from anchor (the final cash flow). Every date is
+// stepped from anchor itself, so a month-end clamp (31 Aug → 28 Feb) does not carry into
+// earlier periods, and an anchor on the last day of its month keeps every date on a month
+// end (28 Feb → 31 Aug), the end-of-month rule.
+func couponDateBefore(anchor, d time.Time, monthsPerPeriod int) time.Time {
+	eom := anchor.AddDate(0, 0, 1).Day() == 1
+	for k := 0; ; k++ {
+		c := utils.AddMonth(anchor, -k*monthsPerPeriod)
+		if eom {
+			c = time.Date(c.Year(), c.Month()+1, 0, 0, 0, 0, 0, c.Location())
+		}
+		if c.Before(d) {
+			return c
+		}
+	}
+}
+
+// periodPriceAndDeriv returns (price, dPrice/dr) for a per-period yield r, with the k-th
+// cash flow t_1 + k periods away (k from 0).
+func periodPriceAndDeriv(r, t1 float64, cfs []Cashflow) (float64, float64) {
+	var price, deriv float64
+	for i, cf := range cfs {
+		t := t1 + float64(i)
+		amt := cf.Amount()
+		price += amt / math.Pow(1.0+r, t)
+		deriv += -t * amt / math.Pow(1.0+r, t+1)
+	}
+	return price, deriv
+}
@@ -0,0 +1,153 @@
+package bond_test
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/meenmo/molib/bond"
+)
+
+// semiAnnualBond returns per-100 cash flows for a bond paying couponPct a year in two
+// coupons, with the first coupon on first and the last on maturity.
+func semiAnnualBond(couponPct float64, first time.Time, n int) []bond.Cashflow {
+	cfs := make([]bond.Cashflow, n)
+	for i := range cfs {
+		cfs[i] = bond.Cashflow{Date: first.AddDate(0, 6*i, 0), Coupon: couponPct / 2}
+	}
+	cfs[n-1].Principal = 100
+	return cfs
+}
+
+// discountPrice prices cfs at an annual yield (decimal) compounded semi-annually, with
+// an ACT/ACT ICMA first period.
+func discountPrice(y float64, settlement time.Time, cfs []bond.Cashflow) float64 {
+	prev := cfs[0].Date.AddDate(0, -6, 0)
+	t1 := cfs[0].Date.Sub(settlement).Hours() / cfs[0].Date.Sub(prev).Hours()
+	price := 0.0
+	for i, cf := range cfs {
+		price += cf.Amount() / math.Pow(1+y/2, t1+float64(i))
+	}
+	return price
+}
+
+func TestYieldToMaturity_RoundTrip(t *testing.T) {
+	t.Parallel()
+
+	settlement := time.Date(2026, 3, 12, 0, 0, 0, 0, time.UTC)
+	cases := []struct {
+		name      string
+		couponPct float64
+		yield     float64
+	}{
+		{"par-ish", 4.0, 0.0425},
+		{"premium", 6.0, 0.0150},
+		{"negative yield", 0.5, -0.0030},
+		// A near-zero coupon bond priced around 20.
+		{"deep discount", 0.25, 0.18},
+	}
+	for _, tc := range cases {
+		cfs := semiAnnualBond(tc.couponPct, time.Date(2026, 8, 15, 0, 0, 0, 0, time.UTC), 20)
+		price := discountPrice(tc.yield, settlement, cfs)
+		res, err := bond.YieldToMaturity(bond.YTMInput{
+			SettlementDate:  settlement,
+			DirtyPrice:      price,
+			Cashflows:       cfs,
+			CouponFrequency: 2,
+		})
+		if err != nil {
+			t.Fatalf("%s: YieldToMaturity: %v", tc.name, err)
+		}
+		if math.Abs(res.Yield-tc.yield*100) > 1e-8 {
+			t.Errorf("%s: yield %.10f%%, want %.10f%% (price %.6f)", tc.name, res.Yield, tc.yield*100, price)
+		}
+		if math.Abs(res.Residual) > 1e-10 || res.Iterations == 0 {
+			t.Errorf("%s: residual %g after %d iterations", tc.name, res.Residual, res.Iterations)
+		}
+	}
+}
+
+func TestYieldToMaturity_MonthEndRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	// A 4% bond paying on the last day of February and August.
+	d := func(y int, m time.Month, day int) time.Time { return time.Date(y, m, day, 0, 0, 0, 0, time.UTC) }
+	cfs := []bond.Cashflow{
+		{Date: d(2026, 8, 31), Coupon: 2},
+		{Date: d(2027, 2, 28), Coupon: 2},
+		{Date: d(2027, 8, 31), Coupon: 2},
+		{Date: d(2028, 2, 29), Coupon: 2},
+		{Date: d(2028, 8, 31), Coupon: 2, Principal: 100},
+	}
+	settlement := d(2026, 3, 2)
+
+	// The current period is 28 Feb → 31 Aug 2026: 182 of its 184 days remain.
+	t1 := 182.0 / 184.0
+	price := 0.0
+	for i, cf := range cfs {
+		price += cf.Amount() / math.Pow(1.02, t1+float64(i))
+	}
+
+	res, err := bond.YieldToMaturity(bond.YTMInput{
+		SettlementDate:  settlement,
+		DirtyPrice:      price,
+		Cashflows:       cfs,
+		CouponFrequency: 2,
+	})
+	if err != nil {
+		t.Fatalf("YieldToMaturity: %v", err)
+	}
+	if math.Abs(res.Yield-4) > 1e-8 {
+		t.Errorf("yield %.10f%%, want 4%%", res.Yield)
+	}
+
+	y := 4.0
+	dur, err := bond.Duration(bond.DurationInput{SettlementDate: settlement, Cashflows: cfs, CouponFrequency: 2, Yield: &y})
+	if err != nil {
+		t.Fatalf("Duration: %v", err)
+	}
+	if math.Abs(dur.Price-price) > 1e-9 {
+		t.Errorf("Duration price %.10f at 4%%, want %.10f", dur.Price, price)
+	}
+}
+
+func TestYieldToMaturity_DeepDiscountBeyondForwardYieldCeiling(t *testing.T) {
+	t.Parallel()
+
+	settlement := time.Date(2026, 3, 12, 0, 0, 0, 0, time.UTC)
+	cfs := semiAnnualBond(2.0, time.Date(2026, 8, 15, 0, 0, 0, 0, time.UTC), 10)
+	price := discountPrice(0.80, settlement, cfs)
+	res, err := bond.YieldToMaturity(bond.YTMInput{
+		SettlementDate:  settlement,
+		DirtyPrice:      price,
+		Cashflows:       cfs,
+		CouponFrequency: 2,
+	})
+	if err != nil {
+		t.Fatalf("YieldToMaturity: %v", err)
+	}
+	if math.Abs(res.Yield-80) > 1e-8 {
+		t.Errorf("yield %.10f%%, want 80%% (price %.6f)", res.Yield, price)
+	}
+}
+
+func TestYieldToMaturity_SingleCashflowMoneyMarket(t *testing.T) {
+	t.Parallel()
+
+	settlement := time.Date(2026, 3, 12, 0, 0, 0, 0, time.UTC)
+	maturity := settlement.AddDate(0, 0, 90)
+	res, err := bond.YieldToMaturity(bond.YTMInput{
+		SettlementDate:  settlement,
+		DirtyPrice:      99.5,
+		Cashflows:       []bond.Cashflow{{Date: maturity, Coupon: 1, Principal: 100}},
+		CouponFrequency: 2,
+		DayCount:        "ACT/360",
+	})
+	if err != nil {
+		t.Fatalf("YieldToMaturity: %v", err)
+	}
+	want := (101/99.5 - 1) * 360 / 90 * 100
+	if math.Abs(res.Yield-want) > 1e-12 || res.Iterations != 0 {
+		t.Errorf("money-market yield %.10f%% (%d iterations), want %.10f%%", res.Yield, res.Iterations, want)
+	}
+}
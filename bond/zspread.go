@@ -0,0 +1,87 @@
+package bond
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/meenmo/molib/swap"
+	"github.com/meenmo/molib/utils"
+)
+
+// ZSpreadInput holds the parameters needed to compute a bond's Z-spread.
+type ZSpreadInput struct {
+	SettlementDate time.Time
+	// DirtyPrice is the full price, in the same units as Cashflows.
+	DirtyPrice float64
+	// Cashflows are the bond's cash flows; those on or before SettlementDate are ignored.
+	Cashflows []Cashflow
+	// DiscountCurve supplies the zero rates the spread is quoted over. It is assumed to
+	// settle on SettlementDate.
+	DiscountCurve swap.DiscountCurve
+}
+
+// ZSpread returns the constant spread s (in bp) over the curve's zero rates that reprices
+// the bond:
+//
+//	DirtyPrice = Σ CF_k · exp(−(z(t_k) + s) · τ_k)
+//
+// where z is the continuously compounded ZeroRateAt and τ_k is ACT/365F from settlement
+// to the cash flow, the curve package's time axis. A bond priced exactly off the curve
+// has a zero Z-spread.
+func ZSpread(in ZSpreadInput) (float64, error) {
+	if in.SettlementDate.IsZero() {
+		return 0, fmt.Errorf("ZSpread: SettlementDate is required")
+	}
+	if in.DiscountCurve == nil {
+		return 0, fmt.Errorf("ZSpread: DiscountCurve is required")
+	}
+	if in.DirtyPrice <= 0 {
+		return 0, fmt.Errorf("ZSpread: DirtyPrice must be positive, got %g", in.DirtyPrice)
+	}
+
+	type flow struct{ amount, tau, zero float64 }
+	var flows []flow
+	for _, cf := range in.Cashflows {
+		if !cf.Date.After(in.SettlementDate) {
+			continue
+		}
+		flows = append(flows, flow{
+			amount: cf.Amount(),
+			tau:    utils.YearFraction(in.SettlementDate, cf.Date, "ACT/365F"),
+			zero:   in.DiscountCurve.ZeroRateAt(cf.Date) / 100.0,
+		})
+	}
+	if len(flows) == 0 {
+		return 0, fmt.Errorf("ZSpread: no cashflows after settlement %s", in.SettlementDate.Format("2006-01-02"))
+	}
+
+	// Price is convex and decreasing in s, so Newton-Raphson converges from zero.
+	s := 0.0
+	for iter := 0; iter < yieldMaxIter; iter++ {
+		var price, deriv float64
+		for _, f := range flows {
+			pv := f.amount * math.Exp(-(f.zero+s)*f.tau)
+			price += pv
+			deriv -= f.tau * pv
+		}
+		diff := price - in.DirtyPrice
+		if math.Abs(diff) < yieldTolerance*in.DirtyPrice {
+			return s * 10000.0, nil
+		}
+		if deriv == 0 {
+			return 0, fmt.Errorf("ZSpread: zero price sensitivity at iter %d", iter)
+		}
+		s -= diff / deriv
+	}
+	return 0, fmt.Errorf("ZSpread: did not converge after %d iterations", yieldMaxIter)
+}
+
+// GSpread returns the bond yield (in percent, e.g. from YieldToMaturity) minus the curve's
+// zero rate interpolated at maturity, in bp.
+func GSpread(yieldPct float64, maturity time.Time, disc swap.DiscountCurve) (float64, error) {
+	if disc == nil {
+		return 0, fmt.Errorf("GSpread: DiscountCurve is required")
+	}
+	return (yieldPct - disc.ZeroRateAt(maturity)) * 100.0, nil
+}
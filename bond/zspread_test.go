@@ -0,0 +1,61 @@
+package bond_test
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/meenmo/molib/bond"
+	"github.com/meenmo/molib/calendar"
+	"github.com/meenmo/molib/swap/curve"
+)
+
+func TestZSpread_ZeroWhenPricedOffCurve(t *testing.T) {
+	t.Parallel()
+
+	settlement := time.Date(2026, 3, 12, 0, 0, 0, 0, time.UTC)
+	disc := curve.BuildCurve(settlement, map[string]float64{
+		"1Y": 2.06795, "2Y": 2.153975, "5Y": 2.3495, "10Y": 2.6955,
+	}, calendar.TARGET, 1)
+
+	cfs := make([]bond.Cashflow, 7)
+	for i := range cfs {
+		cfs[i] = bond.Cashflow{Date: time.Date(2026+i, 11, 15, 0, 0, 0, 0, time.UTC), Coupon: 3}
+	}
+	cfs[6].Principal = 100
+	price := 0.0
+	for _, cf := range cfs {
+		price += cf.Amount() * disc.DF(cf.Date)
+	}
+
+	z, err := bond.ZSpread(bond.ZSpreadInput{SettlementDate: settlement, DirtyPrice: price, Cashflows: cfs, DiscountCurve: disc})
+	if err != nil {
+		t.Fatalf("ZSpread: %v", err)
+	}
+	if math.Abs(z) > 1e-6 {
+		t.Errorf("Z-spread %.10f bp, want 0 for a bond priced off the curve", z)
+	}
+
+	// Shifting every discount factor by exp(−25bp·τ) gives a 25bp Z-spread.
+	shifted := 0.0
+	for _, cf := range cfs {
+		tau := cf.Date.Sub(settlement).Hours() / 24 / 365
+		shifted += cf.Amount() * disc.DF(cf.Date) * math.Exp(-0.0025*tau)
+	}
+	z, err = bond.ZSpread(bond.ZSpreadInput{SettlementDate: settlement, DirtyPrice: shifted, Cashflows: cfs, DiscountCurve: disc})
+	if err != nil {
+		t.Fatalf("ZSpread: %v", err)
+	}
+	if math.Abs(z-25) > 1e-6 {
+		t.Errorf("Z-spread %.10f bp, want 25", z)
+	}
+
+	maturity := cfs[6].Date
+	g, err := bond.GSpread(disc.ZeroRateAt(maturity)+0.4, maturity, disc)
+	if err != nil {
+		t.Fatalf("GSpread: %v", err)
+	}
+	if math.Abs(g-40) > 1e-9 {
+		t.Errorf("G-spread %.10f bp, want 40", g)
+	}
+}
@@ -11,8 +11,10 @@ const (
 	FD     CalendarID = "FD" // Federal Reserve (Fedwire-style) calendar
 	GT     CalendarID = "GT" // US Government bond calendar
 	KR     CalendarID = "KOR"
-	EN     CalendarID = "EN"
+	EN     CalendarID = "EN" // England & Wales bank holidays (London, SONIA)
+	UK     CalendarID = EN   // alias of EN
 	HK     CalendarID = "HK"
+	CA     CalendarID = "CA" // Canada (Toronto) settlement calendar
 )
 
 // buildHolidayMap creates a holiday lookup map from a list of date strings.
@@ -32,11 +34,15 @@ var jpHolidays = map[string]struct{}{}
 var fdHolidays = map[string]struct{}{}
 var gtHolidays = map[string]struct{}{}
 var enHolidays = map[string]struct{}{}
+var caHolidays = map[string]struct{}{}
 var krHolidays = buildHolidayMap(krHolidayList)
 var hkHolidays map[string]struct{} // populated in hongkong.go init()
 
 func isHoliday(cal CalendarID, t time.Time) bool {
 	key := t.Format("2006-01-02")
+	if isRegisteredHoliday(cal, key) || joinedHoliday(cal, t) {
+		return true
+	}
 	switch cal {
 	case TARGET:
 		_, ok := targetHolidays[key]
@@ -59,6 +65,9 @@ func isHoliday(cal CalendarID, t time.Time) bool {
 	case HK:
 		_, ok := hkHolidays[key]
 		return ok
+	case CA:
+		_, ok := caHolidays[key]
+		return ok
 	default:
 		return false
 	}
@@ -95,6 +104,24 @@ func AdjustFollowing(cal CalendarID, t time.Time) time.Time {
 	return t
 }
 
+// AdjustPreceding applies a simple Preceding convention (no month preservation).
+func AdjustPreceding(cal CalendarID, t time.Time) time.Time {
+	for !IsBusinessDay(cal, t) {
+		t = t.AddDate(0, 0, -1)
+	}
+	return t
+}
+
+// AdjustModifiedPreceding applies Modified Preceding: roll back to the previous business
+// day unless that crosses into the previous month, in which case roll forward.
+func AdjustModifiedPreceding(cal CalendarID, t time.Time) time.Time {
+	adjusted := AdjustPreceding(cal, t)
+	if adjusted.Month() != t.Month() {
+		return AdjustFollowing(cal, t)
+	}
+	return adjusted
+}
+
 // AddBusinessDays advances n business days (n can be negative).
 func AddBusinessDays(cal CalendarID, t time.Time, n int) time.Time {
 	step := 1
@@ -110,6 +137,23 @@ func AddBusinessDays(cal CalendarID, t time.Time, n int) time.Time {
 	return t
 }
 
+// BusinessDaysBetween counts the business days in (start, end], so that
+// BusinessDaysBetween(cal, t, AddBusinessDays(cal, t, n)) == n. It is negative when end is
+// before start.
+func BusinessDaysBetween(cal CalendarID, start, end time.Time) int {
+	sign := 1
+	if end.Before(start) {
+		start, end, sign = end, start, -1
+	}
+	n := 0
+	for d := start.AddDate(0, 0, 1); !d.After(end); d = d.AddDate(0, 0, 1) {
+		if IsBusinessDay(cal, d) {
+			n++
+		}
+	}
+	return sign * n
+}
+
 // AddYearsWithRoll adds years and applies backward EOM adjustment then Modified Following.
 func AddYearsWithRoll(cal CalendarID, t time.Time, years int) time.Time {
 	target := t.AddDate(years, 0, 0)
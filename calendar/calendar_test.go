@@ -0,0 +1,193 @@
+package calendar_test
+
+import (
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/meenmo/molib/calendar"
+)
+
+func date(y int, m time.Month, d int) time.Time {
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+func TestUKCalendar_BankHolidays(t *testing.T) {
+	t.Parallel()
+
+	holidays := []time.Time{
+		date(2024, 1, 1), date(2024, 3, 29), date(2024, 4, 1), date(2024, 5, 6),
+		date(2024, 5, 27), date(2024, 8, 26), date(2024, 12, 25), date(2024, 12, 26),
+		date(2025, 1, 1), date(2025, 4, 18), date(2025, 4, 21), date(2025, 5, 5),
+		date(2025, 5, 26), date(2025, 8, 25), date(2025, 12, 25), date(2025, 12, 26),
+		date(2026, 1, 1), date(2026, 4, 3), date(2026, 4, 6), date(2026, 5, 4),
+		date(2026, 5, 25), date(2026, 8, 31), date(2026, 12, 25),
+		// Boxing Day 2026 is a Saturday: substitute Monday 28 Dec.
+		date(2026, 12, 28),
+		// Christmas and Boxing Day 2027 fall on the weekend: substitutes 27 and 28 Dec.
+		date(2027, 12, 27), date(2027, 12, 28),
+		// New Year's Day 2028 is a Saturday: substitute Monday 3 Jan.
+		date(2028, 1, 3),
+	}
+	for _, d := range holidays {
+		if calendar.IsBusinessDay(calendar.UK, d) {
+			t.Errorf("%s should be a UK bank holiday", d.Format("2006-01-02"))
+		}
+	}
+
+	if !calendar.IsBusinessDay(calendar.UK, date(2026, 12, 24)) {
+		t.Errorf("2026-12-24 should be a UK business day")
+	}
+}
+
+func TestUKCalendar_Adjustments(t *testing.T) {
+	t.Parallel()
+
+	// Sun 26 Dec 2027 rolls past the 27/28 Dec substitutes.
+	if got, want := calendar.AdjustFollowing(calendar.UK, date(2027, 12, 26)), date(2027, 12, 29); !got.Equal(want) {
+		t.Errorf("AdjustFollowing: got %s want %s", got.Format("2006-01-02"), want.Format("2006-01-02"))
+	}
+	// Modified Following from Sat 29 May 2027 would cross Mon 31 May (Spring bank holiday)
+	// into June, so it rolls back to Fri 28 May.
+	if got, want := calendar.Adjust(calendar.UK, date(2027, 5, 29)), date(2027, 5, 28); !got.Equal(want) {
+		t.Errorf("Adjust: got %s want %s", got.Format("2006-01-02"), want.Format("2006-01-02"))
+	}
+	// Two business days after Thu 24 Dec 2026 skip Christmas and the Boxing Day substitute.
+	if got, want := calendar.AddBusinessDays(calendar.UK, date(2026, 12, 24), 2), date(2026, 12, 30); !got.Equal(want) {
+		t.Errorf("AddBusinessDays: got %s want %s", got.Format("2006-01-02"), want.Format("2006-01-02"))
+	}
+}
+
+func TestAdjustPrecedingConventions(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name string
+		got  time.Time
+		want time.Time
+	}{
+		// Sat 31 Jan 2026: the previous business day stays in January.
+		{"Preceding month end", calendar.AdjustPreceding(calendar.TARGET, date(2026, 1, 31)), date(2026, 1, 30)},
+		{"ModifiedPreceding month end", calendar.AdjustModifiedPreceding(calendar.TARGET, date(2026, 1, 31)), date(2026, 1, 30)},
+		// Sat 1 Aug 2026: rolling back lands on Fri 31 Jul, so Modified Preceding rolls forward.
+		{"Preceding month start", calendar.AdjustPreceding(calendar.TARGET, date(2026, 8, 1)), date(2026, 7, 31)},
+		{"ModifiedPreceding month start", calendar.AdjustModifiedPreceding(calendar.TARGET, date(2026, 8, 1)), date(2026, 8, 3)},
+		// Business days are left unchanged.
+		{"ModifiedPreceding business day", calendar.AdjustModifiedPreceding(calendar.TARGET, date(2026, 7, 31)), date(2026, 7, 31)},
+	}
+	for _, tc := range cases {
+		if !tc.got.Equal(tc.want) {
+			t.Errorf("%s: got %s want %s", tc.name, tc.got.Format("2006-01-02"), tc.want.Format("2006-01-02"))
+		}
+	}
+}
+
+func TestIMMDates(t *testing.T) {
+	t.Parallel()
+
+	for _, want := range []time.Time{
+		date(2025, 3, 19), date(2025, 6, 18), date(2025, 9, 17), date(2025, 12, 17),
+		date(2026, 3, 18), date(2027, 12, 15),
+	} {
+		if got := calendar.IMMDate(want.Year(), want.Month()); !got.Equal(want) {
+			t.Errorf("IMMDate(%d, %s) = %s, want %s", want.Year(), want.Month(), got.Format("2006-01-02"), want.Format("2006-01-02"))
+		}
+	}
+
+	// From an IMM date itself the next one is a quarter later; the sequence crosses year end.
+	got := calendar.NextIMMDates(date(2025, 6, 18), 4)
+	want := []time.Time{date(2025, 9, 17), date(2025, 12, 17), date(2026, 3, 18), date(2026, 6, 17)}
+	if len(got) != len(want) {
+		t.Fatalf("NextIMMDates: got %d dates, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if !got[i].Equal(want[i]) {
+			t.Errorf("NextIMMDates[%d] = %s, want %s", i, got[i].Format("2006-01-02"), want[i].Format("2006-01-02"))
+		}
+	}
+	if first := calendar.NextIMMDates(date(2025, 12, 20), 1)[0]; !first.Equal(date(2026, 3, 18)) {
+		t.Errorf("NextIMMDates after Dec IMM = %s, want 2026-03-18", first.Format("2006-01-02"))
+	}
+
+	// The March 2024 IMM date is the Vernal Equinox Day holiday in Japan.
+	adj := calendar.NextIMMDatesAdjusted(calendar.JP, date(2023, 12, 20), 2)
+	if !adj[0].Equal(date(2024, 3, 21)) || !adj[1].Equal(date(2024, 6, 19)) {
+		t.Errorf("NextIMMDatesAdjusted = %s, %s, want 2024-03-21, 2024-06-19", adj[0].Format("2006-01-02"), adj[1].Format("2006-01-02"))
+	}
+}
+
+func TestRegisterHolidays_AddBusinessDaysSkipsThem(t *testing.T) {
+	t.Parallel()
+
+	// Far enough out that no other test sees the extra TARGET holiday.
+	mon := date(2099, 6, 15)
+	if got := calendar.AddBusinessDays(calendar.TARGET, mon, 1); !got.Equal(date(2099, 6, 16)) {
+		t.Fatalf("AddBusinessDays before registering = %s", got.Format("2006-01-02"))
+	}
+	calendar.RegisterHolidays(calendar.TARGET, []time.Time{date(2099, 6, 16)})
+	if got := calendar.AddBusinessDays(calendar.TARGET, mon, 1); !got.Equal(date(2099, 6, 17)) {
+		t.Fatalf("AddBusinessDays after registering = %s, want 2099-06-17", got.Format("2006-01-02"))
+	}
+	// Built-in holidays still apply.
+	if calendar.IsBusinessDay(calendar.TARGET, date(2026, 12, 25)) {
+		t.Fatalf("built-in TARGET holiday lost after registering")
+	}
+
+	// A calendar of its own, loaded from JSON while other goroutines read and register.
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			calendar.RegisterHolidays("TEST-CONCURRENT", []time.Time{date(2099, 1, 5+i)})
+			calendar.IsBusinessDay("TEST-JSON", date(2099, 3, 2))
+		}(i)
+	}
+	if err := calendar.LoadHolidaysJSON("TEST-JSON", strings.NewReader(`["2099-03-02", "2099-03-03"]`)); err != nil {
+		t.Fatalf("LoadHolidaysJSON: %v", err)
+	}
+	wg.Wait()
+	if got := calendar.Adjust("TEST-JSON", date(2099, 3, 2)); !got.Equal(date(2099, 3, 4)) {
+		t.Fatalf("Adjust on loaded calendar = %s, want 2099-03-04", got.Format("2006-01-02"))
+	}
+	for i := 0; i < 8; i++ {
+		if calendar.IsBusinessDay("TEST-CONCURRENT", date(2099, 1, 5+i)) {
+			t.Fatalf("concurrently registered holiday 2099-01-%02d lost", 5+i)
+		}
+	}
+	if err := calendar.LoadHolidaysJSON("TEST-BAD", strings.NewReader(`["2099-13-01"]`)); err == nil {
+		t.Fatalf("expected an error for an invalid date")
+	}
+}
+
+func TestJoin_SkipsEveryMembersHolidays(t *testing.T) {
+	t.Parallel()
+
+	joined := calendar.Join(calendar.TARGET, calendar.FD)
+	if joined != "TARGET+FD" {
+		t.Fatalf("Join id = %q", joined)
+	}
+
+	// Independence Day (observed Friday 2026-07-03) is a US-only holiday.
+	if !calendar.IsBusinessDay(calendar.TARGET, date(2026, 7, 3)) {
+		t.Fatalf("2026-07-03 should be a TARGET business day")
+	}
+	if got := calendar.AddBusinessDays(joined, date(2026, 7, 2), 1); !got.Equal(date(2026, 7, 6)) {
+		t.Errorf("AddBusinessDays over the US holiday = %s, want 2026-07-06", got.Format("2006-01-02"))
+	}
+	// Labour Day (Friday 2026-05-01) is a TARGET-only holiday.
+	if !calendar.IsBusinessDay(calendar.FD, date(2026, 5, 1)) {
+		t.Fatalf("2026-05-01 should be an FD business day")
+	}
+	if got := calendar.Adjust(joined, date(2026, 5, 1)); !got.Equal(date(2026, 5, 4)) {
+		t.Errorf("Adjust on the TARGET holiday = %s, want 2026-05-04", got.Format("2006-01-02"))
+	}
+	if got := calendar.AddBusinessDays(joined, date(2026, 4, 30), 1); !got.Equal(date(2026, 5, 4)) {
+		t.Errorf("AddBusinessDays over the TARGET holiday = %s, want 2026-05-04", got.Format("2006-01-02"))
+	}
+
+	if calendar.Join(calendar.JP) != calendar.JP {
+		t.Errorf("Join of one calendar should return it")
+	}
+}
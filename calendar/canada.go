@@ -0,0 +1,72 @@
+package calendar
+
+// caHolidayList contains Canada (Toronto) settlement holidays (weekdays only) from 2020 to 2080.
+// Weekend holidays are observed on the following Monday; the National Day for Truth and
+// Reconciliation (September 30) is included from 2021.
+var caHolidayList = []string{
+	"2020-01-01", "2020-02-17", "2020-04-10", "2020-05-18", "2020-07-01", "2020-08-03", "2020-09-07", "2020-10-12", "2020-11-11", "2020-12-25", "2020-12-28",
+	"2021-01-01", "2021-02-15", "2021-04-02", "2021-05-24", "2021-07-01", "2021-08-02", "2021-09-06", "2021-09-30", "2021-10-11", "2021-11-11", "2021-12-27", "2021-12-28",
+	"2022-01-03", "2022-02-21", "2022-04-15", "2022-05-23", "2022-07-01", "2022-08-01", "2022-09-05", "2022-09-30", "2022-10-10", "2022-11-11", "2022-12-26", "2022-12-27",
+	"2023-01-02", "2023-02-20", "2023-04-07", "2023-05-22", "2023-07-03", "2023-08-07", "2023-09-04", "2023-10-02", "2023-10-09", "2023-11-13", "2023-12-25", "2023-12-26",
+	"2024-01-01", "2024-02-19", "2024-03-29", "2024-05-20", "2024-07-01", "2024-08-05", "2024-09-02", "2024-09-30", "2024-10-14", "2024-11-11", "2024-12-25", "2024-12-26",
+	"2025-01-01", "2025-02-17", "2025-04-18", "2025-05-19", "2025-07-01", "2025-08-04", "2025-09-01", "2025-09-30", "2025-10-13", "2025-11-11", "2025-12-25", "2025-12-26",
+	"2026-01-01", "2026-02-16", "2026-04-03", "2026-05-18", "2026-07-01", "2026-08-03", "2026-09-07", "2026-09-30", "2026-10-12", "2026-11-11", "2026-12-25", "2026-12-28",
+	"2027-01-01", "2027-02-15", "2027-03-26", "2027-05-24", "2027-07-01", "2027-08-02", "2027-09-06", "2027-09-30", "2027-10-11", "2027-11-11", "2027-12-27", "2027-12-28",
+	"2028-01-03", "2028-02-21", "2028-04-14", "2028-05-22", "2028-07-03", "2028-08-07", "2028-09-04", "2028-10-02", "2028-10-09", "2028-11-13", "2028-12-25", "2028-12-26",
+	"2029-01-01", "2029-02-19", "2029-03-30", "2029-05-21", "2029-07-02", "2029-08-06", "2029-09-03", "2029-10-01", "2029-10-08", "2029-11-12", "2029-12-25", "2029-12-26",
+	"2030-01-01", "2030-02-18", "2030-04-19", "2030-05-20", "2030-07-01", "2030-08-05", "2030-09-02", "2030-09-30", "2030-10-14", "2030-11-11", "2030-12-25", "2030-12-26",
+	"2031-01-01", "2031-02-17", "2031-04-11", "2031-05-19", "2031-07-01", "2031-08-04", "2031-09-01", "2031-09-30", "2031-10-13", "2031-11-11", "2031-12-25", "2031-12-26",
+	"2032-01-01", "2032-02-16", "2032-03-26", "2032-05-24", "2032-07-01", "2032-08-02", "2032-09-06", "2032-09-30", "2032-10-11", "2032-11-11", "2032-12-27", "2032-12-28",
+	"2033-01-03", "2033-02-21", "2033-04-15", "2033-05-23", "2033-07-01", "2033-08-01", "2033-09-05", "2033-09-30", "2033-10-10", "2033-11-11", "2033-12-26", "2033-12-27",
+	"2034-01-02", "2034-02-20", "2034-04-07", "2034-05-22", "2034-07-03", "2034-08-07", "2034-09-04", "2034-10-02", "2034-10-09", "2034-11-13", "2034-12-25", "2034-12-26",
+	"2035-01-01", "2035-02-19", "2035-03-23", "2035-05-21", "2035-07-02", "2035-08-06", "2035-09-03", "2035-10-01", "2035-10-08", "2035-11-12", "2035-12-25", "2035-12-26",
+	"2036-01-01", "2036-02-18", "2036-04-11", "2036-05-19", "2036-07-01", "2036-08-04", "2036-09-01", "2036-09-30", "2036-10-13", "2036-11-11", "2036-12-25", "2036-12-26",
+	"2037-01-01", "2037-02-16", "2037-04-03", "2037-05-18", "2037-07-01", "2037-08-03", "2037-09-07", "2037-09-30", "2037-10-12", "2037-11-11", "2037-12-25", "2037-12-28",
+	"2038-01-01", "2038-02-15", "2038-04-23", "2038-05-24", "2038-07-01", "2038-08-02", "2038-09-06", "2038-09-30", "2038-10-11", "2038-11-11", "2038-12-27", "2038-12-28",
+	"2039-01-03", "2039-02-21", "2039-04-08", "2039-05-23", "2039-07-01", "2039-08-01", "2039-09-05", "2039-09-30", "2039-10-10", "2039-11-11", "2039-12-26", "2039-12-27",
+	"2040-01-02", "2040-02-20", "2040-03-30", "2040-05-21", "2040-07-02", "2040-08-06", "2040-09-03", "2040-10-01", "2040-10-08", "2040-11-12", "2040-12-25", "2040-12-26",
+	"2041-01-01", "2041-02-18", "2041-04-19", "2041-05-20", "2041-07-01", "2041-08-05", "2041-09-02", "2041-09-30", "2041-10-14", "2041-11-11", "2041-12-25", "2041-12-26",
+	"2042-01-01", "2042-02-17", "2042-04-04", "2042-05-19", "2042-07-01", "2042-08-04", "2042-09-01", "2042-09-30", "2042-10-13", "2042-11-11", "2042-12-25", "2042-12-26",
+	"2043-01-01", "2043-02-16", "2043-03-27", "2043-05-18", "2043-07-01", "2043-08-03", "2043-09-07", "2043-09-30", "2043-10-12", "2043-11-11", "2043-12-25", "2043-12-28",
+	"2044-01-01", "2044-02-15", "2044-04-15", "2044-05-23", "2044-07-01", "2044-08-01", "2044-09-05", "2044-09-30", "2044-10-10", "2044-11-11", "2044-12-26", "2044-12-27",
+	"2045-01-02", "2045-02-20", "2045-04-07", "2045-05-22", "2045-07-03", "2045-08-07", "2045-09-04", "2045-10-02", "2045-10-09", "2045-11-13", "2045-12-25", "2045-12-26",
+	"2046-01-01", "2046-02-19", "2046-03-23", "2046-05-21", "2046-07-02", "2046-08-06", "2046-09-03", "2046-10-01", "2046-10-08", "2046-11-12", "2046-12-25", "2046-12-26",
+	"2047-01-01", "2047-02-18", "2047-04-12", "2047-05-20", "2047-07-01", "2047-08-05", "2047-09-02", "2047-09-30", "2047-10-14", "2047-11-11", "2047-12-25", "2047-12-26",
+	"2048-01-01", "2048-02-17", "2048-04-03", "2048-05-18", "2048-07-01", "2048-08-03", "2048-09-07", "2048-09-30", "2048-10-12", "2048-11-11", "2048-12-25", "2048-12-28",
+	"2049-01-01", "2049-02-15", "2049-04-16", "2049-05-24", "2049-07-01", "2049-08-02", "2049-09-06", "2049-09-30", "2049-10-11", "2049-11-11", "2049-12-27", "2049-12-28",
+	"2050-01-03", "2050-02-21", "2050-04-08", "2050-05-23", "2050-07-01", "2050-08-01", "2050-09-05", "2050-09-30", "2050-10-10", "2050-11-11", "2050-12-26", "2050-12-27",
+	"2051-01-02", "2051-02-20", "2051-03-31", "2051-05-22", "2051-07-03", "2051-08-07", "2051-09-04", "2051-10-02", "2051-10-09", "2051-11-13", "2051-12-25", "2051-12-26",
+	"2052-01-01", "2052-02-19", "2052-04-19", "2052-05-20", "2052-07-01", "2052-08-05", "2052-09-02", "2052-09-30", "2052-10-14", "2052-11-11", "2052-12-25", "2052-12-26",
+	"2053-01-01", "2053-02-17", "2053-04-04", "2053-05-19", "2053-07-01", "2053-08-04", "2053-09-01", "2053-09-30", "2053-10-13", "2053-11-11", "2053-12-25", "2053-12-26",
+	"2054-01-01", "2054-02-16", "2054-03-27", "2054-05-18", "2054-07-01", "2054-08-03", "2054-09-07", "2054-09-30", "2054-10-12", "2054-11-11", "2054-12-25", "2054-12-28",
+	"2055-01-01", "2055-02-15", "2055-04-16", "2055-05-24", "2055-07-01", "2055-08-02", "2055-09-06", "2055-09-30", "2055-10-11", "2055-11-11", "2055-12-27", "2055-12-28",
+	"2056-01-03", "2056-02-21", "2056-03-31", "2056-05-22", "2056-07-03", "2056-08-07", "2056-09-04", "2056-10-02", "2056-10-09", "2056-11-13", "2056-12-25", "2056-12-26",
+	"2057-01-01", "2057-02-19", "2057-04-20", "2057-05-21", "2057-07-02", "2057-08-06", "2057-09-03", "2057-10-01", "2057-10-08", "2057-11-12", "2057-12-25", "2057-12-26",
+	"2058-01-01", "2058-02-18", "2058-04-12", "2058-05-20", "2058-07-01", "2058-08-05", "2058-09-02", "2058-09-30", "2058-10-14", "2058-11-11", "2058-12-25", "2058-12-26",
+	"2059-01-01", "2059-02-17", "2059-03-28", "2059-05-19", "2059-07-01", "2059-08-04", "2059-09-01", "2059-09-30", "2059-10-13", "2059-11-11", "2059-12-25", "2059-12-26",
+	"2060-01-01", "2060-02-16", "2060-04-16", "2060-05-24", "2060-07-01", "2060-08-02", "2060-09-06", "2060-09-30", "2060-10-11", "2060-11-11", "2060-12-27", "2060-12-28",
+	"2061-01-03", "2061-02-21", "2061-04-08", "2061-05-23", "2061-07-01", "2061-08-01", "2061-09-05", "2061-09-30", "2061-10-10", "2061-11-11", "2061-12-26", "2061-12-27",
+	"2062-01-02", "2062-02-20", "2062-03-24", "2062-05-22", "2062-07-03", "2062-08-07", "2062-09-04", "2062-10-02", "2062-10-09", "2062-11-13", "2062-12-25", "2062-12-26",
+	"2063-01-01", "2063-02-19", "2063-04-13", "2063-05-21", "2063-07-02", "2063-08-06", "2063-09-03", "2063-10-01", "2063-10-08", "2063-11-12", "2063-12-25", "2063-12-26",
+	"2064-01-01", "2064-02-18", "2064-04-04", "2064-05-19", "2064-07-01", "2064-08-04", "2064-09-01", "2064-09-30", "2064-10-13", "2064-11-11", "2064-12-25", "2064-12-26",
+	"2065-01-01", "2065-02-16", "2065-03-27", "2065-05-18", "2065-07-01", "2065-08-03", "2065-09-07", "2065-09-30", "2065-10-12", "2065-11-11", "2065-12-25", "2065-12-28",
+	"2066-01-01", "2066-02-15", "2066-04-09", "2066-05-24", "2066-07-01", "2066-08-02", "2066-09-06", "2066-09-30", "2066-10-11", "2066-11-11", "2066-12-27", "2066-12-28",
+	"2067-01-03", "2067-02-21", "2067-04-01", "2067-05-23", "2067-07-01", "2067-08-01", "2067-09-05", "2067-09-30", "2067-10-10", "2067-11-11", "2067-12-26", "2067-12-27",
+	"2068-01-02", "2068-02-20", "2068-04-20", "2068-05-21", "2068-07-02", "2068-08-06", "2068-09-03", "2068-10-01", "2068-10-08", "2068-11-12", "2068-12-25", "2068-12-26",
+	"2069-01-01", "2069-02-18", "2069-04-12", "2069-05-20", "2069-07-01", "2069-08-05", "2069-09-02", "2069-09-30", "2069-10-14", "2069-11-11", "2069-12-25", "2069-12-26",
+	"2070-01-01", "2070-02-17", "2070-03-28", "2070-05-19", "2070-07-01", "2070-08-04", "2070-09-01", "2070-09-30", "2070-10-13", "2070-11-11", "2070-12-25", "2070-12-26",
+	"2071-01-01", "2071-02-16", "2071-04-17", "2071-05-18", "2071-07-01", "2071-08-03", "2071-09-07", "2071-09-30", "2071-10-12", "2071-11-11", "2071-12-25", "2071-12-28",
+	"2072-01-01", "2072-02-15", "2072-04-08", "2072-05-23", "2072-07-01", "2072-08-01", "2072-09-05", "2072-09-30", "2072-10-10", "2072-11-11", "2072-12-26", "2072-12-27",
+	"2073-01-02", "2073-02-20", "2073-03-24", "2073-05-22", "2073-07-03", "2073-08-07", "2073-09-04", "2073-10-02", "2073-10-09", "2073-11-13", "2073-12-25", "2073-12-26",
+	"2074-01-01", "2074-02-19", "2074-04-13", "2074-05-21", "2074-07-02", "2074-08-06", "2074-09-03", "2074-10-01", "2074-10-08", "2074-11-12", "2074-12-25", "2074-12-26",
+	"2075-01-01", "2075-02-18", "2075-04-05", "2075-05-20", "2075-07-01", "2075-08-05", "2075-09-02", "2075-09-30", "2075-10-14", "2075-11-11", "2075-12-25", "2075-12-26",
+	"2076-01-01", "2076-02-17", "2076-04-17", "2076-05-18", "2076-07-01", "2076-08-03", "2076-09-07", "2076-09-30", "2076-10-12", "2076-11-11", "2076-12-25", "2076-12-28",
+	"2077-01-01", "2077-02-15", "2077-04-09", "2077-05-24", "2077-07-01", "2077-08-02", "2077-09-06", "2077-09-30", "2077-10-11", "2077-11-11", "2077-12-27", "2077-12-28",
+	"2078-01-03", "2078-02-21", "2078-04-01", "2078-05-23", "2078-07-01", "2078-08-01", "2078-09-05", "2078-09-30", "2078-10-10", "2078-11-11", "2078-12-26", "2078-12-27",
+	"2079-01-02", "2079-02-20", "2079-04-21", "2079-05-22", "2079-07-03", "2079-08-07", "2079-09-04", "2079-10-02", "2079-10-09", "2079-11-13", "2079-12-25", "2079-12-26",
+	"2080-01-01", "2080-02-19", "2080-04-05", "2080-05-20", "2080-07-01", "2080-08-05", "2080-09-02", "2080-09-30", "2080-10-14", "2080-11-11", "2080-12-25", "2080-12-26",
+}
+
+func init() {
+	caHolidays = buildHolidayMap(caHolidayList)
+}
@@ -14,6 +14,18 @@ func SortDates(dates []time.Time) {
 	})
 }
 
+// SearchDates locates target in an ascending date slice using binary search.
+//
+// It returns the index of the first date on or after target (len(sorted) if
+// every date is before target) and whether that date equals target.
+func SearchDates(sorted []time.Time, target time.Time) (idx int, exact bool) {
+	idx = sort.Search(len(sorted), func(i int) bool {
+		return !sorted[i].Before(target)
+	})
+	exact = idx < len(sorted) && sorted[idx].Equal(target)
+	return idx, exact
+}
+
 // AdjacentDates returns the two dates from a sorted date slice that bracket target.
 //
 // It assumes dates is sorted in ascending order and has at least two elements.
@@ -23,10 +35,7 @@ func AdjacentDates(target time.Time, dates []time.Time) (time.Time, time.Time) {
 		panic("AdjacentDates: need at least 2 dates")
 	}
 
-	// First index with dates[i] >= target.
-	i := sort.Search(len(dates), func(i int) bool {
-		return !dates[i].Before(target)
-	})
+	i, _ := SearchDates(dates, target)
 
 	if i <= 0 {
 		return dates[0], dates[1]
@@ -0,0 +1,42 @@
+package calendar
+
+import "time"
+
+// IMMDate returns the IMM date of the given month/year: its third Wednesday. IMM dates
+// are defined on the Gregorian calendar; see NextIMMDatesAdjusted to roll them onto a
+// business day.
+func IMMDate(year int, month time.Month) time.Time {
+	t := time.Date(year, month, 15, 0, 0, 0, 0, time.UTC)
+	for t.Weekday() != time.Wednesday {
+		t = t.AddDate(0, 0, 1)
+	}
+	return t
+}
+
+// NextIMMDates returns the n quarterly (Mar/Jun/Sep/Dec) IMM dates strictly after from.
+func NextIMMDates(from time.Time, n int) []time.Time {
+	dates := make([]time.Time, 0, max(n, 0))
+	y, m := from.Year(), from.Month()
+	m -= (m - 1) % 3 // first month of from's quarter
+	m += 2           // its IMM month
+	for len(dates) < n {
+		if d := IMMDate(y, m); d.After(from) {
+			dates = append(dates, d)
+		}
+		if m += 3; m > time.December {
+			y, m = y+1, m-12
+		}
+	}
+	return dates
+}
+
+// NextIMMDatesAdjusted is NextIMMDates with each date adjusted on cal (Modified
+// Following), for markets whose contracts settle on the next business day when the IMM
+// date is a holiday.
+func NextIMMDatesAdjusted(cal CalendarID, from time.Time, n int) []time.Time {
+	dates := NextIMMDates(from, n)
+	for i, d := range dates {
+		dates[i] = Adjust(cal, d)
+	}
+	return dates
+}
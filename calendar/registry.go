@@ -0,0 +1,120 @@
+package calendar
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// registeredHolidays holds holidays added at runtime, per calendar, on top of the
+// built-in lists. RegisterHolidays swaps in an updated copy, so isHoliday reads it
+// without locking.
+var (
+	registerMu         sync.Mutex
+	registeredHolidays atomic.Pointer[map[CalendarID]map[string]struct{}]
+)
+
+// joinedCalendars maps each calendar made by Join to its members, copy-on-write like
+// registeredHolidays.
+var joinedCalendars atomic.Pointer[map[CalendarID][]CalendarID]
+
+// Join returns a calendar whose holidays are the union of its members' holidays, for
+// schedules that must fall on business days in several centres (e.g. a EUR/USD
+// cross-currency swap on TARGET and FD). Every calendar here closes on Saturday and
+// Sunday, so the weekends are the members' too. The id is the members joined with "+"
+// ("TARGET+FD"); Join of a single calendar returns it unchanged, and of none "".
+func Join(ids ...CalendarID) CalendarID {
+	switch len(ids) {
+	case 0:
+		return ""
+	case 1:
+		return ids[0]
+	}
+	names := make([]string, len(ids))
+	for i, id := range ids {
+		names[i] = string(id)
+	}
+	joined := CalendarID(strings.Join(names, "+"))
+
+	registerMu.Lock()
+	defer registerMu.Unlock()
+	next := map[CalendarID][]CalendarID{}
+	if cur := joinedCalendars.Load(); cur != nil {
+		maps.Copy(next, *cur)
+	}
+	next[joined] = append([]CalendarID(nil), ids...)
+	joinedCalendars.Store(&next)
+	return joined
+}
+
+// joinedHoliday reports whether cal was made by Join and t is a holiday of any of its
+// members.
+func joinedHoliday(cal CalendarID, t time.Time) bool {
+	cur := joinedCalendars.Load()
+	if cur == nil {
+		return false
+	}
+	for _, member := range (*cur)[cal] {
+		if isHoliday(member, t) {
+			return true
+		}
+	}
+	return false
+}
+
+// RegisterHolidays adds dates to the holidays of calendar id, for years the built-in
+// lists do not cover yet or for a calendar of its own: an id with no built-in list is a
+// weekends-plus-registered-holidays calendar. Dates are taken by their calendar day.
+// It is safe for concurrent use with the rest of the package.
+func RegisterHolidays(id CalendarID, dates []time.Time) {
+	registerMu.Lock()
+	defer registerMu.Unlock()
+
+	next := map[CalendarID]map[string]struct{}{}
+	if cur := registeredHolidays.Load(); cur != nil {
+		maps.Copy(next, *cur)
+	}
+	days := maps.Clone(next[id])
+	if days == nil {
+		days = make(map[string]struct{}, len(dates))
+	}
+	for _, d := range dates {
+		days[d.Format("2006-01-02")] = struct{}{}
+	}
+	next[id] = days
+	registeredHolidays.Store(&next)
+}
+
+// LoadHolidaysJSON reads a JSON array of "YYYY-MM-DD" dates from r and registers them as
+// holidays of calendar id (see RegisterHolidays). Nothing is registered on error.
+func LoadHolidaysJSON(id CalendarID, r io.Reader) error {
+	var raw []string
+	if err := json.NewDecoder(r).Decode(&raw); err != nil {
+		return fmt.Errorf("LoadHolidaysJSON: %w", err)
+	}
+	dates := make([]time.Time, 0, len(raw))
+	for _, s := range raw {
+		d, err := ParseDate(s)
+		if err != nil {
+			return fmt.Errorf("LoadHolidaysJSON: %w", err)
+		}
+		dates = append(dates, d)
+	}
+	RegisterHolidays(id, dates)
+	return nil
+}
+
+// isRegisteredHoliday reports whether key (YYYY-MM-DD) was registered for cal.
+func isRegisteredHoliday(cal CalendarID, key string) bool {
+	cur := registeredHolidays.Load()
+	if cur == nil {
+		return false
+	}
+	_, ok := (*cur)[cal][key]
+	return ok
+}
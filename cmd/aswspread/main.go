@@ -1,6 +1,7 @@
 package main
 
 import (
+	"encoding/csv"
 	"encoding/json"
 	"flag"
 	"fmt"
@@ -73,6 +74,7 @@ type aswOutput struct {
 func main() {
 	inputParams := flag.String("input-params", "", "ASW fixture JSON path")
 	input := flag.String("input", "", "ASW fixture JSON path (alias of -input-params)")
+	cashflowsCSV := flag.String("cashflows-csv", "", "optional path to write bond and float leg cashflows as CSV")
 	flag.Parse()
 
 	path := strings.TrimSpace(*inputParams)
@@ -131,6 +133,7 @@ func main() {
 	}
 
 	outputs := make([]aswOutput, 0, len(fixture.Bonds))
+	var csvRows [][]string
 
 	for _, tc := range fixture.Bonds {
 		cfs := make([]bond.Cashflow, 0, len(tc.Cashflows))
@@ -188,6 +191,14 @@ func main() {
 			ASWType:             string(aswType),
 		}
 		outputs = append(outputs, out)
+		csvRows = append(csvRows, cashflowRecords(tc.ISIN, settlement, cfs, res, disc)...)
+	}
+
+	if p := strings.TrimSpace(*cashflowsCSV); p != "" {
+		if err := writeCashflowsCSV(p, csvRows); err != nil {
+			fmt.Fprintf(os.Stderr, "cashflows csv: %v\n", err)
+			os.Exit(1)
+		}
 	}
 
 	enc := json.NewEncoder(os.Stdout)
@@ -198,6 +209,67 @@ func main() {
 	}
 }
 
+// cashflowsHeader is the column layout of the -cashflows-csv output. Rates and
+// spreads are in percent and bp; leg is BOND or FLOAT.
+var cashflowsHeader = []string{
+	"isin", "leg", "start_date", "end_date", "pay_date", "accrual",
+	"coupon", "principal", "forward_pct", "spread_bp", "df", "pv",
+}
+
+// cashflowRecords lays out one bond's cashflows and the synthetic floating leg
+// used by ComputeASWSpread. Bond cashflows before settlement are skipped; float
+// periods paid before settlement are kept with zero PV.
+func cashflowRecords(isin string, settlement time.Time, cfs []bond.Cashflow, res bond.ASWResult, disc swap.DiscountCurve) [][]string {
+	const layout = "2006-01-02"
+	num := func(v float64) string { return strconv.FormatFloat(v, 'f', -1, 64) }
+
+	rows := make([][]string, 0, len(cfs)+len(res.FloatPeriods))
+	for _, cf := range cfs {
+		if cf.Date.Before(settlement) {
+			continue
+		}
+		df := disc.DF(cf.Date)
+		rows = append(rows, []string{
+			isin, "BOND", "", "", cf.Date.Format(layout), "",
+			num(cf.Coupon), num(cf.Principal), "", "", num(df), num(cf.Amount() * df),
+		})
+	}
+
+	for _, p := range res.FloatPeriods {
+		// Forward implied by the discount curve, as in the single-curve PV01.
+		fwd := 0.0
+		if p.Accrual > 0 {
+			fwd = (disc.DF(p.StartDate)/disc.DF(p.EndDate) - 1) / p.Accrual
+		}
+		pv := 0.0
+		if !p.PayDate.Before(settlement) {
+			pv = res.FloatNotional * (fwd + res.SpreadBP*1e-4) * p.Accrual * p.DF
+		}
+		rows = append(rows, []string{
+			isin, "FLOAT", p.StartDate.Format(layout), p.EndDate.Format(layout), p.PayDate.Format(layout), num(p.Accrual),
+			"", "", num(fwd * 100), num(res.SpreadBP), num(p.DF), num(pv),
+		})
+	}
+	return rows
+}
+
+func writeCashflowsCSV(path string, rows [][]string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	w := csv.NewWriter(f)
+	if err := w.Write(cashflowsHeader); err != nil {
+		f.Close()
+		return err
+	}
+	if err := w.WriteAll(rows); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
 func resolvePath(value string) string {
 	if value == "" {
 		return value
@@ -231,6 +303,9 @@ func calendarFromFloatLeg(floatLeg market.LegConvention) (calendar.CalendarID, e
 	if floatLeg.Calendar != "" {
 		return floatLeg.Calendar, nil
 	}
+	if profile, err := market.IndexDefaults(string(floatLeg.ReferenceIndex)); err == nil {
+		return profile.Calendar, nil
+	}
 	return "", fmt.Errorf("calendar is required (no calendar on float leg)")
 }
 
@@ -265,40 +340,17 @@ func buildCurveFromConvention(settlement time.Time, quotes map[string]float64, c
 
 func floatLegFromFixture(fixture aswFixture) (market.LegConvention, error) {
 	if fixture.FloatingSwapLeg != "" {
-		return floatLegFromString(fixture.FloatingSwapLeg)
+		return swaps.LegByName(fixture.FloatingSwapLeg)
 	}
 	if fixture.FloatLegConvention != "" {
-		return floatLegFromString(fixture.FloatLegConvention)
+		return swaps.LegByName(fixture.FloatLegConvention)
 	}
 	if fixture.CurveFloatIndex != "" {
-		return floatLegFromString(fixture.CurveFloatIndex)
+		return swaps.LegByName(fixture.CurveFloatIndex)
 	}
 	return market.LegConvention{}, fmt.Errorf("swap floating leg is required (set floating_swap_leg)")
 }
 
-func floatLegFromString(value string) (market.LegConvention, error) {
-	switch value {
-	case "ESTR", "ESTRFloating":
-		return swaps.ESTRFloating, nil
-	case "EURIBOR3M", "EURIBOR3MFloating":
-		return swaps.EURIBOR3MFloating, nil
-	case "EURIBOR6M", "EURIBOR6MFloating":
-		return swaps.EURIBOR6MFloating, nil
-	case "KRXCD91DFloating":
-		return swaps.KRXCD91DFloating, nil
-	case "TIBOR3M", "TIBOR3MFloating":
-		return swaps.TIBOR3MFloating, nil
-	case "TIBOR6M", "TIBOR6MFloating":
-		return swaps.TIBOR6MFloating, nil
-	case "TONAR", "TONARFloating":
-		return swaps.TONARFloating, nil
-	case "SOFR", "SOFRFloating":
-		return swaps.SOFRFloating, nil
-	default:
-		return market.LegConvention{}, fmt.Errorf("unknown float leg %q", value)
-	}
-}
-
 func buildKRXCurve(settlement time.Time, quotes []curveQuote) (*krxch.Curve, error) {
 	if settlement.IsZero() {
 		return nil, fmt.Errorf("settlement date is required")
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/meenmo/molib/bond"
+)
+
+func TestWriteCashflowsCSV_SampleInput(t *testing.T) {
+	raw, err := os.ReadFile("testdata/sample_input.json")
+	if err != nil {
+		t.Fatalf("read fixture: %v", err)
+	}
+	var fixture aswFixture
+	if err := json.Unmarshal(raw, &fixture); err != nil {
+		t.Fatalf("parse fixture: %v", err)
+	}
+	settlement, _ := time.Parse("2006-01-02", fixture.CurveDate)
+
+	floatLeg, err := floatLegFromFixture(fixture)
+	if err != nil {
+		t.Fatalf("float leg: %v", err)
+	}
+	disc, err := buildDiscountCurve(fixture, settlement, floatLeg.Calendar)
+	if err != nil {
+		t.Fatalf("build curve: %v", err)
+	}
+
+	tc := fixture.Bonds[0]
+	cfs := make([]bond.Cashflow, 0, len(tc.Cashflows))
+	bondRows := 0
+	for _, r := range tc.Cashflows {
+		d, _ := time.Parse("2006-01-02", r.Date)
+		cfs = append(cfs, bond.Cashflow{Date: d, Coupon: float64(r.Coupon), Principal: float64(r.Principal)})
+		if !d.Before(settlement) {
+			bondRows++
+		}
+	}
+	px, _ := tc.BondDirtyPrice.Float64()
+	res, err := bond.ComputeASWSpread(bond.ASWInput{
+		SettlementDate: settlement,
+		DirtyPrice:     tc.Notional * px / 100.0,
+		Notional:       tc.Notional,
+		Cashflows:      cfs,
+		FloatLeg:       floatLeg,
+		DiscountCurve:  disc,
+		ASWType:        bond.ASWTypeMMS,
+	})
+	if err != nil {
+		t.Fatalf("ComputeASWSpread: %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "cashflows.csv")
+	if err := writeCashflowsCSV(path, cashflowRecords(tc.ISIN, settlement, cfs, res, disc)); err != nil {
+		t.Fatalf("writeCashflowsCSV: %v", err)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("open csv: %v", err)
+	}
+	defer f.Close()
+	records, err := csv.NewReader(f).ReadAll()
+	if err != nil {
+		t.Fatalf("read csv: %v", err)
+	}
+
+	if !reflect.DeepEqual(records[0], cashflowsHeader) {
+		t.Fatalf("header=%v, want %v", records[0], cashflowsHeader)
+	}
+	if want := 1 + bondRows + len(res.FloatPeriods); len(records) != want {
+		t.Fatalf("rows=%d, want %d (header + %d bond + %d float)", len(records), want, bondRows, len(res.FloatPeriods))
+	}
+	legs := map[string]int{}
+	for _, r := range records[1:] {
+		if r[0] != tc.ISIN {
+			t.Fatalf("isin=%q, want %q", r[0], tc.ISIN)
+		}
+		legs[r[1]]++
+	}
+	if legs["BOND"] != bondRows || legs["FLOAT"] != len(res.FloatPeriods) {
+		t.Fatalf("leg counts=%v", legs)
+	}
+}
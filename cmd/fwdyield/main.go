@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"github.com/meenmo/molib/bond"
+	"github.com/meenmo/molib/daycount"
 )
 
 type yieldInput struct {
@@ -101,8 +102,9 @@ func process(in yieldInput) (*yieldOutput, error) {
 	if err != nil {
 		return nil, fmt.Errorf("invalid settlement_date: %v", err)
 	}
-	if in.DayCount != "ACT/ACT" {
-		return nil, fmt.Errorf("unsupported day_count %q (only ACT/ACT)", in.DayCount)
+	// The forward yield discounts ACT/ACT ICMA; accept any name registered for it.
+	if dc, ok := daycount.Lookup(in.DayCount); in.DayCount != "ACT/ACT" && (!ok || dc != daycount.ActActICMA) {
+		return nil, fmt.Errorf("unsupported day_count %q (only ACT/ACT ICMA)", in.DayCount)
 	}
 
 	cfs := make([]bond.Cashflow, 0, len(in.Cashflows))
@@ -0,0 +1,57 @@
+// Package parswap builds the spot and forward-starting par swaps priced by the
+// parswaprate and swapmatrix commands, so both solve the same trade for the same inputs.
+package parswap
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/meenmo/molib/instruments/swaps"
+	"github.com/meenmo/molib/swap"
+	"github.com/meenmo/molib/swap/market"
+)
+
+// ErrUnknownIndex is returned by SelfDiscountedLegs for an index it cannot price.
+var ErrUnknownIndex = errors.New("unknown floating_rate_index")
+
+// SelfDiscountedLegs resolves the fixed and floating legs for a par rate on an index
+// whose curve both projects and discounts (market.IndexDefaults with DiscountIndex equal
+// to the index). Principal exchange is disabled for standard par rate calculation.
+func SelfDiscountedLegs(index string) (fixedLeg, floatLeg market.LegConvention, err error) {
+	profile, err := market.IndexDefaults(index)
+	if err != nil || profile.DiscountIndex != profile.Index {
+		return fixedLeg, floatLeg, fmt.Errorf("%w: %s", ErrUnknownIndex, index)
+	}
+	floatLeg, err = swaps.LegByName(string(profile.Index))
+	if err != nil {
+		return fixedLeg, floatLeg, err
+	}
+	floatLeg.IncludeInitialPrincipal = false
+	floatLeg.IncludeFinalPrincipal = false
+	fixedLeg, err = swaps.DefaultFixedLeg(floatLeg)
+	return fixedLeg, floatLeg, err
+}
+
+// Params returns the swap paying fixedLeg against floatLeg, traded and valued on
+// tradeDate: the floating leg projects on curveQuotes, and both legs discount on
+// discountQuotes, or on curveQuotes when none are given (single-curve pricing). The
+// caller sets the tenors or explicit dates.
+func Params(curveDate, tradeDate time.Time, notional float64, fixedLeg, floatLeg market.LegConvention, curveQuotes, discountQuotes map[string]float64) swap.InterestRateSwapParams {
+	if len(discountQuotes) == 0 {
+		discountQuotes = curveQuotes
+	}
+	return swap.InterestRateSwapParams{
+		DataSource:     swap.DataSourceBGN,
+		ClearingHouse:  swap.ClearingHouseOTC,
+		CurveDate:      curveDate,
+		TradeDate:      tradeDate,
+		ValuationDate:  tradeDate,
+		Notional:       notional,
+		PayLeg:         fixedLeg,
+		RecLeg:         floatLeg,
+		DiscountingOIS: floatLeg,
+		OISQuotes:      discountQuotes,
+		RecLegQuotes:   curveQuotes,
+	}
+}
@@ -9,7 +9,6 @@ import (
 	"strings"
 	"time"
 
-	"github.com/meenmo/molib/calendar"
 	"github.com/meenmo/molib/instruments/swaps"
 	"github.com/meenmo/molib/swap"
 	"github.com/meenmo/molib/swap/market"
@@ -35,15 +34,19 @@ type PricingInput struct {
 	// - REC (receive fixed, pay floating)
 	Direction string `json:"direction"`
 
-	// FloatIndex is the IBOR index (e.g., EURIBOR6M, EURIBOR3M, TIBOR6M, TIBOR3M).
+	// FloatIndex is the IBOR index (e.g., EURIBOR6M, EURIBOR3M, TIBOR6M, TIBOR3M), or an
+	// overnight index (e.g., SOFR, ESTR) for an OIS-style IRS against the compounded rate.
 	FloatIndex string `json:"float_index"`
 
-	// OISIndex is the discounting overnight index (e.g., ESTR, TONAR).
+	// OISIndex is the discounting overnight index (e.g., ESTR, TONAR). If empty, the
+	// float index's market default is used (see market.IndexDefaults).
 	OISIndex string `json:"ois_index"`
 
-	FixedRatePct   float64            `json:"fixed_rate"`
-	FloatSpreadBP  float64            `json:"float_spread_bp"`
-	OISQuotesPct   map[string]float64 `json:"ois_quotes"`
+	FixedRatePct  float64            `json:"fixed_rate"`
+	FloatSpreadBP float64            `json:"float_spread_bp"`
+	OISQuotesPct  map[string]float64 `json:"ois_quotes"`
+	// FloatQuotesPct are the projection curve quotes; optional for an overnight float
+	// index, which then projects off ois_quotes.
 	FloatQuotesPct map[string]float64 `json:"float_quotes"`
 }
 
@@ -151,21 +154,33 @@ func calculateNPV(input PricingInput) (*PricingOutput, error) {
 		return nil, fmt.Errorf("direction is required (PAY or REC)")
 	}
 
-	floatLeg, err := floatLegFromString(input.FloatIndex)
+	floatProfile, err := market.IndexDefaults(input.FloatIndex)
+	if err != nil {
+		return nil, err
+	}
+	floatLeg, err := swaps.LegByName(string(floatProfile.Index))
 	if err != nil {
 		return nil, err
 	}
 	floatLeg = withoutPrincipal(floatLeg)
+	floatQuotes := input.FloatQuotesPct
+	if len(floatQuotes) == 0 && market.IsOvernight(floatLeg.ReferenceIndex) {
+		floatQuotes = input.OISQuotesPct
+	}
 
-	oisLeg, err := floatLegFromString(input.OISIndex)
+	oisIndex := input.OISIndex
+	if strings.TrimSpace(oisIndex) == "" {
+		oisIndex = string(floatProfile.DiscountIndex)
+	}
+	oisLeg, err := swaps.LegByName(oisIndex)
 	if err != nil {
 		return nil, err
 	}
 	if !market.IsOvernight(oisLeg.ReferenceIndex) {
-		return nil, fmt.Errorf("ois_index must be an overnight index, got %q", input.OISIndex)
+		return nil, fmt.Errorf("ois_index must be an overnight index, got %q", oisIndex)
 	}
 
-	fixedLeg, err := fixedLegFromFloat(floatLeg)
+	fixedLeg, err := swaps.DefaultFixedLeg(floatLeg)
 	if err != nil {
 		return nil, err
 	}
@@ -189,13 +204,13 @@ func calculateNPV(input PricingInput) (*PricingOutput, error) {
 		recLeg = floatLeg
 		paySpreadBP = fixedRateBP
 		recSpreadBP = input.FloatSpreadBP
-		recLegQuotes = input.FloatQuotesPct
+		recLegQuotes = floatQuotes
 	case "REC_FIXED", "REC":
 		payLeg = floatLeg
 		recLeg = fixedLeg
 		paySpreadBP = input.FloatSpreadBP
 		recSpreadBP = fixedRateBP
-		payLegQuotes = input.FloatQuotesPct
+		payLegQuotes = floatQuotes
 	default:
 		return nil, fmt.Errorf("invalid direction %q (use PAY or REC)", input.Direction)
 	}
@@ -203,7 +218,7 @@ func calculateNPV(input PricingInput) (*PricingOutput, error) {
 	if input.OISQuotesPct == nil || len(input.OISQuotesPct) == 0 {
 		return nil, fmt.Errorf("ois_quotes is required")
 	}
-	if input.FloatQuotesPct == nil || len(input.FloatQuotesPct) == 0 {
+	if len(floatQuotes) == 0 {
 		return nil, fmt.Errorf("float_quotes is required")
 	}
 
@@ -249,48 +264,3 @@ func withoutPrincipal(leg market.LegConvention) market.LegConvention {
 	leg.IncludeFinalPrincipal = false
 	return leg
 }
-
-func floatLegFromString(value string) (market.LegConvention, error) {
-	switch strings.TrimSpace(value) {
-	case "ESTR", "ESTRFloating":
-		return swaps.ESTRFloating, nil
-	case "EURIBOR3M", "EURIBOR3MFloating":
-		return swaps.EURIBOR3MFloating, nil
-	case "EURIBOR6M", "EURIBOR6MFloating":
-		return swaps.EURIBOR6MFloating, nil
-	case "TIBOR3M", "TIBOR3MFloating":
-		return swaps.TIBOR3MFloating, nil
-	case "TIBOR6M", "TIBOR6MFloating":
-		return swaps.TIBOR6MFloating, nil
-	case "TONAR", "TONARFloating":
-		return swaps.TONARFloating, nil
-	default:
-		return market.LegConvention{}, fmt.Errorf("unknown index %q", value)
-	}
-}
-
-func fixedLegFromFloat(floatLeg market.LegConvention) (market.LegConvention, error) {
-	switch floatLeg.Calendar {
-	case calendar.TARGET:
-		// EUR fixed leg: 30E/360, annual.
-		fixed := swaps.EURIBORFixed
-		fixed.DayCount = market.DayCount("30E/360")
-		fixed.PayFrequency = market.FreqAnnual
-		fixed.Calendar = floatLeg.Calendar
-		fixed.RollConvention = floatLeg.RollConvention
-		fixed.BusinessDayAdjustment = floatLeg.BusinessDayAdjustment
-		// Match Bloomberg-style stubs by generating from maturity backward for EUR IBOR swaps.
-		fixed.ScheduleDirection = market.ScheduleBackward
-		return fixed, nil
-	case calendar.JP:
-		// JPY fixed leg: ACT/365F, semi-annual.
-		fixed := swaps.TIBORFixed
-		fixed.Calendar = floatLeg.Calendar
-		fixed.RollConvention = floatLeg.RollConvention
-		fixed.BusinessDayAdjustment = floatLeg.BusinessDayAdjustment
-		fixed.ScheduleDirection = market.ScheduleBackward
-		return fixed, nil
-	default:
-		return market.LegConvention{}, fmt.Errorf("unsupported float leg calendar %q (cannot infer fixed leg)", floatLeg.Calendar)
-	}
-}
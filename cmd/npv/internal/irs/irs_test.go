@@ -0,0 +1,39 @@
+package irs
+
+import (
+	"math"
+	"testing"
+)
+
+func TestCalculateNPV_SOFRParFixedRatePricesToZero(t *testing.T) {
+	input := PricingInput{
+		CurveDate:      "2026-01-09",
+		TradeDate:      "2026-01-09",
+		SwapTenorYears: 5,
+		Notional:       10_000_000,
+		Direction:      "PAY",
+		FloatIndex:     "SOFR",
+		OISQuotesPct:   map[string]float64{"1Y": 3.48945, "2Y": 3.3717, "5Y": 3.49207, "10Y": 3.8005},
+	}
+	npvAt := func(ratePct float64) float64 {
+		in := input
+		in.FixedRatePct = ratePct
+		out, err := calculateNPV(in)
+		if err != nil {
+			t.Fatalf("calculateNPV(%.4f%%): %v", ratePct, err)
+		}
+		return out.TotalNPV
+	}
+
+	// NPV is linear in the fixed rate, so two points give the par rate.
+	npv3, npv4 := npvAt(3), npvAt(4)
+	par := 3 - npv3/(npv4-npv3)
+	if npv := npvAt(par); math.Abs(npv) > 1e-4 {
+		t.Fatalf("NPV at par fixed rate %.6f%% = %.6g, want ~0", par, npv)
+	}
+	// SOFR is self-discounted, so par sits near the 5Y quote; the compounded leg's rate
+	// cut-off and fixing calendar keep it from matching exactly.
+	if math.Abs(par-3.49207) > 0.01 {
+		t.Fatalf("par fixed rate %.6f%%, want within 1bp of the 5Y quote 3.49207%%", par)
+	}
+}
@@ -149,16 +149,20 @@ func calculateNPV(input PricingInput) (*PricingOutput, error) {
 		return nil, fmt.Errorf("ois_quotes is required")
 	}
 
-	oisFloat, err := floatLegFromString(input.OISIndex)
+	profile, err := market.IndexDefaults(input.OISIndex)
 	if err != nil {
 		return nil, err
 	}
-	if !market.IsOvernight(oisFloat.ReferenceIndex) {
+	if !market.IsOvernight(profile.Index) {
 		return nil, fmt.Errorf("ois_index must be an overnight index, got %q", input.OISIndex)
 	}
+	oisFloat, err := swaps.LegByName(string(profile.Index))
+	if err != nil {
+		return nil, err
+	}
 	oisFloat = withoutPrincipal(oisFloat)
 
-	fixedLeg, err := fixedLegFromOISIndex(input.OISIndex)
+	fixedLeg, err := swaps.DefaultFixedLeg(oisFloat)
 	if err != nil {
 		return nil, err
 	}
@@ -235,29 +239,3 @@ func withoutPrincipal(leg market.LegConvention) market.LegConvention {
 	leg.IncludeFinalPrincipal = false
 	return leg
 }
-
-func floatLegFromString(value string) (market.LegConvention, error) {
-	switch strings.TrimSpace(value) {
-	case "ESTR", "ESTRFloating":
-		return swaps.ESTRFloating, nil
-	case "SOFR", "SOFRFloating":
-		return swaps.SOFRFloating, nil
-	case "TONAR", "TONARFloating":
-		return swaps.TONARFloating, nil
-	default:
-		return market.LegConvention{}, fmt.Errorf("unknown ois_index %q", value)
-	}
-}
-
-func fixedLegFromOISIndex(value string) (market.LegConvention, error) {
-	switch strings.TrimSpace(value) {
-	case "ESTR", "ESTRFloating":
-		return swaps.ESTRFixed, nil
-	case "SOFR", "SOFRFloating":
-		return swaps.SOFRFixed, nil
-	case "TONAR", "TONARFloating":
-		return swaps.TONARFixed, nil
-	default:
-		return market.LegConvention{}, fmt.Errorf("unknown ois_index %q", value)
-	}
-}
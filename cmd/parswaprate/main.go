@@ -3,6 +3,7 @@ package main
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
 	"io"
@@ -13,7 +14,7 @@ import (
 	"time"
 
 	"github.com/meenmo/molib/calendar"
-	"github.com/meenmo/molib/instruments/swaps"
+	"github.com/meenmo/molib/cmd/internal/parswap"
 	"github.com/meenmo/molib/swap"
 	krx "github.com/meenmo/molib/swap/clearinghouse/krx"
 	"github.com/meenmo/molib/swap/market"
@@ -53,6 +54,11 @@ type PricingInput struct {
 	// period's reset date precedes the trade date. Maps "YYYY-MM-DD" to
 	// the fixing in percent.
 	ReferenceRateFixings map[string]float64 `json:"reference_rate_fixings,omitempty"`
+
+	// MaxIterations optionally caps the par solver's Newton steps (default 10). A run
+	// that hits the cap still reports its last rate and npv_residual, with an error. The
+	// CD91D path solves in closed form and ignores it.
+	MaxIterations *int `json:"max_iterations,omitempty"`
 }
 
 // PricingOutput defines the JSON output schema.
@@ -62,65 +68,20 @@ type PricingOutput struct {
 	FixedLegPV    float64 `json:"fixed_leg_pv"`
 	FloatingLegPV float64 `json:"floating_leg_pv"`
 	TotalNPV      float64 `json:"total_npv"`
+	NPVResidual   float64 `json:"npv_residual"` // NPV repriced at par_rate: ~0 unless the solver failed to converge
 	EffectiveDate string  `json:"effective_date"`
 	MaturityDate  string  `json:"maturity_date"`
 	Error         string  `json:"error,omitempty"`
 }
 
-// OISPreset groups fixed and floating leg conventions for an OIS swap.
-type OISPreset struct {
-	FixedLeg market.LegConvention
-	FloatLeg market.LegConvention
-}
-
-// oisPresets maps OIS index names to their leg conventions.
-// Floating legs have principal exchange disabled for standard OIS par rate calculation.
-var oisPresets = map[string]OISPreset{
-	"TONAR": {
-		FixedLeg: swaps.TONARFixed,
-		FloatLeg: func() market.LegConvention {
-			l := swaps.TONARFloating
-			l.IncludeInitialPrincipal = false
-			l.IncludeFinalPrincipal = false
-			return l
-		}(),
-	},
-	"ESTR": {
-		FixedLeg: swaps.ESTRFixed,
-		FloatLeg: func() market.LegConvention {
-			l := swaps.ESTRFloating
-			l.IncludeInitialPrincipal = false
-			l.IncludeFinalPrincipal = false
-			return l
-		}(),
-	},
-	"SOFR": {
-		FixedLeg: swaps.SOFRFixed,
-		FloatLeg: func() market.LegConvention {
-			l := swaps.SOFRFloating
-			l.IncludeInitialPrincipal = false
-			l.IncludeFinalPrincipal = false
-			return l
-		}(),
-	},
-	"SONIA": {
-		FixedLeg: swaps.SONIAFixed,
-		FloatLeg: func() market.LegConvention {
-			l := swaps.SONIAFloating
-			l.IncludeInitialPrincipal = false
-			l.IncludeFinalPrincipal = false
-			return l
-		}(),
-	},
-	"HIBOR3M": {
-		FixedLeg: swaps.HIBOR3MFixed,
-		FloatLeg: func() market.LegConvention {
-			l := swaps.HIBOR3MFloating
-			l.IncludeInitialPrincipal = false
-			l.IncludeFinalPrincipal = false
-			return l
-		}(),
-	},
+// selfDiscountedLegs resolves the fixed and floating legs for a par rate (see
+// parswap.SelfDiscountedLegs), naming the indices this command accepts on failure.
+func selfDiscountedLegs(index string) (fixedLeg, floatLeg market.LegConvention, err error) {
+	fixedLeg, floatLeg, err = parswap.SelfDiscountedLegs(index)
+	if errors.Is(err, parswap.ErrUnknownIndex) {
+		err = fmt.Errorf("%w (must be TONAR, ESTR, SOFR, SONIA, CORRA, HIBOR3M, or CD91D)", err)
+	}
+	return fixedLeg, floatLeg, err
 }
 
 func main() {
@@ -172,8 +133,10 @@ func main() {
 				in := inputs[i]
 				out, err := calculateParRate(in)
 				if err != nil {
-					outputs[i] = PricingOutput{TaskID: in.TaskID, Error: err.Error()}
-					continue
+					if out == nil {
+						out = &PricingOutput{TaskID: in.TaskID}
+					}
+					out.Error = err.Error()
 				}
 				outputs[i] = *out
 			}
@@ -212,7 +175,7 @@ func usage() {
 	fmt.Println("  parswaprate -input /path/to/input.json")
 	fmt.Println()
 	fmt.Println("Read JSON input, calculate par swap rate, output JSON to stdout.")
-	fmt.Println("Supported floating_rate_index: TONAR, ESTR, SOFR, SONIA, HIBOR3M, CD91D.")
+	fmt.Println("Supported floating_rate_index: TONAR, ESTR, SOFR, SONIA, CORRA, HIBOR3M, CD91D.")
 	fmt.Println()
 	fmt.Println("Common fields:")
 	fmt.Println(`  curve_date, trade_date, effective_date, maturity_date  (YYYY-MM-DD)`)
@@ -228,6 +191,7 @@ func usage() {
 	fmt.Println(`                         (e.g. 3M HIBOR cash for HIBOR3M IRS).`)
 	fmt.Println(`                         Maps to Bloomberg SWPM "Latest Index".`)
 	fmt.Println(`  reference_rate_fixings Date-keyed CD91 fixings (YYYY-MM-DD -> %) for CD91D path.`)
+	fmt.Println(`  max_iterations         Cap on par solver Newton steps (default 10).`)
 	fmt.Println()
 	fmt.Println("Example input (vanilla OIS):")
 	fmt.Println(`  {`)
@@ -279,18 +243,6 @@ func writeError(msg string) {
 	os.Exit(1)
 }
 
-// discountQuotes returns the par quotes used to bootstrap the discount curve.
-// When input.DiscountQuotes is supplied, it takes precedence (dual-curve
-// pricing — projection on CurveQuotes, discount on DiscountQuotes). Otherwise
-// the discount falls back to CurveQuotes for self-discounted single-curve
-// pricing.
-func discountQuotes(input PricingInput) map[string]float64 {
-	if len(input.DiscountQuotes) > 0 {
-		return input.DiscountQuotes
-	}
-	return input.CurveQuotes
-}
-
 func calculateParRate(input PricingInput) (*PricingOutput, error) {
 	switch strings.ToUpper(strings.TrimSpace(input.FloatingRateIndex)) {
 	case "CD91", "CD91D":
@@ -307,9 +259,9 @@ func calculateParRate(input PricingInput) (*PricingOutput, error) {
 		return nil, fmt.Errorf("invalid trade_date: %v", err)
 	}
 
-	preset, ok := oisPresets[input.FloatingRateIndex]
-	if !ok {
-		return nil, fmt.Errorf("unknown floating_rate_index: %s (must be TONAR, ESTR, SOFR, SONIA, HIBOR3M, or CD91D)", input.FloatingRateIndex)
+	fixedLeg, floatLeg, err := selfDiscountedLegs(input.FloatingRateIndex)
+	if err != nil {
+		return nil, err
 	}
 
 	if input.CurveQuotes == nil || len(input.CurveQuotes) == 0 {
@@ -329,20 +281,8 @@ func calculateParRate(input PricingInput) (*PricingOutput, error) {
 		return nil, fmt.Errorf("swap_tenor is required when effective_date/maturity_date are not specified")
 	}
 
-	params := swap.InterestRateSwapParams{
-		DataSource:     swap.DataSourceBGN,
-		ClearingHouse:  swap.ClearingHouseOTC,
-		CurveDate:      curveDate,
-		TradeDate:      tradeDate,
-		ValuationDate:  tradeDate,
-		Notional:            input.Notional,
-		PayLeg:              preset.FixedLeg,
-		RecLeg:              preset.FloatLeg,
-		DiscountingOIS:      preset.FloatLeg,
-		OISQuotes:           discountQuotes(input),
-		RecLegQuotes:        input.CurveQuotes,
-		RecLegFirstResetPct: input.FirstResetPct,
-	}
+	params := parswap.Params(curveDate, tradeDate, input.Notional, fixedLeg, floatLeg, input.CurveQuotes, input.DiscountQuotes)
+	params.RecLegFirstResetPct = input.FirstResetPct
 
 	if hasExplicitDates {
 		effDate, err := time.Parse("2006-01-02", input.EffectiveDate)
@@ -365,22 +305,50 @@ func calculateParRate(input PricingInput) (*PricingOutput, error) {
 		return nil, fmt.Errorf("failed to build swap: %v", err)
 	}
 
-	spreadBP, pv, err := trade.SolveParSpread(swap.SpreadTargetPayLeg)
-	if err != nil {
-		return nil, fmt.Errorf("failed to solve par rate: %v", err)
+	spreadBP, pv, solveErr := solveParSpread(trade, swap.SpreadTargetPayLeg, input.MaxIterations)
+	if solveErr != nil && !errors.Is(solveErr, swap.ErrNotConverged) {
+		return nil, fmt.Errorf("failed to solve par rate: %v", solveErr)
 	}
 
 	parRatePct := spreadBP / 100.0
 
-	return &PricingOutput{
+	out := &PricingOutput{
 		TaskID:        input.TaskID,
 		ParRatePct:    parRatePct,
 		FixedLegPV:    pv.PayLegPV,
 		FloatingLegPV: pv.RecLegPV,
 		TotalNPV:      pv.TotalPV,
+		NPVResidual:   pv.TotalPV,
 		EffectiveDate: trade.Spec.EffectiveDate.Format("2006-01-02"),
 		MaturityDate:  trade.Spec.MaturityDate.Format("2006-01-02"),
-	}, nil
+	}
+	if solveErr != nil {
+		return out, fmt.Errorf("failed to solve par rate: %w", solveErr)
+	}
+	return out, nil
+}
+
+// solveParSpread solves the target leg's par spread, capped at maxIter Newton steps when
+// set, and prices the trade at the result. A run that hits the cap returns its last
+// spread and PV with an error wrapping swap.ErrNotConverged.
+func solveParSpread(trade *swap.SwapTrade, target swap.SpreadTarget, maxIter *int) (float64, swap.PV, error) {
+	if maxIter == nil {
+		return trade.SolveParSpread(target)
+	}
+	spreadBP, solveErr := swap.SolveParSpreadWithMaxIter(trade.Spec, trade.PayProjCurve, trade.RecProjCurve, trade.DiscountCurve, trade.ValuationDate, target, *maxIter)
+	if solveErr != nil && !errors.Is(solveErr, swap.ErrNotConverged) {
+		return 0, swap.PV{}, solveErr
+	}
+	if target == swap.SpreadTargetPayLeg {
+		trade.Spec.PayLegSpreadBP = spreadBP
+	} else {
+		trade.Spec.RecLegSpreadBP = spreadBP
+	}
+	pv, err := trade.PVByLeg()
+	if err != nil {
+		return 0, swap.PV{}, err
+	}
+	return spreadBP, pv, solveErr
 }
 
 // calculateKRXParRate solves the par fixed rate for a KRW CD91 IRS using the
@@ -461,7 +429,7 @@ func calculateKRXParRate(input PricingInput) (*PricingOutput, error) {
 		SettlementDate:  input.TradeDate,
 		FixedRate:       probeRatePct,
 		Notional:        input.Notional,
-		Direction:       krx.PositionReceive,
+		Direction:       krx.PositionPay,
 		SwapQuotes:      quotes,
 		ReferenceIndex:  refFeed,
 	}
@@ -475,14 +443,22 @@ func calculateKRXParRate(input PricingInput) (*PricingOutput, error) {
 	}
 
 	parRatePct := probeRatePct * pvFloat / pvFixed
-	parPV := pvFloat
+
+	// Reprice at the solved rate so npv_residual checks it rather than restating the ratio.
+	par := probe
+	par.FixedRate = parRatePct
+	pvFixed, pvFloat, err = safeKRXPVByLeg(par, curve)
+	if err != nil {
+		return nil, err
+	}
 
 	return &PricingOutput{
 		TaskID:        input.TaskID,
 		ParRatePct:    parRatePct,
-		FixedLegPV:    parPV,
-		FloatingLegPV: parPV,
-		TotalNPV:      0,
+		FixedLegPV:    pvFixed,
+		FloatingLegPV: pvFloat,
+		TotalNPV:      pvFloat - pvFixed,
+		NPVResidual:   pvFloat - pvFixed,
 		EffectiveDate: effDate.Format("2006-01-02"),
 		MaturityDate:  matDate.Format("2006-01-02"),
 	}, nil
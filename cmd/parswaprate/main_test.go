@@ -0,0 +1,90 @@
+package main
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/meenmo/molib/swap"
+)
+
+func sofrParRateInput() PricingInput {
+	return PricingInput{
+		CurveDate:         "2026-01-09",
+		TradeDate:         "2026-01-09",
+		SwapTenor:         5,
+		Notional:          10_000_000,
+		FloatingRateIndex: "SOFR",
+		CurveQuotes:       map[string]float64{"1Y": 3.48945, "2Y": 3.3717, "5Y": 3.49207, "10Y": 3.8005},
+	}
+}
+
+func TestCalculateParRate_NPVResidual(t *testing.T) {
+	out, err := calculateParRate(sofrParRateInput())
+	if err != nil {
+		t.Fatalf("calculateParRate: %v", err)
+	}
+	if math.Abs(out.NPVResidual) > 1e-6 {
+		t.Fatalf("npv_residual=%.6g at the solved rate, want ~0", out.NPVResidual)
+	}
+	if want := out.FixedLegPV + out.FloatingLegPV; math.Abs(out.TotalNPV-want) > 1e-9 {
+		t.Fatalf("total_npv=%.6g, legs sum to %.6g", out.TotalNPV, want)
+	}
+}
+
+func TestCalculateParRate_CappedIterations(t *testing.T) {
+	// No Newton step: the solver stops at its 0bp starting guess.
+	in := sofrParRateInput()
+	zero := 0
+	in.MaxIterations = &zero
+	out, err := calculateParRate(in)
+	if !errors.Is(err, swap.ErrNotConverged) {
+		t.Fatalf("err=%v, want ErrNotConverged", err)
+	}
+	if out == nil {
+		t.Fatalf("capped run returned no output")
+	}
+	if out.ParRatePct != 0 || math.Abs(out.NPVResidual) < 1000 {
+		t.Fatalf("capped run: par_rate=%.6f npv_residual=%.6g, want 0 and a large residual", out.ParRatePct, out.NPVResidual)
+	}
+
+	// One step of an objective linear in the rate lands on the par rate.
+	one := 1
+	in.MaxIterations = &one
+	converged, err := calculateParRate(sofrParRateInput())
+	if err != nil {
+		t.Fatalf("calculateParRate: %v", err)
+	}
+	if out, err = calculateParRate(in); out == nil {
+		t.Fatalf("calculateParRate: %v", err)
+	}
+	if math.Abs(out.ParRatePct-converged.ParRatePct) > 1e-10 {
+		t.Fatalf("one step par_rate=%.12f, converged %.12f", out.ParRatePct, converged.ParRatePct)
+	}
+}
+
+func TestCalculateKRXParRate_RepricesAtSolvedRate(t *testing.T) {
+	out, err := calculateParRate(PricingInput{
+		TradeDate:         "2025-11-21",
+		EffectiveDate:     "2025-11-21",
+		MaturityDate:      "2030-11-21",
+		Notional:          10_000_000_000,
+		FloatingRateIndex: "CD91D",
+		CurveQuotes: map[string]float64{
+			"91D": 2.55, "6M": 2.76, "9M": 2.7225, "1Y": 2.7225, "2Y": 2.8075,
+			"3Y": 2.8882, "5Y": 3.0189, "10Y": 3.1579, "20Y": 3.0946,
+		},
+	})
+	if err != nil {
+		t.Fatalf("calculateParRate: %v", err)
+	}
+	if out.ParRatePct < 2.5 || out.ParRatePct > 3.5 {
+		t.Fatalf("par_rate=%.6f outside the quoted range", out.ParRatePct)
+	}
+	if math.Abs(out.FixedLegPV-out.FloatingLegPV) > 1e-3 || math.Abs(out.NPVResidual) > 1e-3 {
+		t.Fatalf("legs at par: fixed %.6f floating %.6f npv_residual %.6g", out.FixedLegPV, out.FloatingLegPV, out.NPVResidual)
+	}
+	if out.FixedLegPV == 0 {
+		t.Fatalf("fixed leg PV not repriced: %+v", out)
+	}
+}
@@ -3,6 +3,7 @@ package main
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
 	"io"
@@ -26,10 +27,15 @@ type PricingInput struct {
 	Notional     float64            `json:"notional"`
 	PayLeg       string             `json:"pay_leg"`    // "TIBOR6M", "TIBOR3M", "EURIBOR6M", "EURIBOR3M"
 	RecLeg       string             `json:"rec_leg"`    // "TIBOR3M", "TONAR", "EURIBOR3M", "ESTR"
-	OISIndex     string             `json:"ois_index"`  // "TONAR", "ESTR"
+	OISIndex     string             `json:"ois_index"`  // "TONAR", "ESTR"; defaults to the pay leg's discount index
 	OISQuotes    map[string]float64 `json:"ois_quotes"` // tenor -> rate%
 	PayLegQuotes map[string]float64 `json:"pay_leg_quotes"`
 	RecLegQuotes map[string]float64 `json:"rec_leg_quotes"`
+
+	// MaxIterations optionally caps the par solver's Newton steps (default 10). A run
+	// that hits the cap still reports its last spread and npv_residual, with an error. OIS
+	// basis swaps solve in closed form and ignore it.
+	MaxIterations *int `json:"max_iterations,omitempty"`
 }
 
 // PricingOutput defines the JSON output schema.
@@ -40,19 +46,20 @@ type PricingOutput struct {
 	PayLegPV      float64 `json:"pay_leg_pv"`
 	RecLegPV      float64 `json:"rec_leg_pv"`
 	TotalNPV      float64 `json:"total_npv"`
+	NPVResidual   float64 `json:"npv_residual"` // NPV repriced at spread_bp: ~0 unless the solver failed to converge
 	EffectiveDate string  `json:"effective_date"`
 	MaturityDate  string  `json:"maturity_date"`
 	Error         string  `json:"error,omitempty"`
 }
 
-// legConventions maps string identifiers to LegConvention.
-var legConventions = map[string]market.LegConvention{
-	"TIBOR6M":   swaps.TIBOR6MFloating,
-	"TIBOR3M":   swaps.TIBOR3MFloating,
-	"TONAR":     swaps.TONARFloating,
-	"EURIBOR6M": swaps.EURIBOR6MFloating,
-	"EURIBOR3M": swaps.EURIBOR3MFloating,
-	"ESTR":      swaps.ESTRFloating,
+// legByIndex resolves a floating leg from an index name known to market.IndexDefaults.
+func legByIndex(name string) (market.LegConvention, market.IndexProfile, error) {
+	profile, err := market.IndexDefaults(name)
+	if err != nil {
+		return market.LegConvention{}, market.IndexProfile{}, err
+	}
+	leg, err := swaps.LegByName(string(profile.Index))
+	return leg, profile, err
 }
 
 func main() {
@@ -86,20 +93,7 @@ func main() {
 		return
 	}
 
-	hadError := false
-	outputs := make([]PricingOutput, 0, len(inputs))
-	for _, in := range inputs {
-		out, err := calculateSpread(in)
-		if err != nil {
-			hadError = true
-			outputs = append(outputs, PricingOutput{
-				TaskID: in.TaskID,
-				Error:  err.Error(),
-			})
-			continue
-		}
-		outputs = append(outputs, *out)
-	}
+	outputs, hadError := calculateSpreads(inputs)
 
 	if isArray {
 		outputBytes, _ := json.Marshal(outputs)
@@ -175,6 +169,25 @@ func writeError(msg string) {
 	os.Exit(1)
 }
 
+// calculateSpreads prices each input in order. A failed task yields an output carrying
+// its task_id and error (plus its last spread and PVs if the solver hit max_iterations),
+// and sets hadError; the remaining tasks still run.
+func calculateSpreads(inputs []PricingInput) (outputs []PricingOutput, hadError bool) {
+	outputs = make([]PricingOutput, 0, len(inputs))
+	for _, in := range inputs {
+		out, err := calculateSpread(in)
+		if err != nil {
+			hadError = true
+			if out == nil {
+				out = &PricingOutput{TaskID: in.TaskID}
+			}
+			out.Error = err.Error()
+		}
+		outputs = append(outputs, *out)
+	}
+	return outputs, hadError
+}
+
 func calculateSpread(input PricingInput) (*PricingOutput, error) {
 	curveDate, err := time.Parse("2006-01-02", input.CurveDate)
 	if err != nil {
@@ -186,19 +199,24 @@ func calculateSpread(input PricingInput) (*PricingOutput, error) {
 		return nil, fmt.Errorf("invalid trade_date: %v", err)
 	}
 
-	payLeg, ok := legConventions[input.PayLeg]
-	if !ok {
+	payLeg, payProfile, err := legByIndex(input.PayLeg)
+	if err != nil {
 		return nil, fmt.Errorf("unknown pay_leg: %s", input.PayLeg)
 	}
 
-	recLeg, ok := legConventions[input.RecLeg]
-	if !ok {
+	recLeg, _, err := legByIndex(input.RecLeg)
+	if err != nil {
 		return nil, fmt.Errorf("unknown rec_leg: %s", input.RecLeg)
 	}
 
-	oisLeg, ok := legConventions[input.OISIndex]
-	if !ok {
-		return nil, fmt.Errorf("unknown ois_index: %s", input.OISIndex)
+	// Without an explicit ois_index, discount on the pay leg's market default.
+	oisIndex := input.OISIndex
+	if strings.TrimSpace(oisIndex) == "" {
+		oisIndex = string(payProfile.DiscountIndex)
+	}
+	oisLeg, oisProfile, err := legByIndex(oisIndex)
+	if err != nil || !market.IsOvernight(oisProfile.Index) {
+		return nil, fmt.Errorf("unknown ois_index: %s", oisIndex)
 	}
 
 	if input.OISQuotes == nil || len(input.OISQuotes) == 0 {
@@ -225,18 +243,46 @@ func calculateSpread(input PricingInput) (*PricingOutput, error) {
 		return nil, fmt.Errorf("failed to build swap: %v", err)
 	}
 
-	spreadBP, pv, err := trade.SolveParSpread(swap.SpreadTargetRecLeg)
-	if err != nil {
-		return nil, fmt.Errorf("failed to solve par spread: %v", err)
+	spreadBP, pv, solveErr := solveParSpread(trade, swap.SpreadTargetRecLeg, input.MaxIterations)
+	if solveErr != nil && !errors.Is(solveErr, swap.ErrNotConverged) {
+		return nil, fmt.Errorf("failed to solve par spread: %v", solveErr)
 	}
 
-	return &PricingOutput{
+	out := &PricingOutput{
 		TaskID:        input.TaskID,
 		SpreadBP:      spreadBP,
 		PayLegPV:      pv.PayLegPV,
 		RecLegPV:      pv.RecLegPV,
 		TotalNPV:      pv.TotalPV,
+		NPVResidual:   pv.TotalPV,
 		EffectiveDate: trade.Spec.EffectiveDate.Format("2006-01-02"),
 		MaturityDate:  trade.Spec.MaturityDate.Format("2006-01-02"),
-	}, nil
+	}
+	if solveErr != nil {
+		return out, fmt.Errorf("failed to solve par spread: %w", solveErr)
+	}
+	return out, nil
+}
+
+// solveParSpread solves the target leg's par spread, capped at maxIter Newton steps when
+// set, and prices the trade at the result. A run that hits the cap returns its last
+// spread and PV with an error wrapping swap.ErrNotConverged.
+func solveParSpread(trade *swap.SwapTrade, target swap.SpreadTarget, maxIter *int) (float64, swap.PV, error) {
+	if maxIter == nil || trade.IsOISBasisSwap {
+		return trade.SolveParSpread(target)
+	}
+	spreadBP, solveErr := swap.SolveParSpreadWithMaxIter(trade.Spec, trade.PayProjCurve, trade.RecProjCurve, trade.DiscountCurve, trade.ValuationDate, target, *maxIter)
+	if solveErr != nil && !errors.Is(solveErr, swap.ErrNotConverged) {
+		return 0, swap.PV{}, solveErr
+	}
+	if target == swap.SpreadTargetPayLeg {
+		trade.Spec.PayLegSpreadBP = spreadBP
+	} else {
+		trade.Spec.RecLegSpreadBP = spreadBP
+	}
+	pv, err := trade.PVByLeg()
+	if err != nil {
+		return 0, swap.PV{}, err
+	}
+	return spreadBP, pv, solveErr
 }
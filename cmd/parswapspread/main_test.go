@@ -0,0 +1,85 @@
+package main
+
+import (
+	"encoding/json"
+	"math"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestCalculateSpreads_BatchKeepsOrderAndPerTaskErrors(t *testing.T) {
+	raw, err := os.ReadFile("testdata/input.json")
+	if err != nil {
+		t.Fatalf("read testdata: %v", err)
+	}
+	var fixture []PricingInput
+	if err := json.Unmarshal(raw, &fixture); err != nil {
+		t.Fatalf("parse testdata: %v", err)
+	}
+	bad := fixture[0]
+	bad.TaskID = "bad_pay_leg"
+	bad.PayLeg = "LIBOR6M"
+
+	batch, err := json.Marshal([]PricingInput{bad, fixture[0]})
+	if err != nil {
+		t.Fatalf("marshal batch: %v", err)
+	}
+	inputs, isArray, err := parseInputs(batch)
+	if err != nil || !isArray {
+		t.Fatalf("parseInputs: isArray=%v err=%v", isArray, err)
+	}
+
+	outputs, hadError := calculateSpreads(inputs)
+	if !hadError {
+		t.Fatalf("hadError=false with an unknown pay_leg in the batch")
+	}
+	if len(outputs) != 2 || outputs[0].TaskID != "bad_pay_leg" || outputs[1].TaskID != fixture[0].TaskID {
+		t.Fatalf("outputs out of order: %+v", outputs)
+	}
+	if outputs[0].Error != "unknown pay_leg: LIBOR6M" {
+		t.Fatalf("bad task error %q", outputs[0].Error)
+	}
+	if outputs[1].Error != "" || outputs[1].SpreadBP == 0 || math.Abs(outputs[1].NPVResidual) > 1e-6 {
+		t.Fatalf("valid task output %+v", outputs[1])
+	}
+
+	// A single object still parses as one task, not an array.
+	single, err := json.Marshal(fixture[0])
+	if err != nil {
+		t.Fatalf("marshal single: %v", err)
+	}
+	if inputs, isArray, err := parseInputs(single); err != nil || isArray || len(inputs) != 1 {
+		t.Fatalf("single object: %d inputs, isArray=%v, err=%v", len(inputs), isArray, err)
+	}
+}
+
+func TestCalculateSpreads_CappedIterations(t *testing.T) {
+	raw, err := os.ReadFile("testdata/input.json")
+	if err != nil {
+		t.Fatalf("read testdata: %v", err)
+	}
+	var fixture []PricingInput
+	if err := json.Unmarshal(raw, &fixture); err != nil {
+		t.Fatalf("parse testdata: %v", err)
+	}
+
+	// No Newton step: the solver stops at its 0bp starting guess and the output says so.
+	capped := fixture[0]
+	zero := 0
+	capped.MaxIterations = &zero
+	outputs, hadError := calculateSpreads([]PricingInput{capped, fixture[0]})
+	if !hadError {
+		t.Fatalf("hadError=false for a capped run")
+	}
+	got, ref := outputs[0], outputs[1]
+	if !strings.Contains(got.Error, "did not converge") || got.TaskID != capped.TaskID {
+		t.Fatalf("capped output %+v, want a non-convergence error", got)
+	}
+	if got.SpreadBP != 0 || math.Abs(got.NPVResidual) < 1000 || got.EffectiveDate == "" {
+		t.Fatalf("capped output %+v, want spread 0 with a large npv_residual", got)
+	}
+	if ref.Error != "" || math.Abs(ref.NPVResidual) > 1e-6 {
+		t.Fatalf("uncapped output %+v", ref)
+	}
+}
@@ -0,0 +1,287 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/meenmo/molib/cmd/internal/parswap"
+	"github.com/meenmo/molib/swap"
+	"github.com/meenmo/molib/swap/market"
+)
+
+// Default grid: forward starts and swap tenors in years.
+var (
+	defaultForwardTenors = []int{0, 1, 2, 5}
+	defaultSwapTenors    = []int{1, 2, 5, 10, 30}
+)
+
+// MatrixInput defines the JSON input schema for a forward x tenor par rate matrix.
+type MatrixInput struct {
+	TaskID string `json:"task_id,omitempty"`
+
+	CurveDate         string             `json:"curve_date"`
+	TradeDate         string             `json:"trade_date"`
+	Notional          float64            `json:"notional"`
+	FloatingRateIndex string             `json:"floating_rate_index"`
+	CurveQuotes       map[string]float64 `json:"curve_quotes"`
+
+	// DiscountQuotes optionally supplies a separate discount curve, as in parswaprate.
+	// When omitted, the engine self-discounts on CurveQuotes.
+	DiscountQuotes map[string]float64 `json:"discount_quotes,omitempty"`
+
+	// ForwardTenors and SwapTenors (int years) set the matrix rows and columns.
+	// They default to 0,1,2,5 and 1,2,5,10,30.
+	ForwardTenors []int `json:"forward_tenors,omitempty"`
+	SwapTenors    []int `json:"swap_tenors,omitempty"`
+}
+
+// MatrixOutput defines the JSON output schema. ParRates[i][j] is the par fixed rate
+// (percent) of the swap starting ForwardTenors[i] years forward and running SwapTenors[j]
+// years.
+type MatrixOutput struct {
+	TaskID            string      `json:"task_id,omitempty"`
+	FloatingRateIndex string      `json:"floating_rate_index,omitempty"`
+	ForwardTenors     []int       `json:"forward_tenors,omitempty"`
+	SwapTenors        []int       `json:"swap_tenors,omitempty"`
+	ParRates          [][]float64 `json:"par_rates,omitempty"`
+	Error             string      `json:"error,omitempty"`
+}
+
+// selfDiscountedLegs resolves the fixed and floating legs for a par rate, as parswaprate
+// does (see parswap.SelfDiscountedLegs), naming the indices this command accepts on failure.
+func selfDiscountedLegs(index string) (fixedLeg, floatLeg market.LegConvention, err error) {
+	fixedLeg, floatLeg, err = parswap.SelfDiscountedLegs(index)
+	if errors.Is(err, parswap.ErrUnknownIndex) {
+		err = fmt.Errorf("%w (must be TONAR, ESTR, SOFR, SONIA, CORRA, or HIBOR3M)", err)
+	}
+	return fixedLeg, floatLeg, err
+}
+
+func main() {
+	inputPath := flag.String("input", "", "JSON input path (optional; if set, ignores stdin)")
+	help := flag.Bool("h", false, "Show help")
+	flag.BoolVar(help, "help", false, "Show help")
+	flag.Parse()
+
+	if *help {
+		usage()
+		return
+	}
+
+	path := strings.TrimSpace(*inputPath)
+	if path == "" {
+		if stat, err := os.Stdin.Stat(); err == nil && (stat.Mode()&os.ModeCharDevice) != 0 {
+			usage()
+			os.Exit(2)
+		}
+	}
+
+	inputBytes, err := readInput(path)
+	if err != nil {
+		writeError(fmt.Sprintf("failed to read input: %v", err))
+		return
+	}
+
+	inputs, isArray, err := parseInputs(inputBytes)
+	if err != nil {
+		writeError(fmt.Sprintf("failed to parse JSON input: %v", err))
+		return
+	}
+
+	outputs := make([]MatrixOutput, len(inputs))
+	hadError := false
+	for i, in := range inputs {
+		out, err := calculateMatrix(in)
+		if err != nil {
+			outputs[i] = MatrixOutput{TaskID: in.TaskID, Error: err.Error()}
+			hadError = true
+			continue
+		}
+		outputs[i] = *out
+	}
+
+	if isArray {
+		outputBytes, _ := json.Marshal(outputs)
+		fmt.Println(string(outputBytes))
+	} else {
+		outputBytes, _ := json.Marshal(outputs[0])
+		fmt.Println(string(outputBytes))
+	}
+
+	if hadError {
+		os.Exit(1)
+	}
+}
+
+func usage() {
+	fmt.Println("Usage:")
+	fmt.Println("  swapmatrix < input.json")
+	fmt.Println("  swapmatrix -input /path/to/input.json")
+	fmt.Println()
+	fmt.Println("Read JSON input, calculate a forward x tenor grid of par swap rates, output JSON to stdout.")
+	fmt.Println("Supported floating_rate_index: TONAR, ESTR, SOFR, SONIA, CORRA, HIBOR3M.")
+	fmt.Println()
+	fmt.Println("Fields:")
+	fmt.Println(`  curve_date, trade_date   (YYYY-MM-DD)`)
+	fmt.Println(`  notional                 (float)`)
+	fmt.Println(`  floating_rate_index      (string)`)
+	fmt.Println(`  curve_quotes             (tenor -> par-rate %, projection curve)`)
+	fmt.Println(`  discount_quotes          (optional; separate discount-curve par quotes)`)
+	fmt.Println(`  forward_tenors           (optional int years; default [0,1,2,5])`)
+	fmt.Println(`  swap_tenors              (optional int years; default [1,2,5,10,30])`)
+	fmt.Println()
+	fmt.Println("Output par_rates[i][j] is the par rate (%) for forward_tenors[i] x swap_tenors[j].")
+	fmt.Println()
+	fmt.Println("Example input:")
+	fmt.Println(`  {`)
+	fmt.Println(`    "curve_date": "2026-01-09",`)
+	fmt.Println(`    "trade_date": "2026-01-09",`)
+	fmt.Println(`    "notional": 1000000,`)
+	fmt.Println(`    "floating_rate_index": "SOFR",`)
+	fmt.Println(`    "curve_quotes": {"1Y": 3.48945, "2Y": 3.3717, ...}`)
+	fmt.Println(`  }`)
+}
+
+func readInput(path string) ([]byte, error) {
+	if path != "" {
+		return os.ReadFile(path)
+	}
+	return io.ReadAll(os.Stdin)
+}
+
+func parseInputs(raw []byte) ([]MatrixInput, bool, error) {
+	trimmed := bytes.TrimSpace(raw)
+	if len(trimmed) == 0 {
+		return nil, false, fmt.Errorf("empty input")
+	}
+
+	if trimmed[0] == '[' {
+		var inputs []MatrixInput
+		if err := json.Unmarshal(trimmed, &inputs); err != nil {
+			return nil, true, err
+		}
+		if len(inputs) == 0 {
+			return nil, true, fmt.Errorf("empty input array")
+		}
+		return inputs, true, nil
+	}
+
+	var input MatrixInput
+	if err := json.Unmarshal(trimmed, &input); err != nil {
+		return nil, false, err
+	}
+	return []MatrixInput{input}, false, nil
+}
+
+func writeError(msg string) {
+	output := MatrixOutput{Error: msg}
+	outputBytes, _ := json.Marshal(output)
+	fmt.Println(string(outputBytes))
+	os.Exit(1)
+}
+
+// calculateMatrix solves the par fixed rate of every forward x tenor swap. Cells are
+// independent and are fanned out across CPUs; any failing cell fails the matrix.
+func calculateMatrix(input MatrixInput) (*MatrixOutput, error) {
+	curveDate, err := time.Parse("2006-01-02", input.CurveDate)
+	if err != nil {
+		return nil, fmt.Errorf("invalid curve_date: %v", err)
+	}
+	tradeDate, err := time.Parse("2006-01-02", input.TradeDate)
+	if err != nil {
+		return nil, fmt.Errorf("invalid trade_date: %v", err)
+	}
+
+	fixedLeg, floatLeg, err := selfDiscountedLegs(input.FloatingRateIndex)
+	if err != nil {
+		return nil, err
+	}
+	if len(input.CurveQuotes) == 0 {
+		return nil, fmt.Errorf("curve_quotes is required")
+	}
+
+	forwards, tenors := input.ForwardTenors, input.SwapTenors
+	if len(forwards) == 0 {
+		forwards = defaultForwardTenors
+	}
+	if len(tenors) == 0 {
+		tenors = defaultSwapTenors
+	}
+	for _, f := range forwards {
+		if f < 0 {
+			return nil, fmt.Errorf("forward_tenors must be >= 0, got %d", f)
+		}
+	}
+	for _, n := range tenors {
+		if n <= 0 {
+			return nil, fmt.Errorf("swap_tenors must be > 0, got %d", n)
+		}
+	}
+
+	base := parswap.Params(curveDate, tradeDate, input.Notional, fixedLeg, floatLeg, input.CurveQuotes, input.DiscountQuotes)
+
+	rates := make([][]float64, len(forwards))
+	for i := range rates {
+		rates[i] = make([]float64, len(tenors))
+	}
+	errs := make([]error, len(forwards)*len(tenors))
+
+	jobs := make(chan int, len(errs))
+	var wg sync.WaitGroup
+	for w := 0; w < min(runtime.NumCPU(), len(errs)); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for k := range jobs {
+				i, j := k/len(tenors), k%len(tenors)
+				params := base
+				params.ForwardTenorYears = forwards[i]
+				params.SwapTenorYears = tenors[j]
+				rates[i][j], errs[k] = parRatePct(params)
+				if errs[k] != nil {
+					errs[k] = fmt.Errorf("%dYx%dY: %v", forwards[i], tenors[j], errs[k])
+				}
+			}
+		}()
+	}
+	for k := range errs {
+		jobs <- k
+	}
+	close(jobs)
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &MatrixOutput{
+		TaskID:            input.TaskID,
+		FloatingRateIndex: input.FloatingRateIndex,
+		ForwardTenors:     forwards,
+		SwapTenors:        tenors,
+		ParRates:          rates,
+	}, nil
+}
+
+// parRatePct builds the swap and solves its fixed leg's par rate, in percent.
+func parRatePct(params swap.InterestRateSwapParams) (float64, error) {
+	trade, err := swap.InterestRateSwap(params)
+	if err != nil {
+		return 0, fmt.Errorf("failed to build swap: %v", err)
+	}
+	spreadBP, _, err := trade.SolveParSpread(swap.SpreadTargetPayLeg)
+	if err != nil {
+		return 0, fmt.Errorf("failed to solve par rate: %v", err)
+	}
+	return spreadBP / 100.0, nil
+}
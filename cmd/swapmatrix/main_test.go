@@ -0,0 +1,60 @@
+package main
+
+import (
+	"encoding/json"
+	"math"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/meenmo/molib/cmd/internal/parswap"
+	"github.com/meenmo/molib/swap"
+)
+
+func TestCalculateMatrix_SpotTenYearMatchesParSwapRate(t *testing.T) {
+	raw, err := os.ReadFile("testdata/input.json")
+	if err != nil {
+		t.Fatalf("read testdata: %v", err)
+	}
+	var input MatrixInput
+	if err := json.Unmarshal(raw, &input); err != nil {
+		t.Fatalf("parse testdata: %v", err)
+	}
+
+	out, err := calculateMatrix(input)
+	if err != nil {
+		t.Fatalf("calculateMatrix: %v", err)
+	}
+	if len(out.ParRates) != len(defaultForwardTenors) || len(out.ParRates[0]) != len(defaultSwapTenors) {
+		t.Fatalf("matrix is %dx%d, want %dx%d", len(out.ParRates), len(out.ParRates[0]), len(defaultForwardTenors), len(defaultSwapTenors))
+	}
+	if out.ForwardTenors[0] != 0 || out.SwapTenors[3] != 10 {
+		t.Fatalf("unexpected axes %v x %v", out.ForwardTenors, out.SwapTenors)
+	}
+
+	// The spot 10Y swap parswaprate prices for the same inputs: its legs and parameters
+	// come from the shared parswap package, solved as calculateParRate solves them.
+	curveDate, _ := time.Parse("2006-01-02", input.CurveDate)
+	tradeDate, _ := time.Parse("2006-01-02", input.TradeDate)
+	fixedLeg, floatLeg, err := parswap.SelfDiscountedLegs(input.FloatingRateIndex)
+	if err != nil {
+		t.Fatalf("SelfDiscountedLegs: %v", err)
+	}
+	params := parswap.Params(curveDate, tradeDate, input.Notional, fixedLeg, floatLeg, input.CurveQuotes, input.DiscountQuotes)
+	params.SwapTenorYears = 10
+	trade, err := swap.InterestRateSwap(params)
+	if err != nil {
+		t.Fatalf("InterestRateSwap: %v", err)
+	}
+	spreadBP, _, err := trade.SolveParSpread(swap.SpreadTargetPayLeg)
+	if err != nil {
+		t.Fatalf("SolveParSpread: %v", err)
+	}
+	if got, want := out.ParRates[0][3], spreadBP/100.0; math.Abs(got-want) > 1e-12 {
+		t.Fatalf("0Yx10Y cell %.12f%%, spot 10Y par rate %.12f%%", got, want)
+	}
+	// Forward-starting cells differ from spot on an upward-sloping curve.
+	if out.ParRates[3][3] == out.ParRates[0][3] {
+		t.Fatalf("5Yx10Y cell equals 0Yx10Y: %.6f%%", out.ParRates[3][3])
+	}
+}
@@ -0,0 +1,176 @@
+// Package daycount implements accrual day count conventions and a registry resolving
+// convention names (as used by utils.YearFraction and market.DayCount) to them.
+package daycount
+
+import (
+	"maps"
+	"math"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/meenmo/molib/calendar"
+)
+
+// Convention computes the year fraction of an accrual period.
+type Convention interface {
+	// YearFraction returns the year fraction from start to end, negative when end is
+	// before start. refStart and refEnd bound the regular coupon period the accrual
+	// belongs to; only ActActICMA reads them, and zero values are allowed elsewhere.
+	YearFraction(start, end, refStart, refEnd time.Time) float64
+}
+
+type (
+	act360         struct{}
+	act365F        struct{}
+	actActISDA     struct{}
+	actActICMA     struct{}
+	thirty360US    struct{}
+	thirty360E     struct{}
+	thirtyE360ISDA struct{}
+)
+
+// Business252 is Business/252 on Calendar: business days in (start, end] over 252, the
+// BRL convention. An empty Calendar counts weekdays only.
+type Business252 struct {
+	Calendar calendar.CalendarID
+}
+
+var (
+	// Act360 is actual days over 360.
+	Act360 Convention = act360{}
+	// Act365F is actual days over 365.
+	Act365F Convention = act365F{}
+	// ActActISDA splits the period at year ends: days in each calendar year over that
+	// year's length (365 or 366).
+	ActActISDA Convention = actActISDA{}
+	// ActActICMA is actual days over the days in the reference period, times the
+	// reference period's length in years (1/frequency). Without a reference period the
+	// year ending at end is used.
+	ActActICMA Convention = actActICMA{}
+	// Thirty360US is 30/360 bond basis: D1 = 31 becomes 30, and D2 = 31 becomes 30 when
+	// D1 is 30 or 31.
+	Thirty360US Convention = thirty360US{}
+	// Thirty360E is 30E/360 (Eurobond basis): D1 and D2 are capped at 30.
+	Thirty360E Convention = thirty360E{}
+	// ThirtyE360ISDA is 30E/360 ISDA: a month-end D1 or D2 (February included) becomes
+	// 30. The exception for a February maturity date is not applied.
+	ThirtyE360ISDA Convention = thirtyE360ISDA{}
+	// Bus252 is Business252 on weekdays only; use Business252{Calendar: cal} for holidays.
+	Bus252 Convention = Business252{}
+)
+
+// registry maps names to conventions. Register swaps in an updated copy, so Lookup reads
+// it without locking.
+var (
+	registryMu sync.Mutex
+	registry   atomic.Pointer[map[string]Convention]
+)
+
+func init() {
+	registry.Store(&map[string]Convention{
+		"ACT/360":      Act360,
+		"ACT/365F":     Act365F,
+		"ACT/365":      Act365F,
+		"ACT/ACT ISDA": ActActISDA,
+		"ACT/ACT ICMA": ActActICMA,
+		"30U/360":      Thirty360US,
+		"30E/360":      Thirty360E,
+		// "30/360" has always meant the EUR swap fixed-leg basis here; keep it on 30E/360.
+		"30/360":       Thirty360E,
+		"30E/360 ISDA": ThirtyE360ISDA,
+		"BUS/252":      Bus252,
+	})
+}
+
+// Register makes c available under name, replacing any convention already registered
+// under it.
+func Register(name string, c Convention) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	next := maps.Clone(*registry.Load())
+	next[name] = c
+	registry.Store(&next)
+}
+
+// Lookup returns the convention registered under name.
+func Lookup(name string) (Convention, bool) {
+	c, ok := (*registry.Load())[name]
+	return c, ok
+}
+
+// days returns the actual days from start to end, fractional for intraday times.
+func days(start, end time.Time) float64 {
+	return end.Sub(start).Hours() / 24
+}
+
+func (act360) YearFraction(start, end, _, _ time.Time) float64 {
+	return days(start, end) / 360.0
+}
+
+func (act365F) YearFraction(start, end, _, _ time.Time) float64 {
+	return days(start, end) / 365.0
+}
+
+func (actActISDA) YearFraction(start, end, _, _ time.Time) float64 {
+	if end.Before(start) {
+		return -actActISDA{}.YearFraction(end, start, time.Time{}, time.Time{})
+	}
+	yf := 0.0
+	for start.Year() < end.Year() {
+		next := time.Date(start.Year()+1, 1, 1, 0, 0, 0, 0, start.Location())
+		yf += days(start, next) / daysInYear(start.Year())
+		start = next
+	}
+	return yf + days(start, end)/daysInYear(start.Year())
+}
+
+func daysInYear(year int) float64 {
+	if time.Date(year, 12, 31, 0, 0, 0, 0, time.UTC).YearDay() == 366 {
+		return 366
+	}
+	return 365
+}
+
+func (actActICMA) YearFraction(start, end, refStart, refEnd time.Time) float64 {
+	if refStart.IsZero() || refEnd.IsZero() {
+		refStart, refEnd = end.AddDate(-1, 0, 0), end
+	}
+	refDays := days(refStart, refEnd)
+	// Regular periods are whole months long; recover the count from the day count.
+	months := math.Round(refDays / (365.25 / 12))
+	return days(start, end) / refDays * months / 12.0
+}
+
+func thirty360(y1, m1, d1, y2, m2, d2 int) float64 {
+	return float64(360*(y2-y1)+30*(m2-m1)+(d2-d1)) / 360.0
+}
+
+func (thirty360US) YearFraction(start, end, _, _ time.Time) float64 {
+	d1, d2 := start.Day(), end.Day()
+	if d1 == 31 {
+		d1 = 30
+	}
+	if d2 == 31 && d1 == 30 {
+		d2 = 30
+	}
+	return thirty360(start.Year(), int(start.Month()), d1, end.Year(), int(end.Month()), d2)
+}
+
+func (thirty360E) YearFraction(start, end, _, _ time.Time) float64 {
+	return thirty360(start.Year(), int(start.Month()), min(start.Day(), 30), end.Year(), int(end.Month()), min(end.Day(), 30))
+}
+
+func (thirtyE360ISDA) YearFraction(start, end, _, _ time.Time) float64 {
+	day := func(t time.Time) int {
+		if t.AddDate(0, 0, 1).Month() != t.Month() {
+			return 30
+		}
+		return t.Day()
+	}
+	return thirty360(start.Year(), int(start.Month()), day(start), end.Year(), int(end.Month()), day(end))
+}
+
+func (b Business252) YearFraction(start, end, _, _ time.Time) float64 {
+	return float64(calendar.BusinessDaysBetween(b.Calendar, start, end)) / 252.0
+}
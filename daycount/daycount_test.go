@@ -0,0 +1,92 @@
+package daycount_test
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/meenmo/molib/daycount"
+	"github.com/meenmo/molib/utils"
+)
+
+func date(y int, m time.Month, d int) time.Time {
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+func TestConventions_AcrossLeapYear(t *testing.T) {
+	t.Parallel()
+
+	// 2023-11-15 to 2024-05-15: 47 days in 2023 and 135 in leap 2024.
+	start, end := date(2023, 11, 15), date(2024, 5, 15)
+	for _, tc := range []struct {
+		name       string
+		dc         daycount.Convention
+		start, end time.Time
+		refStart   time.Time
+		refEnd     time.Time
+		want       float64
+	}{
+		{"ACT/360", daycount.Act360, start, end, time.Time{}, time.Time{}, 182.0 / 360},
+		{"ACT/365F", daycount.Act365F, start, end, time.Time{}, time.Time{}, 182.0 / 365},
+		{"ACT/ACT ISDA", daycount.ActActISDA, start, end, time.Time{}, time.Time{}, 47.0/365 + 135.0/366},
+		// A full semi-annual coupon period accrues exactly half a year; a partial one its
+		// share of the period's 182 days.
+		{"ACT/ACT ICMA", daycount.ActActICMA, start, end, start, end, 0.5},
+		{"ACT/ACT ICMA partial", daycount.ActActICMA, date(2024, 2, 10), end, start, end, 95.0 / 182 * 0.5},
+		// Month ends into leap February: only 30E/360 ISDA treats Feb 29 as day 30.
+		{"30U/360 Feb", daycount.Thirty360US, date(2023, 8, 31), date(2024, 2, 29), time.Time{}, time.Time{}, 179.0 / 360},
+		{"30E/360 Feb", daycount.Thirty360E, date(2023, 8, 31), date(2024, 2, 29), time.Time{}, time.Time{}, 179.0 / 360},
+		{"30E/360 ISDA Feb", daycount.ThirtyE360ISDA, date(2023, 8, 31), date(2024, 2, 29), time.Time{}, time.Time{}, 180.0 / 360},
+		// An end on the 31st: 30U/360 keeps it when D1 is before the 30th.
+		{"30U/360 Mar", daycount.Thirty360US, date(2023, 12, 15), date(2024, 3, 31), time.Time{}, time.Time{}, 106.0 / 360},
+		{"30E/360 Mar", daycount.Thirty360E, date(2023, 12, 15), date(2024, 3, 31), time.Time{}, time.Time{}, 105.0 / 360},
+		{"30E/360 ISDA Mar", daycount.ThirtyE360ISDA, date(2023, 12, 15), date(2024, 3, 31), time.Time{}, time.Time{}, 105.0 / 360},
+		// February 2024 has 21 weekdays.
+		{"BUS/252", daycount.Bus252, date(2024, 1, 31), date(2024, 2, 29), time.Time{}, time.Time{}, 21.0 / 252},
+	} {
+		got := tc.dc.YearFraction(tc.start, tc.end, tc.refStart, tc.refEnd)
+		if math.Abs(got-tc.want) > 1e-15 {
+			t.Errorf("%s: %.15f, want %.15f", tc.name, got, tc.want)
+		}
+		// Reversing an actual-day period negates it; 30/360 day rules are not symmetric.
+		if back := tc.dc.YearFraction(tc.end, tc.start, tc.refStart, tc.refEnd); !strings.HasPrefix(tc.name, "30") && math.Abs(back+got) > 1e-15 {
+			t.Errorf("%s: reversed period gives %.15f, want %.15f", tc.name, back, -got)
+		}
+	}
+}
+
+func TestLookup_UtilsYearFractionDelegates(t *testing.T) {
+	t.Parallel()
+
+	start, end := date(2023, 11, 15), date(2024, 5, 31)
+	for name, dc := range map[string]daycount.Convention{
+		"ACT/360":      daycount.Act360,
+		"ACT/365F":     daycount.Act365F,
+		"ACT/ACT ISDA": daycount.ActActISDA,
+		"30U/360":      daycount.Thirty360US,
+		"30E/360":      daycount.Thirty360E,
+		"30/360":       daycount.Thirty360E,
+		"30E/360 ISDA": daycount.ThirtyE360ISDA,
+		"BUS/252":      daycount.Bus252,
+	} {
+		got, ok := daycount.Lookup(name)
+		if !ok || got != dc {
+			t.Fatalf("Lookup(%q) = %v, %v", name, got, ok)
+		}
+		if yf, want := utils.YearFraction(start, end, name), dc.YearFraction(start, end, time.Time{}, time.Time{}); yf != want {
+			t.Fatalf("utils.YearFraction(%q) = %.15f, want %.15f", name, yf, want)
+		}
+	}
+	if _, ok := daycount.Lookup("ACT/ACT AFB"); ok {
+		t.Fatalf("unexpected convention registered under ACT/ACT AFB")
+	}
+	if got, want := utils.YearFraction(start, end, "ACT/ACT AFB"), daycount.Act365F.YearFraction(start, end, time.Time{}, time.Time{}); got != want {
+		t.Fatalf("unknown convention gives %.15f, want ACT/365F %.15f", got, want)
+	}
+
+	daycount.Register("BUS/252 KRW", daycount.Business252{Calendar: "KOR"})
+	if dc, ok := daycount.Lookup("BUS/252 KRW"); !ok || dc != (daycount.Business252{Calendar: "KOR"}) {
+		t.Fatalf("Register: Lookup returned %v, %v", dc, ok)
+	}
+}
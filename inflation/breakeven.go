@@ -0,0 +1,18 @@
+// Package inflation provides inflation metrics derived from nominal and real curves.
+package inflation
+
+import (
+	"time"
+
+	"github.com/meenmo/molib/swap/curve"
+)
+
+// Breakeven returns the breakeven inflation rate (percent, continuously compounded) to
+// horizon implied by a nominal and a real zero curve: the nominal zero rate minus the
+// real zero rate. With continuous compounding this is the exact Fisher relation.
+func Breakeven(nominal, real *curve.Curve, horizon time.Time) float64 {
+	if nominal == nil || real == nil {
+		panic("Breakeven: nil curve")
+	}
+	return nominal.ZeroRateAt(horizon) - real.ZeroRateAt(horizon)
+}
@@ -0,0 +1,37 @@
+package inflation_test
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/meenmo/molib/calendar"
+	"github.com/meenmo/molib/inflation"
+	"github.com/meenmo/molib/swap/curve"
+	"github.com/meenmo/molib/utils"
+)
+
+// flatCurve returns a curve with a flat continuously-compounded zero rate (percent).
+func flatCurve(settlement time.Time, zeroPct float64) *curve.Curve {
+	dfs := make(map[time.Time]float64)
+	for y := 1; y <= 30; y++ {
+		d := settlement.AddDate(y, 0, 0)
+		dfs[d] = math.Exp(-zeroPct / 100 * utils.YearFraction(settlement, d, "ACT/365F"))
+	}
+	return curve.NewCurveFromDFs(settlement, dfs, calendar.FD, 0)
+}
+
+func TestBreakeven(t *testing.T) {
+	t.Parallel()
+
+	settlement := time.Date(2026, 3, 12, 0, 0, 0, 0, time.UTC)
+	horizon := time.Date(2036, 3, 12, 0, 0, 0, 0, time.UTC)
+	nominal := flatCurve(settlement, 4.1)
+
+	if be := inflation.Breakeven(nominal, nominal, horizon); math.Abs(be) > 1e-10 {
+		t.Fatalf("nominal == real should give zero breakeven, got %.12f", be)
+	}
+	if be := inflation.Breakeven(nominal, flatCurve(settlement, 2.1), horizon); math.Abs(be-2.0) > 1e-8 {
+		t.Fatalf("2%% nominal-real spread should give 2%% breakeven, got %.10f", be)
+	}
+}
@@ -28,7 +28,7 @@ type OISPreset struct {
 	FloatLeg market.LegConvention
 }
 
-// Preset leg conventions for EUR and JPY.
+// Preset leg conventions by index.
 var (
 	SOFRFixed = market.LegConvention{
 		LegType:               market.LegFixed,
@@ -118,6 +118,36 @@ var (
 		ScheduleDirection:       market.ScheduleBackward,
 	}
 
+	CORRAFixed = market.LegConvention{
+		LegType:               market.LegFixed,
+		DayCount:              market.Act365F,
+		PayFrequency:          market.FreqAnnual,
+		FixingLagDays:         0,
+		PayDelayDays:          1,
+		BusinessDayAdjustment: market.ModifiedFollowing,
+		RollConvention:        market.BackwardEOM,
+		Calendar:              calendar.CA,
+		ScheduleDirection:     market.ScheduleBackward,
+	}
+
+	CORRAFloating = market.LegConvention{
+		LegType:                 market.LegFloating,
+		ReferenceIndex:          market.CORRA,
+		DayCount:                market.Act365F,
+		ResetFrequency:          market.FreqDaily,
+		PayFrequency:            market.FreqAnnual,
+		FixingLagDays:           0,
+		PayDelayDays:            1,
+		BusinessDayAdjustment:   market.ModifiedFollowing,
+		RollConvention:          market.BackwardEOM,
+		Calendar:                calendar.CA,
+		ResetPosition:           market.ResetInArrears,
+		RateCutoffDays:          1,
+		IncludeInitialPrincipal: true,
+		IncludeFinalPrincipal:   true,
+		ScheduleDirection:       market.ScheduleBackward,
+	}
+
 	EURIBORFixed = market.LegConvention{
 		LegType:               market.LegFixed,
 		DayCount:              market.Act360,
@@ -350,9 +380,21 @@ var (
 		FloatLeg: TONARFloating,
 	}
 
-	// JPY OIS: fixed vs TONAR.
+	// GBP OIS: fixed vs SONIA.
 	OISSONIA = OISPreset{
 		FixedLeg: SONIAFixed,
 		FloatLeg: SONIAFloating,
 	}
+
+	// USD OIS: fixed vs SOFR.
+	OISSOFR = OISPreset{
+		FixedLeg: SOFRFixed,
+		FloatLeg: SOFRFloating,
+	}
+
+	// CAD OIS: fixed vs CORRA.
+	OISCORRA = OISPreset{
+		FixedLeg: CORRAFixed,
+		FloatLeg: CORRAFloating,
+	}
 )
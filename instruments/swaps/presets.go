@@ -0,0 +1,161 @@
+package swaps
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/meenmo/molib/calendar"
+	"github.com/meenmo/molib/swap/market"
+)
+
+// PresetInfo describes an exported leg convention preset for discovery and input validation.
+type PresetInfo struct {
+	Name           string
+	Currency       string
+	LegType        market.LegType
+	ReferenceIndex market.ReferenceIndex
+	DayCount       market.DayCount
+	ResetFrequency market.Frequency
+	PayFrequency   market.Frequency
+	Calendar       calendar.CalendarID
+	Convention     market.LegConvention
+}
+
+// currencyByCalendar maps a leg's payment calendar to its ISO currency code.
+var currencyByCalendar = map[calendar.CalendarID]string{
+	calendar.TARGET: "EUR",
+	calendar.JP:     "JPY",
+	calendar.FD:     "USD",
+	calendar.GT:     "USD",
+	calendar.EN:     "GBP",
+	calendar.HK:     "HKD",
+	calendar.KR:     "KRW",
+	calendar.CA:     "CAD",
+}
+
+// SupportedPresets lists every exported leg convention preset in this package.
+func SupportedPresets() []PresetInfo {
+	legs := []struct {
+		name string
+		leg  market.LegConvention
+	}{
+		{"SOFRFixed", SOFRFixed},
+		{"SOFRFloating", SOFRFloating},
+		{"ESTRFixed", ESTRFixed},
+		{"ESTRFloating", ESTRFloating},
+		{"SONIAFixed", SONIAFixed},
+		{"SONIAFloating", SONIAFloating},
+		{"CORRAFixed", CORRAFixed},
+		{"CORRAFloating", CORRAFloating},
+		{"EURIBORFixed", EURIBORFixed},
+		{"EURIBOR3MFloating", EURIBOR3MFloating},
+		{"EURIBOR6MFloating", EURIBOR6MFloating},
+		{"TONARFixed", TONARFixed},
+		{"TONARFloating", TONARFloating},
+		{"TIBORFixed", TIBORFixed},
+		{"TIBOR3MFloating", TIBOR3MFloating},
+		{"TIBOR6MFloating", TIBOR6MFloating},
+		{"HIBOR3MFloating", HIBOR3MFloating},
+		{"HIBOR3MFixed", HIBOR3MFixed},
+		{"KRXCD91DFixed", KRXCD91DFixed},
+		{"KRXCD91DFloating", KRXCD91DFloating},
+	}
+
+	out := make([]PresetInfo, 0, len(legs))
+	for _, l := range legs {
+		out = append(out, PresetInfo{
+			Name:           l.name,
+			Currency:       currencyByCalendar[l.leg.Calendar],
+			LegType:        l.leg.LegType,
+			ReferenceIndex: l.leg.ReferenceIndex,
+			DayCount:       l.leg.DayCount,
+			ResetFrequency: l.leg.ResetFrequency,
+			PayFrequency:   l.leg.PayFrequency,
+			Calendar:       l.leg.Calendar,
+			Convention:     l.leg,
+		})
+	}
+	return out
+}
+
+// floatingAliases maps bare index names (as used in CLI inputs and fixtures) to
+// their floating leg preset.
+var floatingAliases = map[string]market.LegConvention{
+	"SOFR":      SOFRFloating,
+	"ESTR":      ESTRFloating,
+	"SONIA":     SONIAFloating,
+	"CORRA":     CORRAFloating,
+	"EURIBOR3M": EURIBOR3MFloating,
+	"EURIBOR6M": EURIBOR6MFloating,
+	"TONAR":     TONARFloating,
+	"TIBOR3M":   TIBOR3MFloating,
+	"TIBOR6M":   TIBOR6MFloating,
+	"HIBOR3M":   HIBOR3MFloating,
+	"KRXCD91D":  KRXCD91DFloating,
+	"CD91D":     KRXCD91DFloating,
+}
+
+// LegByName resolves a leg convention by preset name (e.g. "EURIBOR6MFloating",
+// "TONARFixed") or by bare index name (e.g. "ESTR", "TIBOR3M"), which resolves to
+// the floating leg. Surrounding whitespace is ignored.
+func LegByName(name string) (market.LegConvention, error) {
+	name = strings.TrimSpace(name)
+	if leg, ok := floatingAliases[name]; ok {
+		return leg, nil
+	}
+	for _, p := range SupportedPresets() {
+		if p.Name == name {
+			return p.Convention, nil
+		}
+	}
+	return market.LegConvention{}, fmt.Errorf("LegByName: unknown leg %q", name)
+}
+
+// DefaultFixedLeg returns the standard fixed leg quoted against floatLeg.
+//
+// Overnight legs map to their OIS fixed preset (e.g. SOFR: ACT/360 annual). IBOR legs
+// map by currency, with the day count and frequency from market.IndexDefaults: EUR
+// 30E/360 annual, JPY ACT/365F semi-annual, HKD and KRW ACT/365F quarterly. IBOR fixed
+// legs take the float leg's calendar, roll and business-day adjustment and are generated
+// backward from maturity, matching Bloomberg stubs.
+func DefaultFixedLeg(floatLeg market.LegConvention) (market.LegConvention, error) {
+	if market.IsOvernight(floatLeg.ReferenceIndex) {
+		switch floatLeg.ReferenceIndex {
+		case market.ESTR:
+			return ESTRFixed, nil
+		case market.SOFR, market.EFFR:
+			return SOFRFixed, nil
+		case market.TONAR:
+			return TONARFixed, nil
+		case market.SONIA:
+			return SONIAFixed, nil
+		case market.CORRA:
+			return CORRAFixed, nil
+		}
+		return market.LegConvention{}, fmt.Errorf("DefaultFixedLeg: no fixed leg for overnight index %s", floatLeg.ReferenceIndex)
+	}
+
+	var fixed market.LegConvention
+	switch floatLeg.Calendar {
+	case calendar.TARGET:
+		fixed = EURIBORFixed
+	case calendar.JP:
+		fixed = TIBORFixed
+	case calendar.HK:
+		fixed = HIBOR3MFixed
+	case calendar.KR:
+		// KRX CD swaps generate forward from the effective date.
+		return KRXCD91DFixed, nil
+	default:
+		return market.LegConvention{}, fmt.Errorf("DefaultFixedLeg: unsupported float leg calendar %q", floatLeg.Calendar)
+	}
+	if profile, err := market.IndexDefaults(string(floatLeg.ReferenceIndex)); err == nil {
+		fixed.DayCount = profile.FixedDayCount
+		fixed.PayFrequency = profile.FixedFrequency
+	}
+	fixed.Calendar = floatLeg.Calendar
+	fixed.RollConvention = floatLeg.RollConvention
+	fixed.BusinessDayAdjustment = floatLeg.BusinessDayAdjustment
+	fixed.ScheduleDirection = market.ScheduleBackward
+	return fixed, nil
+}
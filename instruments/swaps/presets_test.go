@@ -0,0 +1,171 @@
+package swaps_test
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"reflect"
+	"testing"
+
+	"github.com/meenmo/molib/calendar"
+	"github.com/meenmo/molib/instruments/swaps"
+	"github.com/meenmo/molib/swap/market"
+)
+
+// legPresetNames parses conventions.go and returns every exported
+// market.LegConvention variable declared there.
+func legPresetNames(t *testing.T) []string {
+	t.Helper()
+
+	file, err := parser.ParseFile(token.NewFileSet(), "conventions.go", nil, 0)
+	if err != nil {
+		t.Fatalf("parse conventions.go: %v", err)
+	}
+
+	var names []string
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.VAR {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			vs := spec.(*ast.ValueSpec)
+			for i, name := range vs.Names {
+				if !name.IsExported() || i >= len(vs.Values) {
+					continue
+				}
+				lit, ok := vs.Values[i].(*ast.CompositeLit)
+				if !ok {
+					continue
+				}
+				sel, ok := lit.Type.(*ast.SelectorExpr)
+				if ok && sel.Sel.Name == "LegConvention" {
+					names = append(names, name.Name)
+				}
+			}
+		}
+	}
+	return names
+}
+
+func TestSupportedPresets_CoversEveryLegPreset(t *testing.T) {
+	t.Parallel()
+
+	presets := swaps.SupportedPresets()
+	byName := make(map[string]swaps.PresetInfo, len(presets))
+	for _, p := range presets {
+		if _, dup := byName[p.Name]; dup {
+			t.Fatalf("duplicate preset %q", p.Name)
+		}
+		byName[p.Name] = p
+	}
+
+	names := legPresetNames(t)
+	if len(names) == 0 {
+		t.Fatalf("no leg presets found in conventions.go")
+	}
+	for _, name := range names {
+		p, ok := byName[name]
+		if !ok {
+			t.Errorf("preset %s missing from SupportedPresets", name)
+			continue
+		}
+		if p.Calendar == "" {
+			t.Errorf("preset %s has empty calendar", name)
+		}
+		if p.Currency == "" {
+			t.Errorf("preset %s has no currency for calendar %q", name, p.Calendar)
+		}
+	}
+	if len(presets) != len(names) {
+		t.Errorf("SupportedPresets has %d entries, conventions.go declares %d", len(presets), len(names))
+	}
+}
+
+func TestLegByName(t *testing.T) {
+	t.Parallel()
+
+	aliases := map[string]market.LegConvention{
+		"SOFR":      swaps.SOFRFloating,
+		"ESTR":      swaps.ESTRFloating,
+		"SONIA":     swaps.SONIAFloating,
+		"CORRA":     swaps.CORRAFloating,
+		"EURIBOR3M": swaps.EURIBOR3MFloating,
+		"EURIBOR6M": swaps.EURIBOR6MFloating,
+		"TONAR":     swaps.TONARFloating,
+		"TIBOR3M":   swaps.TIBOR3MFloating,
+		"TIBOR6M":   swaps.TIBOR6MFloating,
+		"HIBOR3M":   swaps.HIBOR3MFloating,
+		"KRXCD91D":  swaps.KRXCD91DFloating,
+		" ESTR\t":   swaps.ESTRFloating,
+	}
+	for name, want := range aliases {
+		got, err := swaps.LegByName(name)
+		if err != nil {
+			t.Errorf("LegByName(%q): %v", name, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("LegByName(%q) resolved to %s, want %s", name, got.ReferenceIndex, want.ReferenceIndex)
+		}
+	}
+
+	for _, p := range swaps.SupportedPresets() {
+		got, err := swaps.LegByName(p.Name)
+		if err != nil {
+			t.Errorf("LegByName(%q): %v", p.Name, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, p.Convention) {
+			t.Errorf("LegByName(%q) does not match its preset", p.Name)
+		}
+	}
+
+	if _, err := swaps.LegByName("LIBOR3M"); err == nil {
+		t.Fatalf("expected error for unknown leg")
+	}
+}
+
+func TestDefaultFixedLeg(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		float     market.LegConvention
+		dayCount  market.DayCount
+		frequency market.Frequency
+	}{
+		{swaps.EURIBOR6MFloating, "30E/360", market.FreqAnnual},
+		{swaps.EURIBOR3MFloating, "30E/360", market.FreqAnnual},
+		{swaps.ESTRFloating, swaps.ESTRFixed.DayCount, swaps.ESTRFixed.PayFrequency},
+		{swaps.TIBOR6MFloating, market.Act365F, market.FreqSemi},
+		{swaps.TONARFloating, swaps.TONARFixed.DayCount, swaps.TONARFixed.PayFrequency},
+		{swaps.SOFRFloating, market.Act360, market.FreqAnnual},
+		{swaps.SONIAFloating, swaps.SONIAFixed.DayCount, swaps.SONIAFixed.PayFrequency},
+		{swaps.CORRAFloating, market.Act365F, market.FreqAnnual},
+		{swaps.HIBOR3MFloating, market.Act365F, market.FreqQuarterly},
+		{swaps.KRXCD91DFloating, market.Act365F, market.FreqQuarterly},
+	}
+	for _, tc := range cases {
+		got, err := swaps.DefaultFixedLeg(tc.float)
+		if err != nil {
+			t.Errorf("DefaultFixedLeg(%s): %v", tc.float.ReferenceIndex, err)
+			continue
+		}
+		if got.LegType != market.LegFixed {
+			t.Errorf("DefaultFixedLeg(%s) leg type %s, want FIXED", tc.float.ReferenceIndex, got.LegType)
+		}
+		if got.DayCount != tc.dayCount || got.PayFrequency != tc.frequency {
+			t.Errorf("DefaultFixedLeg(%s) = %s/%dM, want %s/%dM",
+				tc.float.ReferenceIndex, got.DayCount, got.PayFrequency, tc.dayCount, tc.frequency)
+		}
+		if got.Calendar != tc.float.Calendar {
+			t.Errorf("DefaultFixedLeg(%s) calendar %s, want %s", tc.float.ReferenceIndex, got.Calendar, tc.float.Calendar)
+		}
+	}
+
+	unsupported := swaps.EURIBOR6MFloating
+	unsupported.Calendar = calendar.EN
+	if _, err := swaps.DefaultFixedLeg(unsupported); err == nil {
+		t.Fatalf("expected error for unsupported calendar")
+	}
+}
@@ -2,11 +2,12 @@ package swap
 
 import (
 	"fmt"
+	"maps"
 	"time"
 
-	"github.com/meenmo/molib/calendar"
 	"github.com/meenmo/molib/swap/curve"
 	"github.com/meenmo/molib/swap/market"
+	"github.com/meenmo/molib/utils"
 )
 
 // DataSource identifies the source of market conventions and quotes.
@@ -37,6 +38,16 @@ const (
 	ClearingHouseEUREX ClearingHouse = "EUREX"
 )
 
+// Position is the side of the fixed leg taken in a fixed/floating swap.
+type Position string
+
+const (
+	// PositionPay pays the fixed leg and receives the floating leg (payer swap).
+	PositionPay Position = "PAY"
+	// PositionReceive receives the fixed leg and pays the floating leg (receiver swap).
+	PositionReceive Position = "REC"
+)
+
 // InterestRateSwapParams defines inputs to construct a generic two-leg interest rate swap trade.
 //
 // This builder is clearing-house-aware for date conventions (e.g., spot lag), but pricing is driven by:
@@ -59,6 +70,12 @@ type InterestRateSwapParams struct {
 	ForwardTenorYears int
 	SwapTenorYears    int
 
+	// ForwardFromSpot controls forward-start effective dates when ForwardTenorYears > 0.
+	// By default the effective date is spot-lagged from (TradeDate + ForwardTenorYears),
+	// matching Bloomberg SWPM "XxY" (spot-of-forward) quotes.
+	// When true, the effective date is (spot + ForwardTenorYears) without re-applying the lag.
+	ForwardFromSpot bool
+
 	// Optional explicit dates (override ForwardTenorYears / SwapTenorYears if set)
 	EffectiveDate time.Time
 	MaturityDate  time.Time
@@ -66,6 +83,13 @@ type InterestRateSwapParams struct {
 	// Economics
 	Notional float64
 
+	// Direction, when set, places the fixed leg on the pay (PositionPay) or receive
+	// (PositionReceive) side. PayLeg and RecLeg then only name the two legs: whichever is
+	// fixed is moved to the Direction side, together with its quotes, spread and first
+	// reset, and the floating leg to the other. Exactly one leg must be fixed. When empty,
+	// PayLeg is paid and RecLeg received as given.
+	Direction Position
+
 	// Legs and discounting convention
 	PayLeg         market.LegConvention
 	RecLeg         market.LegConvention
@@ -73,11 +97,25 @@ type InterestRateSwapParams struct {
 
 	// Quotes used to build curves as-of CurveDate.
 	//
-	// OISQuotes is required.
-	// PayLegQuotes / RecLegQuotes are required only for IBOR floating legs.
-	OISQuotes    map[string]float64
-	PayLegQuotes map[string]float64
-	RecLegQuotes map[string]float64
+	// OISQuotes is required unless DiscountQuotes is set.
+	// PayLegQuotes / RecLegQuotes are required for floating legs.
+	// Missing quotes fail with a *MissingQuotesError wrapping ErrMissingOISQuotes or
+	// ErrMissingProjectionQuotes.
+	OISQuotes    market.Quotes
+	PayLegQuotes market.Quotes
+	RecLegQuotes market.Quotes
+
+	// DiscountQuotes, when non-nil, bootstraps the discount curve in place of OISQuotes,
+	// so discounting can differ from either projection curve (e.g., an OIS basis trade
+	// whose two TONAR legs project off venue curves but discount off a third).
+	DiscountQuotes market.Quotes
+
+	// DiscountCurveConvention selects the fixed-leg day count used when bootstrapping
+	// the discount curve from OISQuotes. FixedLegDayCountOIS uses OIS conventions
+	// (ACT/360 for EUR); FixedLegDayCountIBOR uses IBOR IRS conventions (30/360 for EUR),
+	// which suits legacy trades discounted on an IBOR curve.
+	// If empty, it is inferred from DiscountingOIS: OIS for overnight indices, IBOR otherwise.
+	DiscountCurveConvention curve.FixedLegDayCount
 
 	// Spreads (in bp). For fixed legs, spread is interpreted as the fixed coupon in bp.
 	PayLegSpreadBP float64
@@ -90,6 +128,21 @@ type InterestRateSwapParams struct {
 	// Bloomberg SWPM's "Latest Index".
 	PayLegFirstResetPct *float64
 	RecLegFirstResetPct *float64
+
+	// Fixings supplies realized overnight fixings for a period in progress at the
+	// valuation date (see market.SwapSpec.Fixings). Optional.
+	Fixings market.FixingRepo
+
+	// ForceParRateBasis treats a swap of two different overnight indices (e.g., SOFR vs
+	// Fed Funds) as an OIS basis swap, so SolveParSpread returns the difference of the two
+	// legs' par rates on the common discount curve, as it does for one index across
+	// venues. Ignored unless both legs are overnight.
+	ForceParRateBasis bool
+
+	// CrossCurrency disables the check that floating legs are in the discounting
+	// currency and on its calendar, for a leg deliberately discounted on another
+	// currency's curve (e.g. as one side of a cross-currency structure).
+	CrossCurrency bool
 }
 
 // SwapTrade is a fully specified swap trade paired with valuation curves.
@@ -108,10 +161,62 @@ type SwapTrade struct {
 	PayProjCurve  ProjectionCurve
 	RecProjCurve  ProjectionCurve
 
-	// IsOISBasisSwap indicates this is an OIS basis swap where both legs reference
-	// the same overnight index but from different venues (e.g., LCHS vs JSCC TONAR).
+	// IsOISBasisSwap indicates this is an OIS basis swap: both legs reference the same
+	// overnight index from different venues (e.g., LCHS vs JSCC TONAR), or two overnight
+	// indices with ForceParRateBasis (e.g., SOFR vs Fed Funds).
 	// When true, SolveParSpread uses par rate difference instead of cross-curve NPV.
 	IsOISBasisSwap bool
+
+	// Notes records construction decisions worth surfacing when debugging a price,
+	// e.g. a projection curve that was shared with the discount curve.
+	Notes []string
+
+	// params are the inputs the trade was built from, kept so curves can be rebuilt
+	// from shifted quotes (see ScenarioPnL).
+	params InterestRateSwapParams
+}
+
+// legCurrency returns the currency of a leg from its reference index, or else from its
+// calendar, using market.IndexDefaults. It returns "" when neither identifies one.
+func legCurrency(leg market.LegConvention) string {
+	if p, err := market.IndexDefaults(string(leg.ReferenceIndex)); err == nil {
+		return p.Currency
+	}
+	for _, r := range []market.ReferenceIndex{market.SOFR, market.ESTR, market.SONIA, market.CORRA, market.TONAR, market.HIBOR3M, market.CD91D} {
+		if p, _ := market.IndexDefaults(string(r)); p.Calendar == leg.Calendar {
+			return p.Currency
+		}
+	}
+	return ""
+}
+
+// checkFloatingLegs reports an error if a floating leg is in a different currency from
+// the discounting leg (ErrCurrencyMismatch) or, in the same currency, rolls on a
+// different calendar (ErrCalendarMismatch). Currencies that cannot be identified are
+// not compared.
+func checkFloatingLegs(params InterestRateSwapParams) error {
+	disc := params.DiscountingOIS
+	discCcy := legCurrency(disc)
+	for _, l := range []struct {
+		name string
+		leg  market.LegConvention
+	}{
+		{"pay leg", params.PayLeg},
+		{"receive leg", params.RecLeg},
+	} {
+		if l.leg.LegType != market.LegFloating {
+			continue
+		}
+		if ccy := legCurrency(l.leg); ccy != "" && discCcy != "" && ccy != discCcy {
+			return fmt.Errorf("%s %s (%s, calendar %s) is not in the discounting currency %s (%s, calendar %s); set CrossCurrency to allow it: %w",
+				l.name, l.leg.ReferenceIndex, ccy, l.leg.Calendar, discCcy, disc.ReferenceIndex, disc.Calendar, ErrCurrencyMismatch)
+		}
+		if l.leg.Calendar != disc.Calendar {
+			return fmt.Errorf("%s %s calendar %s differs from discounting %s calendar %s; set CrossCurrency to allow it: %w",
+				l.name, l.leg.ReferenceIndex, l.leg.Calendar, disc.ReferenceIndex, disc.Calendar, ErrCalendarMismatch)
+		}
+	}
+	return nil
 }
 
 func defaultSpotLagDays(ch ClearingHouse) int {
@@ -142,8 +247,38 @@ func InterestRateSwap(params InterestRateSwapParams) (*SwapTrade, error) {
 	if params.Notional == 0 {
 		return nil, fmt.Errorf("InterestRateSwap: Notional is required")
 	}
-	if params.OISQuotes == nil {
-		return nil, fmt.Errorf("InterestRateSwap: OISQuotes is required")
+	if params.Direction != "" {
+		oriented, err := orientLegs(params)
+		if err != nil {
+			return nil, fmt.Errorf("InterestRateSwap: %w", err)
+		}
+		params = oriented
+	}
+	discQuotes := params.DiscountQuotes
+	if discQuotes == nil {
+		discQuotes = params.OISQuotes
+	}
+	if discQuotes == nil {
+		return nil, fmt.Errorf("InterestRateSwap: OISQuotes is required: %w", &MissingQuotesError{Index: params.DiscountingOIS.ReferenceIndex, Err: ErrMissingOISQuotes})
+	}
+	for _, q := range []struct {
+		name   string
+		quotes market.Quotes
+	}{
+		{"OISQuotes", params.OISQuotes},
+		{"PayLegQuotes", params.PayLegQuotes},
+		{"RecLegQuotes", params.RecLegQuotes},
+		{"DiscountQuotes", params.DiscountQuotes},
+	} {
+		if err := q.quotes.Validate(); err != nil {
+			return nil, fmt.Errorf("InterestRateSwap: %s: %w", q.name, err)
+		}
+	}
+
+	if !params.CrossCurrency {
+		if err := checkFloatingLegs(params); err != nil {
+			return nil, fmt.Errorf("InterestRateSwap: %w", err)
+		}
 	}
 
 	spotLag := params.SpotLagDays
@@ -156,6 +291,14 @@ func InterestRateSwap(params InterestRateSwapParams) (*SwapTrade, error) {
 		spot = params.EffectiveDate
 		effective = params.EffectiveDate
 		maturity = params.MaturityDate
+	} else if params.ForwardFromSpot {
+		spot, effective, maturity = SpotEffectiveMaturityForwardFromSpot(
+			params.TradeDate,
+			params.DiscountingOIS.Calendar,
+			spotLag,
+			params.ForwardTenorYears,
+			params.SwapTenorYears,
+		)
 	} else {
 		spot, effective, maturity = SpotEffectiveMaturityWithSpotLag(
 			params.TradeDate,
@@ -168,32 +311,52 @@ func InterestRateSwap(params InterestRateSwapParams) (*SwapTrade, error) {
 
 	// Curve settlement is spot date (curve date + spot lag), not the curve date itself.
 	// This matches the standard convention where quotes are for swaps starting at spot.
-	curveSettlement := calendar.AddBusinessDays(params.DiscountingOIS.Calendar, params.CurveDate, spotLag)
-
-	// Build discount curve: use IBOR conventions (30/360 for EUR) if discounting with IBOR rate,
-	// or OIS conventions (ACT/360 for EUR) if discounting with overnight rate.
+	curveSettlement := curve.SpotSettlement(params.CurveDate, params.DiscountingOIS.Calendar, spotLag)
+
+	// Build discount curve from DiscountQuotes (or OISQuotes): use IBOR conventions (30/360 for EUR) if discounting with IBOR rate,
+	// or OIS conventions (ACT/360 for EUR) if discounting with overnight rate, unless overridden.
+	discConvention := params.DiscountCurveConvention
+	if discConvention == "" {
+		discConvention = curve.FixedLegDayCountIBOR
+		if market.IsOvernight(params.DiscountingOIS.ReferenceIndex) {
+			discConvention = curve.FixedLegDayCountOIS
+		}
+	}
 	var disc *curve.Curve
-	if market.IsOvernight(params.DiscountingOIS.ReferenceIndex) {
-		disc = curve.BuildCurve(curveSettlement, params.OISQuotes, params.DiscountingOIS.Calendar, 1)
-	} else {
+	switch discConvention {
+	case curve.FixedLegDayCountOIS:
+		disc = curve.BuildCurve(curveSettlement, discQuotes, params.DiscountingOIS.Calendar, 1)
+	case curve.FixedLegDayCountIBOR:
 		// IBOR discounting (pre-2020 convention): use 30/360 for EUR fixed leg
-		disc = curve.BuildIBORDiscountCurve(curveSettlement, params.OISQuotes, params.DiscountingOIS.Calendar, 1)
+		disc = curve.BuildIBORDiscountCurve(curveSettlement, discQuotes, params.DiscountingOIS.Calendar, 1)
+	default:
+		return nil, fmt.Errorf("InterestRateSwap: unknown DiscountCurveConvention %q", discConvention)
 	}
 	if disc == nil {
 		return nil, fmt.Errorf("InterestRateSwap: failed to build discount curve")
 	}
 
-	buildProj := func(leg market.LegConvention, quotes map[string]float64) (ProjectionCurve, error) {
+	var notes []string
+	buildProj := func(leg market.LegConvention, quotes market.Quotes) (ProjectionCurve, error) {
 		if leg.LegType != market.LegFloating {
 			return nil, nil
 		}
 		// For all floating legs, quotes must be provided explicitly
 		if quotes == nil {
-			return nil, fmt.Errorf("missing quotes for %s projection curve", leg.ReferenceIndex)
+			return nil, &MissingQuotesError{Index: leg.ReferenceIndex, Err: ErrMissingProjectionQuotes}
 		}
 
 		// For overnight rates (OIS), build curve directly from quotes
 		if market.IsOvernight(leg.ReferenceIndex) {
+			// When the leg projects off the same quotes and calendar as the OIS discount
+			// curve, a second bootstrap would rebuild the discount curve; reuse it instead.
+			if leg.ReferenceIndex == params.DiscountingOIS.ReferenceIndex &&
+				leg.Calendar == params.DiscountingOIS.Calendar &&
+				discConvention == curve.FixedLegDayCountOIS &&
+				maps.Equal(quotes, discQuotes) {
+				notes = append(notes, fmt.Sprintf("%s projection reuses the discount curve (quotes match discount quotes)", leg.ReferenceIndex))
+				return disc, nil
+			}
 			// Build OIS curve for this leg using provided quotes
 			// This enables OIS basis swaps (e.g., JSCC TONAR vs LCH TONAR)
 			oisCurve := curve.BuildCurve(curveSettlement, quotes, leg.Calendar, 1)
@@ -234,12 +397,14 @@ func InterestRateSwap(params InterestRateSwapParams) (*SwapTrade, error) {
 		RecLegSpreadBP:      params.RecLegSpreadBP,
 		PayLegFirstResetPct: params.PayLegFirstResetPct,
 		RecLegFirstResetPct: params.RecLegFirstResetPct,
+		Fixings:             params.Fixings,
 	}
 
-	// Detect OIS basis swap: both legs are overnight rates with the same reference index
+	// Detect OIS basis swap: both legs are overnight rates on the same reference index,
+	// or on any two with ForceParRateBasis.
 	isOISBasisSwap := market.IsOvernight(params.PayLeg.ReferenceIndex) &&
 		market.IsOvernight(params.RecLeg.ReferenceIndex) &&
-		params.PayLeg.ReferenceIndex == params.RecLeg.ReferenceIndex
+		(params.PayLeg.ReferenceIndex == params.RecLeg.ReferenceIndex || params.ForceParRateBasis)
 
 	return &SwapTrade{
 		DataSource:     params.DataSource,
@@ -253,9 +418,37 @@ func InterestRateSwap(params InterestRateSwapParams) (*SwapTrade, error) {
 		PayProjCurve:   projPay,
 		RecProjCurve:   projRec,
 		IsOISBasisSwap: isOISBasisSwap,
+		Notes:          notes,
+		params:         params,
 	}, nil
 }
 
+// orientLegs moves the fixed leg of params, with its pay/receive-specific inputs, to the
+// side given by params.Direction.
+func orientLegs(params InterestRateSwapParams) (InterestRateSwapParams, error) {
+	payFixed := params.PayLeg.LegType == market.LegFixed
+	recFixed := params.RecLeg.LegType == market.LegFixed
+	if payFixed == recFixed {
+		return params, fmt.Errorf("Direction %s needs exactly one fixed leg", params.Direction)
+	}
+	var flip bool
+	switch params.Direction {
+	case PositionPay:
+		flip = recFixed
+	case PositionReceive:
+		flip = payFixed
+	default:
+		return params, fmt.Errorf("unknown Direction %q", params.Direction)
+	}
+	if flip {
+		params.PayLeg, params.RecLeg = params.RecLeg, params.PayLeg
+		params.PayLegQuotes, params.RecLegQuotes = params.RecLegQuotes, params.PayLegQuotes
+		params.PayLegSpreadBP, params.RecLegSpreadBP = params.RecLegSpreadBP, params.PayLegSpreadBP
+		params.PayLegFirstResetPct, params.RecLegFirstResetPct = params.RecLegFirstResetPct, params.PayLegFirstResetPct
+	}
+	return params, nil
+}
+
 // NPV returns the swap NPV for the trade's current spreads.
 func (t *SwapTrade) NPV() (float64, error) {
 	return NPV(t.Spec, t.PayProjCurve, t.RecProjCurve, t.DiscountCurve, t.ValuationDate)
@@ -268,7 +461,7 @@ func (t *SwapTrade) PVByLeg() (PV, error) {
 
 // SolveParSpread solves for the target leg spread (in bp) such that NPV = 0, and updates the trade spec.
 //
-// For OIS basis swaps (same overnight index, different venues), it computes the difference
+// For OIS basis swaps (see SwapTrade.IsOISBasisSwap), it computes the difference
 // in par swap rates between the two curves instead of using cross-curve NPV optimization.
 func (t *SwapTrade) SolveParSpread(target SpreadTarget) (float64, PV, error) {
 	var spreadBP float64
@@ -304,3 +497,213 @@ func (t *SwapTrade) SolveParSpread(target SpreadTarget) (float64, PV, error) {
 	}
 	return spreadBP, pv, nil
 }
+
+// SolveParFixedRate is SolveParSpread on whichever leg is fixed, returning the par fixed
+// rate in percent rather than bp. It updates the trade spec the same way and errors unless
+// exactly one leg is fixed.
+func (t *SwapTrade) SolveParFixedRate() (float64, PV, error) {
+	payFixed := t.Spec.PayLeg.LegType == market.LegFixed
+	recFixed := t.Spec.RecLeg.LegType == market.LegFixed
+	if payFixed == recFixed {
+		return 0, PV{}, fmt.Errorf("SolveParFixedRate: trade must have exactly one fixed leg")
+	}
+	target := SpreadTargetPayLeg
+	if recFixed {
+		target = SpreadTargetRecLeg
+	}
+	spreadBP, pv, err := t.SolveParSpread(target)
+	if err != nil {
+		return 0, PV{}, fmt.Errorf("SolveParFixedRate: %w", err)
+	}
+	return spreadBP / 100, pv, nil
+}
+
+// NPVForSpreads returns the trade NPV with the target leg's spread set to each of
+// spreadsBP, leaving the trade unchanged. NPV is linear in the spread, so it prices the
+// legs once and moves along the analytic PV01 of the target leg; a target leg that
+// rounds coupons, follows a SpreadSchedule or clamps coupons to a floor or cap after the
+// spread is not linear and is repriced per spread.
+func (t *SwapTrade) NPVForSpreads(target SpreadTarget, spreadsBP []float64) ([]float64, error) {
+	var (
+		leg    market.LegConvention
+		baseBP float64
+	)
+	switch target {
+	case SpreadTargetPayLeg:
+		leg, baseBP = t.Spec.PayLeg, t.Spec.PayLegSpreadBP
+	case SpreadTargetRecLeg:
+		leg, baseBP = t.Spec.RecLeg, t.Spec.RecLegSpreadBP
+	default:
+		return nil, fmt.Errorf("NPVForSpreads: unknown target %d", target)
+	}
+
+	out := make([]float64, len(spreadsBP))
+	clampsSpread := leg.LegType == market.LegFloating && !leg.ClampBeforeSpread &&
+		(leg.CouponFloor != nil || leg.CouponCap != nil)
+	if leg.RoundCoupons || len(leg.SpreadSchedule) > 0 || clampsSpread {
+		spec := t.Spec
+		for i, bp := range spreadsBP {
+			if target == SpreadTargetPayLeg {
+				spec.PayLegSpreadBP = bp
+			} else {
+				spec.RecLegSpreadBP = bp
+			}
+			npv, err := NPV(spec, t.PayProjCurve, t.RecProjCurve, t.DiscountCurve, t.ValuationDate)
+			if err != nil {
+				return nil, err
+			}
+			out[i] = npv
+		}
+		return out, nil
+	}
+
+	base, err := t.NPV()
+	if err != nil {
+		return nil, err
+	}
+	pv01Dec, err := pv01TargetLegPerDec(t.Spec, t.DiscountCurve, t.ValuationDate, target)
+	if err != nil {
+		return nil, err
+	}
+	for i, bp := range spreadsBP {
+		out[i] = base + (bp-baseBP)*1e-4*pv01Dec
+	}
+	return out, nil
+}
+
+// FairLevels returns, for a fixed-vs-float trade, the par fixed rate (in percent) and the
+// floating leg spread (in bp) that zeroes NPV at the trade's current fixed rate.
+//
+// Both are solved on a copy of the spec; the trade is left unchanged.
+func (t *SwapTrade) FairLevels() (fixedRatePct, floatSpreadBP float64, err error) {
+	payFixed := t.Spec.PayLeg.LegType == market.LegFixed
+	recFixed := t.Spec.RecLeg.LegType == market.LegFixed
+	if payFixed == recFixed {
+		return 0, 0, fmt.Errorf("FairLevels: trade must have exactly one fixed leg")
+	}
+
+	fixedTarget, floatTarget := SpreadTargetPayLeg, SpreadTargetRecLeg
+	if recFixed {
+		fixedTarget, floatTarget = SpreadTargetRecLeg, SpreadTargetPayLeg
+	}
+
+	fixedBP, err := SolveParSpread(t.Spec, t.PayProjCurve, t.RecProjCurve, t.DiscountCurve, t.ValuationDate, fixedTarget)
+	if err != nil {
+		return 0, 0, fmt.Errorf("FairLevels: fixed rate: %w", err)
+	}
+	floatSpreadBP, err = SolveParSpread(t.Spec, t.PayProjCurve, t.RecProjCurve, t.DiscountCurve, t.ValuationDate, floatTarget)
+	if err != nil {
+		return 0, 0, fmt.Errorf("FairLevels: float spread: %w", err)
+	}
+	return fixedBP / 100, floatSpreadBP, nil
+}
+
+// parRateDecimals is the precision, in decimal places of the rate as a decimal, to which
+// a clearing venue rounds par swap rates (LCH 1e-7, KRX 1e-6 i.e. 0.0001%).
+var parRateDecimals = map[ClearingHouse]uint32{
+	ClearingHouseLCH: 7,
+	ClearingHouseKRX: 6,
+}
+
+// ParSwapRateRounded returns the trade's par fixed rate (in percent, as FairLevels) rounded
+// to venue's published precision, for reconciling against cleared trade economics.
+func (t *SwapTrade) ParSwapRateRounded(venue ClearingHouse) (float64, error) {
+	decimals, ok := parRateDecimals[venue]
+	if !ok {
+		return 0, fmt.Errorf("ParSwapRateRounded: no par rate rounding defined for venue %q", venue)
+	}
+	fixedPct, _, err := t.FairLevels()
+	if err != nil {
+		return 0, fmt.Errorf("ParSwapRateRounded: %w", err)
+	}
+	// Round in percent terms: a decimal precision of 1e-7 is 1e-5 in percent.
+	return utils.RoundTo(fixedPct, decimals-2), nil
+}
+
+// ForwardParRate returns the par fixed rate (in percent) the curves imply for the trade's
+// swap structure from its effective date: the floating leg's curve-implied coupons over
+// the fixed leg's annuity, both discounted on the trade's discount curve.
+//
+// Unlike FairLevels it is a pure curve quantity: notional exchanges, floating spreads,
+// first-reset overrides and realized fixings are ignored, and every period from the
+// effective date is included regardless of the valuation date.
+func (t *SwapTrade) ForwardParRate() (float64, error) {
+	rate, _, err := t.forwardParAnnuity()
+	if err != nil {
+		return 0, fmt.Errorf("ForwardParRate: %w", err)
+	}
+	return rate * 100, nil
+}
+
+// forwardParAnnuity returns the forward par rate (decimal) and the fixed leg annuity per
+// unit notional, as described on ForwardParRate.
+func (t *SwapTrade) forwardParAnnuity() (rate, annuity float64, err error) {
+	fixedLeg, floatLeg, projCurve := t.Spec.PayLeg, t.Spec.RecLeg, t.RecProjCurve
+	if t.Spec.RecLeg.LegType == market.LegFixed {
+		fixedLeg, floatLeg, projCurve = t.Spec.RecLeg, t.Spec.PayLeg, t.PayProjCurve
+	}
+	if fixedLeg.LegType != market.LegFixed || floatLeg.LegType != market.LegFloating {
+		return 0, 0, fmt.Errorf("trade must have one fixed and one floating leg")
+	}
+	if isNilInterface(t.DiscountCurve) || isNilInterface(projCurve) {
+		return 0, 0, ErrNilCurve
+	}
+
+	fixedPeriods, err := GenerateSchedule(t.Spec.EffectiveDate, t.Spec.MaturityDate, fixedLeg)
+	if err != nil {
+		return 0, 0, fmt.Errorf("fixed leg: %w", err)
+	}
+	floatPeriods, err := GenerateSchedule(t.Spec.EffectiveDate, t.Spec.MaturityDate, floatLeg)
+	if err != nil {
+		return 0, 0, fmt.Errorf("floating leg: %w", err)
+	}
+
+	for _, p := range fixedPeriods {
+		annuity += utils.YearFraction(p.StartDate, p.EndDate, string(fixedLeg.DayCount)) * t.DiscountCurve.DF(p.PayDate)
+	}
+	if annuity == 0 {
+		return 0, 0, fmt.Errorf("fixed leg annuity is zero")
+	}
+
+	floatPV := 0.0
+	for _, p := range floatPeriods {
+		r := forwardRate(projCurve, p.StartDate, p.EndDate, string(floatLeg.DayCount))
+		if compoundsResets(floatLeg) {
+			r = compoundedIBORRate(projCurve, p, floatLeg, nil)
+		}
+		floatPV += r * utils.YearFraction(p.StartDate, p.EndDate, string(floatLeg.DayCount)) * t.DiscountCurve.DF(p.PayDate)
+	}
+	return floatPV / annuity, annuity, nil
+}
+
+// CurveSnapshot returns copies of the bootstrapped discount and projection curves the trade
+// prices off, so callers can audit the zero/DF curves behind a price.
+//
+// Projection snapshots are zero-valued for fixed legs. An error is returned if a curve
+// was not built by the curve package (e.g., a caller-supplied DiscountCurve).
+func (t *SwapTrade) CurveSnapshot() (disc, projPay, projRec curve.CurveSnapshot, err error) {
+	snapshot := func(name string, v any) (curve.CurveSnapshot, error) {
+		if isNilInterface(v) {
+			return curve.CurveSnapshot{}, nil
+		}
+		c, ok := v.(*curve.Curve)
+		if !ok {
+			return curve.CurveSnapshot{}, fmt.Errorf("CurveSnapshot: %s curve is %T, not *curve.Curve", name, v)
+		}
+		return c.Snapshot(), nil
+	}
+
+	if isNilInterface(t.DiscountCurve) {
+		return disc, projPay, projRec, ErrNilCurve
+	}
+	if disc, err = snapshot("discount", t.DiscountCurve); err != nil {
+		return disc, projPay, projRec, err
+	}
+	if projPay, err = snapshot("pay projection", t.PayProjCurve); err != nil {
+		return disc, projPay, projRec, err
+	}
+	if projRec, err = snapshot("receive projection", t.RecProjCurve); err != nil {
+		return disc, projPay, projRec, err
+	}
+	return disc, projPay, projRec, nil
+}
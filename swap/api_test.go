@@ -1,14 +1,18 @@
 package swap_test
 
 import (
+	"errors"
 	"math"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/meenmo/molib/calendar"
+	"github.com/meenmo/molib/instruments/swaps"
 	"github.com/meenmo/molib/swap"
 	"github.com/meenmo/molib/swap/curve"
 	"github.com/meenmo/molib/swap/market"
+	"github.com/meenmo/molib/utils"
 )
 
 func TestGenerateSchedule_SinglePeriod(t *testing.T) {
@@ -59,6 +63,368 @@ func TestGenerateSchedule_SinglePeriod(t *testing.T) {
 	}
 }
 
+func TestGenerateSchedule_BusinessDayAdjustment(t *testing.T) {
+	t.Parallel()
+
+	// Mon 1 Sep 2025 is Labor Day and Sat 1 Nov 2025 a weekend: rolling back from either
+	// crosses into the previous month.
+	effective := time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC)
+	maturity := time.Date(2025, 11, 1, 0, 0, 0, 0, time.UTC)
+	day := func(m time.Month, d int) time.Time { return time.Date(2025, m, d, 0, 0, 0, 0, time.UTC) }
+
+	cases := []struct {
+		adj  market.BusinessDayAdjustment
+		ends []time.Time
+	}{
+		{market.ModifiedFollowing, []time.Time{day(9, 2), day(10, 1), day(11, 3)}},
+		{market.Preceding, []time.Time{day(8, 29), day(10, 1), day(10, 31)}},
+		{market.ModifiedPreceding, []time.Time{day(9, 2), day(10, 1), day(11, 3)}},
+	}
+	for _, tc := range cases {
+		leg := swaps.SOFRFixed
+		leg.PayFrequency = market.FreqMonthly
+		leg.PayDelayDays = 0
+		leg.RollConvention = market.Backward
+		leg.BusinessDayAdjustment = tc.adj
+
+		periods, err := swap.GenerateSchedule(effective, maturity, leg)
+		if err != nil {
+			t.Fatalf("%s: GenerateSchedule: %v", tc.adj, err)
+		}
+		if len(periods) != len(tc.ends) {
+			t.Fatalf("%s: got %d periods, want %d", tc.adj, len(periods), len(tc.ends))
+		}
+		for i, p := range periods {
+			if !p.EndDate.Equal(tc.ends[i]) {
+				t.Errorf("%s: period %d end %s, want %s", tc.adj, i, p.EndDate.Format("2006-01-02"), tc.ends[i].Format("2006-01-02"))
+			}
+		}
+	}
+
+	leg := swaps.SOFRFixed
+	leg.BusinessDayAdjustment = "FOLLOWING_NEAREST"
+	if _, err := swap.GenerateSchedule(effective, maturity, leg); err == nil {
+		t.Fatalf("expected error for unsupported business day adjustment")
+	}
+}
+
+func TestGenerateSchedule_FixingLagSign(t *testing.T) {
+	t.Parallel()
+
+	effective := time.Date(2025, 3, 5, 0, 0, 0, 0, time.UTC) // Wednesday
+	maturity := time.Date(2025, 6, 5, 0, 0, 0, 0, time.UTC)  // Thursday
+
+	leg := market.LegConvention{
+		LegType:        market.LegFloating,
+		ReferenceIndex: market.EURIBOR3M,
+		DayCount:       market.Act360,
+		ResetFrequency: market.FreqQuarterly,
+		PayFrequency:   market.FreqQuarterly,
+		FixingLagDays:  2,
+		Calendar:       calendar.TARGET,
+		ResetPosition:  market.ResetInAdvance,
+	}
+
+	fixingDate := func(leg market.LegConvention) time.Time {
+		t.Helper()
+		periods, err := swap.GenerateSchedule(effective, maturity, leg)
+		if err != nil {
+			t.Fatalf("GenerateSchedule error: %v", err)
+		}
+		if len(periods) != 1 {
+			t.Fatalf("expected 1 period, got %d", len(periods))
+		}
+		return periods[0].FixingDate
+	}
+
+	// In advance: two business days before accrual start.
+	if got, want := fixingDate(leg), time.Date(2025, 3, 3, 0, 0, 0, 0, time.UTC); !got.Equal(want) {
+		t.Fatalf("in-advance fixing: got %s want %s", got.Format("2006-01-02"), want.Format("2006-01-02"))
+	}
+
+	// In arrears: two business days before accrual end, plus any rate cutoff.
+	leg.ResetPosition = market.ResetInArrears
+	if got, want := fixingDate(leg), time.Date(2025, 6, 3, 0, 0, 0, 0, time.UTC); !got.Equal(want) {
+		t.Fatalf("in-arrears fixing: got %s want %s", got.Format("2006-01-02"), want.Format("2006-01-02"))
+	}
+	leg.RateCutoffDays = 1
+	if got, want := fixingDate(leg), time.Date(2025, 6, 2, 0, 0, 0, 0, time.UTC); !got.Equal(want) {
+		t.Fatalf("in-arrears fixing with cutoff: got %s want %s", got.Format("2006-01-02"), want.Format("2006-01-02"))
+	}
+
+	leg.FixingLagDays = -2
+	if _, err := swap.GenerateSchedule(effective, maturity, leg); err == nil {
+		t.Fatalf("expected error for negative fixing lag")
+	}
+}
+
+func TestAnniversarySchedule_EOMRoll(t *testing.T) {
+	t.Parallel()
+
+	// 2025-02-28 is the last day of February, so EOM roll keeps every date at month end.
+	start := time.Date(2025, 2, 28, 0, 0, 0, 0, time.UTC)
+	dates := swap.AnniversarySchedule(start, 6, 10, calendar.TARGET, market.BackwardEOM, market.ModifiedFollowing)
+	if len(dates) != 10 {
+		t.Fatalf("got %d dates, want 10", len(dates))
+	}
+	for i, d := range dates {
+		unadj := time.Date(2025, time.Month(2+6*(i+1)+1), 0, 0, 0, 0, 0, time.UTC)
+		if want := calendar.Adjust(calendar.TARGET, unadj); !d.Equal(want) {
+			t.Fatalf("date %d = %s, want %s", i, d.Format("2006-01-02"), want.Format("2006-01-02"))
+		}
+		if !calendar.IsEndOfMonth(calendar.TARGET, d) {
+			t.Fatalf("date %d = %s is not the last business day of its month", i, d.Format("2006-01-02"))
+		}
+	}
+
+	// Without EOM the anniversary stays on the 28th.
+	dates = swap.AnniversarySchedule(start, 6, 10, calendar.TARGET, market.Backward, market.ModifiedFollowing)
+	if got := dates[0]; got.Day() != 28 || got.Month() != time.August {
+		t.Fatalf("first non-EOM date = %s, want 2025-08-28", got.Format("2006-01-02"))
+	}
+}
+
+func TestGenerateSchedule_EndOfMonth(t *testing.T) {
+	t.Parallel()
+
+	effective := time.Date(2025, 6, 30, 0, 0, 0, 0, time.UTC)
+	maturity := time.Date(2027, 6, 30, 0, 0, 0, 0, time.UTC)
+	want := []time.Time{
+		time.Date(2025, 12, 31, 0, 0, 0, 0, time.UTC),
+		time.Date(2026, 6, 30, 0, 0, 0, 0, time.UTC),
+		time.Date(2026, 12, 31, 0, 0, 0, 0, time.UTC),
+		time.Date(2027, 6, 30, 0, 0, 0, 0, time.UTC),
+	}
+
+	for _, dir := range []market.ScheduleDirection{market.ScheduleForward, market.ScheduleBackward} {
+		leg := swaps.EURIBOR6MFloating
+		leg.RollConvention = market.Backward
+		leg.ScheduleDirection = dir
+
+		// Without EndOfMonth, 30-Jun + 6M rolls to 30-Dec.
+		periods, err := swap.GenerateSchedule(effective, maturity, leg)
+		if err != nil {
+			t.Fatalf("%s: GenerateSchedule: %v", dir, err)
+		}
+		if got := periods[0].EndDate; !got.Equal(time.Date(2025, 12, 30, 0, 0, 0, 0, time.UTC)) {
+			t.Fatalf("%s: first period end without EndOfMonth %s, want 2025-12-30", dir, got.Format("2006-01-02"))
+		}
+
+		leg.EndOfMonth = true
+		periods, err = swap.GenerateSchedule(effective, maturity, leg)
+		if err != nil {
+			t.Fatalf("%s: GenerateSchedule(EndOfMonth): %v", dir, err)
+		}
+		if len(periods) != len(want) {
+			t.Fatalf("%s: got %d periods, want %d", dir, len(periods), len(want))
+		}
+		for i, p := range periods {
+			if !p.EndDate.Equal(want[i]) {
+				t.Errorf("%s: period %d end %s, want %s", dir, i, p.EndDate.Format("2006-01-02"), want[i].Format("2006-01-02"))
+			}
+		}
+	}
+}
+
+func TestGenerateSchedule_Feb29Anchor(t *testing.T) {
+	t.Parallel()
+
+	effective := time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC)
+	leg := swaps.EURIBORFixed
+	leg.PayFrequency = market.FreqAnnual
+	leg.PayDelayDays = 0
+
+	// EDATE roll: Feb 28 in non-leap years, back to Feb 29 in 2028 with no stub.
+	eomEnds := []time.Time{
+		time.Date(2025, 2, 28, 0, 0, 0, 0, time.UTC),
+		time.Date(2026, 2, 28, 0, 0, 0, 0, time.UTC),
+		time.Date(2027, 2, 28, 0, 0, 0, 0, time.UTC),
+		time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC),
+	}
+	// Go date normalisation: Mar 1 in non-leap years.
+	marchEnds := []time.Time{
+		time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2027, 3, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC),
+	}
+
+	for _, tc := range []struct {
+		roll market.RollConvention
+		dir  market.ScheduleDirection
+		ends []time.Time
+	}{
+		{market.BackwardEOM, market.ScheduleForward, eomEnds},
+		{market.BackwardEOM, market.ScheduleBackward, eomEnds},
+		{market.Backward, market.ScheduleForward, marchEnds},
+	} {
+		leg.RollConvention = tc.roll
+		leg.ScheduleDirection = tc.dir
+		for _, years := range []int{1, 4} {
+			maturity := tc.ends[0]
+			if years == 4 {
+				maturity = tc.ends[3]
+			}
+			periods, err := swap.GenerateSchedule(effective, maturity, leg)
+			if err != nil {
+				t.Fatalf("%s/%s %dY: GenerateSchedule: %v", tc.roll, tc.dir, years, err)
+			}
+			if len(periods) != years {
+				t.Fatalf("%s/%s %dY: got %d periods, want %d", tc.roll, tc.dir, years, len(periods), years)
+			}
+			for i, p := range periods {
+				if want := calendar.Adjust(leg.Calendar, tc.ends[i]); !p.EndDate.Equal(want) {
+					t.Fatalf("%s/%s %dY: period %d ends %s, want %s", tc.roll, tc.dir, years, i,
+						p.EndDate.Format("2006-01-02"), want.Format("2006-01-02"))
+				}
+			}
+		}
+	}
+}
+
+func TestGenerateSchedule_StubConventions(t *testing.T) {
+	t.Parallel()
+
+	date := func(y int, m time.Month, d int) time.Time { return time.Date(y, m, d, 0, 0, 0, 0, time.UTC) }
+	leg := swaps.EURIBOR6MFloating
+	effective := date(2026, 1, 15)
+
+	// 18 months: three regular semi-annual periods under every convention.
+	for _, stub := range []market.StubConvention{market.StubNone, market.StubShortFront, market.StubLongFront, market.StubShortBack, market.StubLongBack} {
+		leg.StubConvention = stub
+		periods, err := swap.GenerateSchedule(effective, date(2027, 7, 15), leg)
+		if err != nil {
+			t.Fatalf("%s: GenerateSchedule: %v", stub, err)
+		}
+		if len(periods) != 3 || !periods[1].StartDate.Equal(date(2026, 7, 15)) || !periods[2].StartDate.Equal(date(2027, 1, 15)) {
+			t.Fatalf("%s: 18M schedule is not three regular periods: %+v", stub, periods)
+		}
+	}
+
+	// One month more leaves a one-month stub for each convention to place.
+	maturity := date(2027, 8, 16)
+	for _, tc := range []struct {
+		stub       market.StubConvention
+		n          int
+		start, end time.Time // the stub period
+	}{
+		{market.StubShortFront, 4, effective, date(2026, 2, 16)},
+		{market.StubLongFront, 3, effective, date(2026, 8, 17)}, // Aug 16 is a Sunday
+		{market.StubShortBack, 4, date(2027, 7, 15), maturity},
+		{market.StubLongBack, 3, date(2027, 1, 15), maturity},
+	} {
+		leg.StubConvention = tc.stub
+		periods, err := swap.GenerateSchedule(effective, maturity, leg)
+		if err != nil {
+			t.Fatalf("%s: GenerateSchedule: %v", tc.stub, err)
+		}
+		if len(periods) != tc.n {
+			t.Fatalf("%s: got %d periods, want %d", tc.stub, len(periods), tc.n)
+		}
+		stub := periods[0]
+		if tc.stub == market.StubShortBack || tc.stub == market.StubLongBack {
+			stub = periods[len(periods)-1]
+		}
+		if !stub.StartDate.Equal(tc.start) || !stub.EndDate.Equal(tc.end) {
+			t.Fatalf("%s: stub %s to %s, want %s to %s", tc.stub, stub.StartDate.Format("2006-01-02"), stub.EndDate.Format("2006-01-02"),
+				tc.start.Format("2006-01-02"), tc.end.Format("2006-01-02"))
+		}
+		for i := 1; i < len(periods); i++ {
+			if !periods[i].StartDate.Equal(periods[i-1].EndDate) {
+				t.Fatalf("%s: period %d starts %s, previous ends %s", tc.stub, i, periods[i].StartDate.Format("2006-01-02"), periods[i-1].EndDate.Format("2006-01-02"))
+			}
+		}
+	}
+
+	leg.StubConvention = market.StubNone
+	if _, err := swap.GenerateSchedule(effective, maturity, leg); err == nil {
+		t.Fatalf("expected an error for a stub under %s", market.StubNone)
+	}
+	leg.StubConvention = "MIDDLE"
+	if _, err := swap.GenerateSchedule(effective, maturity, leg); err == nil {
+		t.Fatalf("expected an error for an unknown stub convention")
+	}
+}
+
+func TestPayDateCollisions_HolidayCluster(t *testing.T) {
+	t.Parallel()
+
+	// Annual JPY schedule rolling forward from New Year's Day with a 2-day final stub:
+	// 2027-01-01 (holiday) and the 2027-01-03 maturity (Sunday) both adjust to 2027-01-04.
+	leg := swaps.TIBORFixed
+	leg.ScheduleDirection = market.ScheduleForward
+	leg.PayFrequency = market.FreqAnnual
+	leg.RollConvention = market.Backward
+	leg.PayDelayDays = 1
+
+	periods, err := swap.GenerateSchedule(
+		time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2027, 1, 3, 0, 0, 0, 0, time.UTC),
+		leg,
+	)
+	if err != nil {
+		t.Fatalf("GenerateSchedule: %v", err)
+	}
+	for i := 1; i < len(periods); i++ {
+		if periods[i].PayDate.Before(periods[i-1].PayDate) {
+			t.Fatalf("pay dates not sorted at period %d", i)
+		}
+	}
+
+	got := swap.PayDateCollisions(periods)
+	if len(got) != 1 || got[0] != len(periods)-1 {
+		t.Fatalf("collisions=%v, want [%d]", got, len(periods)-1)
+	}
+	if want := time.Date(2027, 1, 5, 0, 0, 0, 0, time.UTC); !periods[got[0]].PayDate.Equal(want) {
+		t.Fatalf("colliding pay date %s, want %s", periods[got[0]].PayDate.Format("2006-01-02"), want.Format("2006-01-02"))
+	}
+
+	// A regular schedule has none.
+	leg.ScheduleDirection = market.ScheduleBackward
+	periods, err = swap.GenerateSchedule(
+		time.Date(2025, 3, 12, 0, 0, 0, 0, time.UTC),
+		time.Date(2030, 3, 12, 0, 0, 0, 0, time.UTC),
+		leg,
+	)
+	if err != nil {
+		t.Fatalf("GenerateSchedule: %v", err)
+	}
+	if got := swap.PayDateCollisions(periods); len(got) != 0 {
+		t.Fatalf("unexpected collisions %v in a regular schedule", got)
+	}
+}
+
+func TestScheduleKey(t *testing.T) {
+	t.Parallel()
+
+	effective := time.Date(2025, 3, 5, 0, 0, 0, 0, time.UTC)
+	maturity := time.Date(2030, 3, 5, 0, 0, 0, 0, time.UTC)
+
+	key := func(leg market.LegConvention) string {
+		t.Helper()
+		k, err := swap.ScheduleKey(effective, maturity, leg)
+		if err != nil {
+			t.Fatalf("ScheduleKey: %v", err)
+		}
+		return k
+	}
+
+	base := key(swaps.ESTRFloating)
+	if again := key(swaps.ESTRFloating); again != base {
+		t.Fatalf("identical legs produced different keys: %s vs %s", base, again)
+	}
+
+	delayed := swaps.ESTRFloating
+	delayed.PayDelayDays = 2
+	if key(delayed) == base {
+		t.Fatalf("expected a different key when pay delay changes")
+	}
+
+	if _, err := swap.ScheduleKey(maturity, effective, swaps.ESTRFloating); err == nil {
+		t.Fatalf("expected error for maturity before effective")
+	}
+}
+
 func TestGetDiscountFactorsAndZeroRates(t *testing.T) {
 	t.Parallel()
 
@@ -98,6 +464,43 @@ func TestGetDiscountFactorsAndZeroRates(t *testing.T) {
 	}
 }
 
+func TestCurveMaxHorizon(t *testing.T) {
+	t.Parallel()
+
+	settlement := time.Date(2026, 3, 12, 0, 0, 0, 0, time.UTC)
+	quotes := map[string]float64{"1Y": 2.07, "2Y": 2.15, "5Y": 2.35, "10Y": 2.70}
+	horizon := time.Date(2036, 3, 12, 0, 0, 0, 0, time.UTC)
+	disc := curve.BuildCurve(settlement, quotes, calendar.TARGET, 1).WithMaxHorizon(horizon)
+
+	within := []time.Time{time.Date(2031, 3, 12, 0, 0, 0, 0, time.UTC), horizon}
+	if _, err := swap.GetDiscountFactors(disc, within); err != nil {
+		t.Fatalf("GetDiscountFactors within horizon: %v", err)
+	}
+
+	beyond := []time.Time{time.Date(2031, 3, 12, 0, 0, 0, 0, time.UTC), time.Date(2076, 3, 12, 0, 0, 0, 0, time.UTC)}
+	if _, err := swap.GetDiscountFactors(disc, beyond); !errors.Is(err, curve.ErrBeyondHorizon) {
+		t.Fatalf("GetDiscountFactors beyond horizon: expected ErrBeyondHorizon, got %v", err)
+	}
+	if _, err := swap.GetZeroRates(disc, beyond); !errors.Is(err, curve.ErrBeyondHorizon) {
+		t.Fatalf("GetZeroRates beyond horizon: expected ErrBeyondHorizon, got %v", err)
+	}
+
+	spec := market.SwapSpec{
+		Notional:      1_000_000,
+		EffectiveDate: settlement,
+		MaturityDate:  time.Date(2056, 3, 12, 0, 0, 0, 0, time.UTC),
+		PayLeg:        swaps.ESTRFixed,
+		RecLeg:        swaps.ESTRFloating,
+	}
+	if _, err := swap.NPV(spec, nil, disc, disc, settlement); !errors.Is(err, curve.ErrBeyondHorizon) {
+		t.Fatalf("NPV of a 30Y swap on a 10Y-horizon curve: expected ErrBeyondHorizon, got %v", err)
+	}
+	spec.MaturityDate = time.Date(2031, 3, 12, 0, 0, 0, 0, time.UTC)
+	if _, err := swap.NPV(spec, nil, disc, disc, settlement); err != nil {
+		t.Fatalf("NPV within horizon: %v", err)
+	}
+}
+
 func TestGetForwardRates_SinglePeriod(t *testing.T) {
 	t.Parallel()
 
@@ -290,3 +693,1860 @@ func TestSolveParFixedRate_SinglePeriod(t *testing.T) {
 		t.Fatalf("expected solved NPV ~ 0, got %.12f", npvSolved)
 	}
 }
+
+func TestOISParRateAnalytic_MatchesScheduleParRate(t *testing.T) {
+	t.Parallel()
+
+	settlement := time.Date(2026, 1, 13, 0, 0, 0, 0, time.UTC)
+	disc := curve.BuildCurve(settlement, map[string]float64{
+		"1Y": 3.0, "2Y": 3.0, "3Y": 3.0, "5Y": 3.0, "7Y": 3.0, "10Y": 3.0,
+	}, calendar.FD, 1)
+
+	// Pay on accrual end so the compounded forwards telescope exactly.
+	leg := swaps.SOFRFixed
+	leg.PayDelayDays = 0
+	spec := market.SwapSpec{
+		Notional:      1,
+		EffectiveDate: settlement,
+		MaturityDate:  time.Date(2033, 1, 13, 0, 0, 0, 0, time.UTC),
+	}
+
+	want, err := swap.ComputeOISParRateWithDiscount(spec, disc, disc, settlement, leg)
+	if err != nil {
+		t.Fatalf("ComputeOISParRateWithDiscount: %v", err)
+	}
+	got := swap.OISParRateAnalytic(disc, spec.EffectiveDate, spec.MaturityDate, leg)
+	if math.Abs(got-want) > 1e-10 {
+		t.Fatalf("analytic par rate %.12f, schedule par rate %.12f", got, want)
+	}
+}
+
+func TestInterestRateSwap_DiscountCurveConvention(t *testing.T) {
+	t.Parallel()
+
+	curveDate := time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)
+	estrQuotes := map[string]float64{
+		"1Y":  2.06795,
+		"2Y":  2.153975,
+		"3Y":  2.24,
+		"5Y":  2.3495,
+		"7Y":  2.484,
+		"10Y": 2.6955,
+	}
+	euriborQuotes := map[string]float64{
+		"1Y":  2.25,
+		"2Y":  2.35,
+		"3Y":  2.44,
+		"5Y":  2.56,
+		"7Y":  2.70,
+		"10Y": 2.90,
+	}
+
+	build := func(conv curve.FixedLegDayCount) *swap.SwapTrade {
+		trade, err := swap.InterestRateSwap(swap.InterestRateSwapParams{
+			DataSource:              swap.DataSourceBGN,
+			ClearingHouse:           swap.ClearingHouseOTC,
+			CurveDate:               curveDate,
+			TradeDate:               curveDate,
+			SwapTenorYears:          5,
+			Notional:                10_000_000,
+			PayLeg:                  swaps.EURIBORFixed,
+			RecLeg:                  swaps.EURIBOR6MFloating,
+			DiscountingOIS:          swaps.ESTRFloating,
+			OISQuotes:               estrQuotes,
+			RecLegQuotes:            euriborQuotes,
+			PayLegSpreadBP:          250,
+			DiscountCurveConvention: conv,
+		})
+		if err != nil {
+			t.Fatalf("InterestRateSwap(%s): %v", conv, err)
+		}
+		return trade
+	}
+
+	oisTrade := build(curve.FixedLegDayCountOIS)
+	iborTrade := build(curve.FixedLegDayCountIBOR)
+	defaultTrade := build("")
+
+	maturity := oisTrade.Spec.MaturityDate
+	dfOIS := oisTrade.DiscountCurve.DF(maturity)
+	dfIBOR := iborTrade.DiscountCurve.DF(maturity)
+	dfDefault := defaultTrade.DiscountCurve.DF(maturity)
+
+	if dfDefault != dfOIS {
+		t.Fatalf("default convention for ESTR discounting should be OIS: got DF %.12f want %.12f", dfDefault, dfOIS)
+	}
+	// 30/360 accrues less than ACT/360 over a year, so the IBOR-convention fixed leg
+	// pays smaller coupons and the bootstrap needs a higher DF to reprice the same par quote.
+	if dfIBOR <= dfOIS {
+		t.Fatalf("expected IBOR-convention DF above OIS-convention DF at %s: ois=%.12f ibor=%.12f",
+			maturity.Format("2006-01-02"), dfOIS, dfIBOR)
+	}
+	t.Logf("DF(%s): OIS=%.10f IBOR=%.10f diff=%.4f bp", maturity.Format("2006-01-02"), dfOIS, dfIBOR, (dfIBOR-dfOIS)*1e4)
+
+	for _, trade := range []*swap.SwapTrade{oisTrade, iborTrade} {
+		if _, err := trade.NPV(); err != nil {
+			t.Fatalf("NPV: %v", err)
+		}
+	}
+
+	if _, err := swap.InterestRateSwap(swap.InterestRateSwapParams{
+		CurveDate:               curveDate,
+		TradeDate:               curveDate,
+		SwapTenorYears:          5,
+		Notional:                1,
+		PayLeg:                  swaps.EURIBORFixed,
+		RecLeg:                  swaps.EURIBOR6MFloating,
+		DiscountingOIS:          swaps.ESTRFloating,
+		OISQuotes:               estrQuotes,
+		RecLegQuotes:            euriborQuotes,
+		DiscountCurveConvention: "BOGUS",
+	}); err == nil {
+		t.Fatalf("expected error for unknown DiscountCurveConvention")
+	}
+}
+
+func TestInterestRateSwap_OvernightLegReusesDiscountCurve(t *testing.T) {
+	t.Parallel()
+
+	curveDate := time.Date(2026, 1, 9, 0, 0, 0, 0, time.UTC)
+	oisQuotes := map[string]float64{
+		"1Y":  3.48945,
+		"2Y":  3.3717,
+		"5Y":  3.49207,
+		"10Y": 3.8005,
+	}
+	params := swap.InterestRateSwapParams{
+		DataSource:     swap.DataSourceBGN,
+		ClearingHouse:  swap.ClearingHouseOTC,
+		CurveDate:      curveDate,
+		TradeDate:      curveDate,
+		SwapTenorYears: 5,
+		Notional:       10_000_000,
+		PayLeg:         swaps.SOFRFixed,
+		RecLeg:         swaps.SOFRFloating,
+		DiscountingOIS: swaps.SOFRFloating,
+		OISQuotes:      oisQuotes,
+		RecLegQuotes:   map[string]float64{"1Y": 3.48945, "2Y": 3.3717, "5Y": 3.49207, "10Y": 3.8005},
+	}
+
+	trade, err := swap.InterestRateSwap(params)
+	if err != nil {
+		t.Fatalf("InterestRateSwap: %v", err)
+	}
+	if trade.RecProjCurve != swap.ProjectionCurve(trade.DiscountCurve) {
+		t.Fatalf("expected rec projection curve to be the discount curve when quotes match OISQuotes")
+	}
+	if len(trade.Notes) == 0 {
+		t.Fatalf("expected a note recording the reused projection curve")
+	}
+
+	// Different quotes still build a separate projection curve.
+	params.RecLegQuotes = map[string]float64{"1Y": 3.5, "2Y": 3.4, "5Y": 3.5, "10Y": 3.8}
+	trade, err = swap.InterestRateSwap(params)
+	if err != nil {
+		t.Fatalf("InterestRateSwap: %v", err)
+	}
+	if trade.RecProjCurve == swap.ProjectionCurve(trade.DiscountCurve) {
+		t.Fatalf("expected a separate projection curve when quotes differ from OISQuotes")
+	}
+}
+
+func TestInterestRateSwap_CurrencyMismatch(t *testing.T) {
+	t.Parallel()
+
+	curveDate := time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)
+	params := swap.InterestRateSwapParams{
+		DataSource:     swap.DataSourceBGN,
+		ClearingHouse:  swap.ClearingHouseOTC,
+		CurveDate:      curveDate,
+		TradeDate:      curveDate,
+		SwapTenorYears: 5,
+		Notional:       10_000_000,
+		PayLeg:         swaps.TIBORFixed,
+		RecLeg:         swaps.TIBOR6MFloating,
+		DiscountingOIS: swaps.ESTRFloating, // a JPY leg on an EUR curve
+		OISQuotes:      map[string]float64{"1Y": 2.06795, "2Y": 2.153975, "5Y": 2.3495, "10Y": 2.6955},
+		RecLegQuotes:   map[string]float64{"1Y": 0.95, "2Y": 1.10, "5Y": 1.40, "10Y": 1.75},
+		PayLegSpreadBP: 140,
+	}
+
+	_, err := swap.InterestRateSwap(params)
+	if !errors.Is(err, swap.ErrCurrencyMismatch) {
+		t.Fatalf("expected a currency mismatch error, got %v", err)
+	}
+
+	params.CrossCurrency = true
+	if _, err := swap.InterestRateSwap(params); err != nil {
+		t.Fatalf("InterestRateSwap with CrossCurrency: %v", err)
+	}
+
+	params.CrossCurrency = false
+	params.DiscountingOIS = swaps.TONARFloating
+	if _, err := swap.InterestRateSwap(params); err != nil {
+		t.Fatalf("InterestRateSwap on a JPY curve: %v", err)
+	}
+}
+
+func TestInterestRateSwap_CalendarMismatch(t *testing.T) {
+	t.Parallel()
+
+	curveDate := time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)
+	params := swap.InterestRateSwapParams{
+		DataSource:     swap.DataSourceBGN,
+		ClearingHouse:  swap.ClearingHouseOTC,
+		CurveDate:      curveDate,
+		TradeDate:      curveDate,
+		SwapTenorYears: 5,
+		Notional:       10_000_000,
+		PayLeg:         swaps.EURIBORFixed,
+		RecLeg:         swaps.TIBOR6MFloating,
+		DiscountingOIS: swaps.ESTRFloating,
+		OISQuotes:      map[string]float64{"1Y": 2.06795, "2Y": 2.153975, "5Y": 2.3495, "10Y": 2.6955},
+		RecLegQuotes:   map[string]float64{"1Y": 0.95, "2Y": 1.10, "5Y": 1.40, "10Y": 1.75},
+		PayLegSpreadBP: 240,
+	}
+
+	// A JPY TIBOR leg on EUR quotes: the error names both calendars.
+	_, err := swap.InterestRateSwap(params)
+	if err == nil {
+		t.Fatalf("expected an error for a TIBOR leg on an ESTR discount curve")
+	}
+	for _, cal := range []calendar.CalendarID{calendar.JP, calendar.TARGET} {
+		if !strings.Contains(err.Error(), string(cal)) {
+			t.Fatalf("error %q does not name calendar %s", err, cal)
+		}
+	}
+
+	// Same currency, wrong calendar.
+	leg := swaps.EURIBOR6MFloating
+	leg.Calendar = calendar.JP
+	params.RecLeg = leg
+	params.RecLegQuotes = map[string]float64{"1Y": 2.25, "2Y": 2.33, "5Y": 2.52, "10Y": 2.84}
+	if _, err := swap.InterestRateSwap(params); !errors.Is(err, swap.ErrCalendarMismatch) {
+		t.Fatalf("expected a calendar mismatch error, got %v", err)
+	}
+
+	params.CrossCurrency = true
+	if _, err := swap.InterestRateSwap(params); err != nil {
+		t.Fatalf("InterestRateSwap with CrossCurrency: %v", err)
+	}
+}
+
+func TestInterestRateSwap_MissingQuotesErrors(t *testing.T) {
+	t.Parallel()
+
+	curveDate := time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)
+	params := swap.InterestRateSwapParams{
+		DataSource:     swap.DataSourceBGN,
+		ClearingHouse:  swap.ClearingHouseOTC,
+		CurveDate:      curveDate,
+		TradeDate:      curveDate,
+		SwapTenorYears: 5,
+		Notional:       10_000_000,
+		PayLeg:         swaps.EURIBORFixed,
+		RecLeg:         swaps.EURIBOR6MFloating,
+		DiscountingOIS: swaps.ESTRFloating,
+		OISQuotes:      map[string]float64{"1Y": 2.06795, "2Y": 2.153975, "5Y": 2.3495, "10Y": 2.6955},
+		PayLegSpreadBP: 240,
+	}
+
+	// An IBOR leg without RecLegQuotes has nothing to build its projection curve from.
+	_, err := swap.InterestRateSwap(params)
+	if !errors.Is(err, swap.ErrMissingProjectionQuotes) || errors.Is(err, swap.ErrMissingOISQuotes) {
+		t.Fatalf("expected ErrMissingProjectionQuotes, got %v", err)
+	}
+	var missing *swap.MissingQuotesError
+	if !errors.As(err, &missing) || missing.Index != market.EURIBOR6M {
+		t.Fatalf("errors.As: %v, index %+v", err, missing)
+	}
+
+	params.RecLegQuotes = map[string]float64{"1Y": 2.25, "2Y": 2.33, "5Y": 2.52, "10Y": 2.84}
+	params.OISQuotes = nil
+	_, err = swap.InterestRateSwap(params)
+	if !errors.Is(err, swap.ErrMissingOISQuotes) || !errors.As(err, &missing) || missing.Index != market.ESTR {
+		t.Fatalf("expected ErrMissingOISQuotes for ESTR, got %v", err)
+	}
+}
+
+func TestInterestRateSwap_Direction(t *testing.T) {
+	t.Parallel()
+
+	curveDate := time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)
+	estrQuotes := map[string]float64{"1Y": 2.06795, "2Y": 2.153975, "5Y": 2.3495, "10Y": 2.6955}
+	euriborQuotes := map[string]float64{"1Y": 2.25, "2Y": 2.33, "5Y": 2.52, "10Y": 2.84}
+	build := func(direction swap.Position) swap.PV {
+		trade, err := swap.InterestRateSwap(swap.InterestRateSwapParams{
+			DataSource:     swap.DataSourceBGN,
+			ClearingHouse:  swap.ClearingHouseOTC,
+			CurveDate:      curveDate,
+			TradeDate:      curveDate,
+			SwapTenorYears: 5,
+			Notional:       10_000_000,
+			Direction:      direction,
+			// The legs name the trade; Direction decides which one is paid.
+			PayLeg:         swaps.EURIBOR6MFloating,
+			RecLeg:         swaps.EURIBORFixed,
+			DiscountingOIS: swaps.ESTRFloating,
+			OISQuotes:      estrQuotes,
+			PayLegQuotes:   euriborQuotes,
+			RecLegSpreadBP: 250,
+		})
+		if err != nil {
+			t.Fatalf("InterestRateSwap(%s): %v", direction, err)
+		}
+		wantPay := market.LegFixed
+		if direction == swap.PositionReceive {
+			wantPay = market.LegFloating
+		}
+		if trade.Spec.PayLeg.LegType != wantPay {
+			t.Fatalf("%s: pay leg is %s, want %s", direction, trade.Spec.PayLeg.LegType, wantPay)
+		}
+		pv, err := trade.PVByLeg()
+		if err != nil {
+			t.Fatalf("PVByLeg(%s): %v", direction, err)
+		}
+		return pv
+	}
+
+	payer, receiver := build(swap.PositionPay), build(swap.PositionReceive)
+	if math.Abs(payer.TotalPV+receiver.TotalPV) > 1e-6 ||
+		math.Abs(payer.PayLegPV+receiver.RecLegPV) > 1e-6 ||
+		math.Abs(payer.RecLegPV+receiver.PayLegPV) > 1e-6 {
+		t.Fatalf("receiver PVs %+v are not the negation of payer PVs %+v", receiver, payer)
+	}
+	if payer.TotalPV == 0 {
+		t.Fatalf("expected an off-market trade")
+	}
+}
+
+func TestSolveParSpread_SOFRvsFedFundsBasis(t *testing.T) {
+	t.Parallel()
+
+	curveDate := time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)
+	sofr := map[string]float64{"1Y": 3.90, "2Y": 3.70, "5Y": 3.60, "10Y": 3.75}
+	effr := map[string]float64{"1Y": 3.95, "2Y": 3.75, "5Y": 3.65, "10Y": 3.80} // SOFR + 5bp
+	fedFunds := swaps.SOFRFloating
+	fedFunds.ReferenceIndex = market.EFFR
+	fedFunds.FixingCalendar = ""
+
+	params := swap.InterestRateSwapParams{
+		DataSource:     swap.DataSourceBGN,
+		ClearingHouse:  swap.ClearingHouseOTC,
+		CurveDate:      curveDate,
+		TradeDate:      curveDate,
+		SwapTenorYears: 5,
+		Notional:       10_000_000,
+		PayLeg:         swaps.SOFRFloating,
+		RecLeg:         fedFunds,
+		DiscountingOIS: swaps.SOFRFloating,
+		OISQuotes:      sofr,
+		PayLegQuotes:   sofr,
+		RecLegQuotes:   effr,
+	}
+	trade, err := swap.InterestRateSwap(params)
+	if err != nil {
+		t.Fatalf("InterestRateSwap: %v", err)
+	}
+	if trade.IsOISBasisSwap {
+		t.Fatalf("SOFR vs Fed Funds is a par-rate basis only with ForceParRateBasis")
+	}
+
+	params.ForceParRateBasis = true
+	if trade, err = swap.InterestRateSwap(params); err != nil {
+		t.Fatalf("InterestRateSwap: %v", err)
+	}
+	if !trade.IsOISBasisSwap || trade.PayProjCurve == trade.RecProjCurve {
+		t.Fatalf("expected a basis swap on two projection curves")
+	}
+	spreadBP, _, err := trade.SolveParSpread(swap.SpreadTargetPayLeg)
+	if err != nil {
+		t.Fatalf("SolveParSpread: %v", err)
+	}
+
+	// The spread is the difference of the legs' par rates on the common SOFR discount curve.
+	payPar, err := swap.ComputeOISParRateWithDiscount(trade.Spec, trade.PayProjCurve.(swap.DiscountCurve), trade.DiscountCurve, trade.ValuationDate, trade.Spec.PayLeg)
+	if err != nil {
+		t.Fatalf("pay leg par rate: %v", err)
+	}
+	recPar, err := swap.ComputeOISParRateWithDiscount(trade.Spec, trade.RecProjCurve.(swap.DiscountCurve), trade.DiscountCurve, trade.ValuationDate, trade.Spec.RecLeg)
+	if err != nil {
+		t.Fatalf("receive leg par rate: %v", err)
+	}
+	if want := (payPar - recPar) * 1e4; math.Abs(spreadBP-want) > 1e-9 {
+		t.Fatalf("basis %.6fbp, want par-rate difference %.6fbp", spreadBP, want)
+	}
+	if math.Abs(spreadBP+5) > 0.5 {
+		t.Fatalf("basis %.4fbp, want about -5bp from the quotes", spreadBP)
+	}
+}
+
+func TestInterestRateSwap_DiscountQuotes(t *testing.T) {
+	t.Parallel()
+
+	curveDate := time.Date(2026, 1, 9, 0, 0, 0, 0, time.UTC)
+	lchQuotes := map[string]float64{"1Y": 0.74, "2Y": 0.86, "5Y": 1.12, "10Y": 1.55}
+	jsccQuotes := map[string]float64{"1Y": 0.745, "2Y": 0.868, "5Y": 1.131, "10Y": 1.562}
+	discQuotes := map[string]float64{"1Y": 0.70, "2Y": 0.80, "5Y": 1.05, "10Y": 1.45}
+	params := swap.InterestRateSwapParams{
+		DataSource:     swap.DataSourceBGN,
+		ClearingHouse:  swap.ClearingHouseOTC,
+		CurveDate:      curveDate,
+		TradeDate:      curveDate,
+		SwapTenorYears: 5,
+		Notional:       10_000_000,
+		PayLeg:         swaps.TONARFloating,
+		RecLeg:         swaps.TONARFloating,
+		DiscountingOIS: swaps.TONARFloating,
+		PayLegQuotes:   lchQuotes,
+		RecLegQuotes:   jsccQuotes,
+		DiscountQuotes: discQuotes,
+	}
+
+	trade, err := swap.InterestRateSwap(params)
+	if err != nil {
+		t.Fatalf("InterestRateSwap: %v", err)
+	}
+	if trade.PayProjCurve == swap.ProjectionCurve(trade.DiscountCurve) ||
+		trade.RecProjCurve == swap.ProjectionCurve(trade.DiscountCurve) {
+		t.Fatalf("expected the discount curve to be separate from both projection curves")
+	}
+
+	at := trade.Spec.MaturityDate
+	df := trade.DiscountCurve.DF(at)
+	payDF, recDF := trade.PayProjCurve.DF(at), trade.RecProjCurve.DF(at)
+	if df <= payDF || df <= recDF {
+		t.Fatalf("DF(%s)=%.8f should exceed projection DFs %.8f / %.8f (lower discount quotes)",
+			at.Format("2006-01-02"), df, payDF, recDF)
+	}
+	want := curve.BuildCurve(trade.DiscountCurve.(*curve.Curve).Settlement(), discQuotes, swaps.TONARFloating.Calendar, 1).DF(at)
+	if math.Abs(df-want) > 1e-12 {
+		t.Fatalf("discount DF=%.12f, want %.12f from DiscountQuotes", df, want)
+	}
+
+	// DiscountQuotes takes precedence over OISQuotes.
+	params.OISQuotes = lchQuotes
+	withOIS, err := swap.InterestRateSwap(params)
+	if err != nil {
+		t.Fatalf("InterestRateSwap: %v", err)
+	}
+	if got := withOIS.DiscountCurve.DF(at); got != df {
+		t.Fatalf("DF with OISQuotes set=%.12f, want %.12f", got, df)
+	}
+
+	// Without DiscountQuotes the pay leg's quotes discount, as before.
+	params.DiscountQuotes = nil
+	legacy, err := swap.InterestRateSwap(params)
+	if err != nil {
+		t.Fatalf("InterestRateSwap: %v", err)
+	}
+	if legacy.PayProjCurve != swap.ProjectionCurve(legacy.DiscountCurve) {
+		t.Fatalf("expected pay projection to reuse the OISQuotes discount curve")
+	}
+}
+
+func TestNPV_MaturedTrade(t *testing.T) {
+	t.Parallel()
+
+	curveDate := time.Date(2026, 1, 9, 0, 0, 0, 0, time.UTC)
+	quotes := map[string]float64{"1Y": 2.07, "2Y": 2.15, "5Y": 2.35}
+	trade, err := swap.InterestRateSwap(swap.InterestRateSwapParams{
+		DataSource:     swap.DataSourceBGN,
+		ClearingHouse:  swap.ClearingHouseOTC,
+		CurveDate:      curveDate,
+		TradeDate:      curveDate,
+		SwapTenorYears: 1,
+		Notional:       10_000_000,
+		PayLeg:         swaps.ESTRFixed,
+		RecLeg:         swaps.ESTRFloating,
+		DiscountingOIS: swaps.ESTRFloating,
+		OISQuotes:      quotes,
+		RecLegQuotes:   quotes,
+		PayLegSpreadBP: 207,
+	})
+	if err != nil {
+		t.Fatalf("InterestRateSwap: %v", err)
+	}
+
+	// On the maturity date the final coupon is still due.
+	trade.ValuationDate = trade.Spec.MaturityDate
+	if _, err := trade.NPV(); err != nil {
+		t.Fatalf("NPV at maturity: %v", err)
+	}
+
+	trade.ValuationDate = trade.Spec.MaturityDate.AddDate(0, 1, 0)
+	if _, err := trade.NPV(); !errors.Is(err, swap.ErrMatured) {
+		t.Fatalf("NPV after maturity: err=%v, want ErrMatured", err)
+	}
+	if _, err := trade.PVByLeg(); !errors.Is(err, swap.ErrMatured) {
+		t.Fatalf("PVByLeg after maturity: err=%v, want ErrMatured", err)
+	}
+}
+
+func TestSpotEffectiveMaturity_ForwardStartConventions(t *testing.T) {
+	t.Parallel()
+
+	// Trade on Thursday 2026-01-08, TARGET calendar, T+2 spot, 5Y x 5Y.
+	tradeDate := time.Date(2026, 1, 8, 0, 0, 0, 0, time.UTC)
+
+	spot, effective, maturity := swap.SpotEffectiveMaturityWithSpotLag(tradeDate, calendar.TARGET, 2, 5, 5)
+	spotFromSpot, effectiveFromSpot, maturityFromSpot := swap.SpotEffectiveMaturityForwardFromSpot(tradeDate, calendar.TARGET, 2, 5, 5)
+
+	wantSpot := time.Date(2026, 1, 12, 0, 0, 0, 0, time.UTC)
+	if !spot.Equal(wantSpot) || !spotFromSpot.Equal(wantSpot) {
+		t.Fatalf("spot mismatch: got %s / %s want %s", spot.Format("2006-01-02"), spotFromSpot.Format("2006-01-02"), wantSpot.Format("2006-01-02"))
+	}
+
+	// Spot-of-forward: (2031-01-08 + 2BD) = Friday 2031-01-10.
+	wantEffective := time.Date(2031, 1, 10, 0, 0, 0, 0, time.UTC)
+	if !effective.Equal(wantEffective) {
+		t.Fatalf("spot-of-forward effective: got %s want %s", effective.Format("2006-01-02"), wantEffective.Format("2006-01-02"))
+	}
+	// Forward-from-spot: 2031-01-12 is a Sunday, rolled to Monday 2031-01-13.
+	wantEffectiveFromSpot := time.Date(2031, 1, 13, 0, 0, 0, 0, time.UTC)
+	if !effectiveFromSpot.Equal(wantEffectiveFromSpot) {
+		t.Fatalf("forward-from-spot effective: got %s want %s", effectiveFromSpot.Format("2006-01-02"), wantEffectiveFromSpot.Format("2006-01-02"))
+	}
+	if !maturity.After(effective) || !maturityFromSpot.After(effectiveFromSpot) {
+		t.Fatalf("maturity must follow effective: %s/%s, %s/%s",
+			effective.Format("2006-01-02"), maturity.Format("2006-01-02"),
+			effectiveFromSpot.Format("2006-01-02"), maturityFromSpot.Format("2006-01-02"))
+	}
+}
+
+func TestSolveParSpreadWithMaxIter_Residual(t *testing.T) {
+	t.Parallel()
+
+	curveDate := time.Date(2026, 1, 9, 0, 0, 0, 0, time.UTC)
+	quotes := map[string]float64{"1Y": 3.48945, "2Y": 3.3717, "5Y": 3.49207, "10Y": 3.8005}
+	payLeg := swaps.SOFRFixed
+	recLeg := swaps.SOFRFloating
+	recLeg.IncludeInitialPrincipal = false
+	recLeg.IncludeFinalPrincipal = false
+
+	trade, err := swap.InterestRateSwap(swap.InterestRateSwapParams{
+		DataSource:     swap.DataSourceBGN,
+		ClearingHouse:  swap.ClearingHouseOTC,
+		CurveDate:      curveDate,
+		TradeDate:      curveDate,
+		SwapTenorYears: 5,
+		Notional:       10_000_000,
+		PayLeg:         payLeg,
+		RecLeg:         recLeg,
+		DiscountingOIS: recLeg,
+		OISQuotes:      quotes,
+		RecLegQuotes:   quotes,
+	})
+	if err != nil {
+		t.Fatalf("InterestRateSwap: %v", err)
+	}
+
+	residual := func(spreadBP float64) float64 {
+		spec := trade.Spec
+		spec.PayLegSpreadBP = spreadBP
+		npv, err := swap.NPV(spec, trade.PayProjCurve, trade.RecProjCurve, trade.DiscountCurve, trade.ValuationDate)
+		if err != nil {
+			t.Fatalf("NPV: %v", err)
+		}
+		return npv
+	}
+
+	solved, err := swap.SolveParSpreadWithMaxIter(trade.Spec, trade.PayProjCurve, trade.RecProjCurve, trade.DiscountCurve, trade.ValuationDate, swap.SpreadTargetPayLeg, 10)
+	if err != nil {
+		t.Fatalf("SolveParSpreadWithMaxIter: %v", err)
+	}
+	if r := residual(solved); math.Abs(r) > 1e-6 {
+		t.Fatalf("expected converged residual ~0, got %.6g", r)
+	}
+
+	capped, err := swap.SolveParSpreadWithMaxIter(trade.Spec, trade.PayProjCurve, trade.RecProjCurve, trade.DiscountCurve, trade.ValuationDate, swap.SpreadTargetPayLeg, 0)
+	if err == nil {
+		t.Fatalf("expected non-convergence error with zero iterations")
+	}
+	if r := residual(capped); math.Abs(r) < 1000 {
+		t.Fatalf("expected large residual for capped run, got %.6g", r)
+	}
+}
+
+func TestSolveSpreadForNPV_UnwindValue(t *testing.T) {
+	t.Parallel()
+
+	curveDate := time.Date(2026, 1, 9, 0, 0, 0, 0, time.UTC)
+	quotes := map[string]float64{"1Y": 3.48945, "2Y": 3.3717, "5Y": 3.49207, "10Y": 3.8005}
+	recLeg := swaps.SOFRFloating
+	recLeg.IncludeInitialPrincipal = false
+	recLeg.IncludeFinalPrincipal = false
+
+	trade, err := swap.InterestRateSwap(swap.InterestRateSwapParams{
+		DataSource:     swap.DataSourceBGN,
+		ClearingHouse:  swap.ClearingHouseOTC,
+		CurveDate:      curveDate,
+		TradeDate:      curveDate,
+		SwapTenorYears: 5,
+		Notional:       10_000_000,
+		PayLeg:         swaps.SOFRFixed,
+		RecLeg:         recLeg,
+		DiscountingOIS: recLeg,
+		OISQuotes:      quotes,
+		RecLegQuotes:   quotes,
+	})
+	if err != nil {
+		t.Fatalf("InterestRateSwap: %v", err)
+	}
+
+	par, err := swap.SolveParSpread(trade.Spec, trade.PayProjCurve, trade.RecProjCurve, trade.DiscountCurve, trade.ValuationDate, swap.SpreadTargetPayLeg)
+	if err != nil {
+		t.Fatalf("SolveParSpread: %v", err)
+	}
+
+	const unwindNPV = 25_000.0
+	spreadBP, err := swap.SolveSpreadForNPV(trade.Spec, trade.PayProjCurve, trade.RecProjCurve, trade.DiscountCurve, trade.ValuationDate, swap.SpreadTargetPayLeg, unwindNPV)
+	if err != nil {
+		t.Fatalf("SolveSpreadForNPV: %v", err)
+	}
+
+	spec := trade.Spec
+	spec.PayLegSpreadBP = spreadBP
+	npv, err := swap.NPV(spec, trade.PayProjCurve, trade.RecProjCurve, trade.DiscountCurve, trade.ValuationDate)
+	if err != nil {
+		t.Fatalf("NPV: %v", err)
+	}
+	if math.Abs(npv-unwindNPV) > 1e-6 {
+		t.Fatalf("NPV at solved spread: got %.6f want %.6f", npv, unwindNPV)
+	}
+	// Receiving a positive unwind value as the fixed payer requires paying a lower rate than par.
+	if spreadBP >= par {
+		t.Fatalf("expected spread below par: got %.6f bp par %.6f bp", spreadBP, par)
+	}
+}
+
+func TestLegPV_RoundCoupons(t *testing.T) {
+	t.Parallel()
+
+	effective := time.Date(2025, 4, 2, 0, 0, 0, 0, time.UTC)
+	maturity := time.Date(2030, 4, 2, 0, 0, 0, 0, time.UTC)
+	disc := curve.NewCurveFromDFs(effective, map[time.Time]float64{
+		effective: 1.0,
+		time.Date(2027, 4, 2, 0, 0, 0, 0, time.UTC): 0.985,
+		maturity: 0.962,
+	}, calendar.JP, 1)
+
+	floatLeg := swaps.TONARFloating
+	floatLeg.IncludeInitialPrincipal = false
+	floatLeg.IncludeFinalPrincipal = false
+	spec := market.SwapSpec{
+		Notional:       1_234_567_891,
+		EffectiveDate:  effective,
+		MaturityDate:   maturity,
+		PayLeg:         swaps.TONARFixed,
+		RecLeg:         floatLeg,
+		PayLegSpreadBP: 73.3,
+	}
+
+	unrounded, err := swap.NPV(spec, nil, disc, disc, effective)
+	if err != nil {
+		t.Fatalf("NPV: %v", err)
+	}
+
+	spec.PayLeg.RoundCoupons = true
+	spec.RecLeg.RoundCoupons = true
+	rounded, err := swap.NPV(spec, nil, disc, disc, effective)
+	if err != nil {
+		t.Fatalf("NPV(rounded): %v", err)
+	}
+
+	// JPY coupons round to whole yen: at most half a yen per coupon on each leg.
+	diff := math.Abs(rounded - unrounded)
+	if diff == 0 || diff > 0.5*10 {
+		t.Fatalf("rounded vs unrounded NPV difference out of bounds: %.6f (rounded %.4f unrounded %.4f)", diff, rounded, unrounded)
+	}
+}
+
+func TestLegPV_SpreadSchedule(t *testing.T) {
+	t.Parallel()
+
+	effective := time.Date(2025, 4, 2, 0, 0, 0, 0, time.UTC)
+	maturity := time.Date(2026, 4, 2, 0, 0, 0, 0, time.UTC)
+	disc := curve.NewCurveFromDFs(effective, map[time.Time]float64{
+		effective: 1.0,
+		maturity:  0.975,
+	}, calendar.TARGET, 1)
+
+	floatLeg := swaps.EURIBOR6MFloating
+	floatLeg.IncludeInitialPrincipal = false
+	floatLeg.IncludeFinalPrincipal = false
+	spec := market.SwapSpec{
+		Notional:       10_000_000,
+		EffectiveDate:  effective,
+		MaturityDate:   maturity,
+		PayLeg:         swaps.EURIBORFixed,
+		RecLeg:         floatLeg,
+		PayLegSpreadBP: 250,
+		RecLegSpreadBP: 5,
+	}
+	periods, err := swap.GenerateSchedule(effective, maturity, floatLeg)
+	if err != nil {
+		t.Fatalf("GenerateSchedule: %v", err)
+	}
+	if len(periods) != 2 {
+		t.Fatalf("expected 2 semi-annual periods, got %d", len(periods))
+	}
+
+	npvAt := func(spec market.SwapSpec) float64 {
+		npv, err := swap.NPV(spec, nil, disc, disc, effective)
+		if err != nil {
+			t.Fatalf("NPV: %v", err)
+		}
+		return npv
+	}
+	flat := npvAt(spec)
+
+	// Step from 10bp to 50bp at the second period; the flat 5bp no longer applies.
+	spec.RecLeg.SpreadSchedule = map[time.Time]float64{
+		effective:            10,
+		periods[1].StartDate: 50,
+	}
+	stepped := npvAt(spec)
+
+	want := 0.0
+	for i, bp := range []float64{10 - 5, 50 - 5} {
+		p := periods[i]
+		accrual := utils.YearFraction(p.StartDate, p.EndDate, string(floatLeg.DayCount))
+		want += spec.Notional * accrual * bp * 1e-4 * disc.DF(p.PayDate)
+	}
+	if got := stepped - flat; math.Abs(got-want) > 1e-6 {
+		t.Fatalf("stepped-minus-flat NPV %.6f, want %.6f", got, want)
+	}
+
+	// An entry after the first period start leaves that period on the flat spread.
+	spec.RecLeg.SpreadSchedule = map[time.Time]float64{periods[1].StartDate: 50}
+	p := periods[1]
+	accrual := utils.YearFraction(p.StartDate, p.EndDate, string(floatLeg.DayCount))
+	want = spec.Notional * accrual * (50 - 5) * 1e-4 * disc.DF(p.PayDate)
+	if got := npvAt(spec) - flat; math.Abs(got-want) > 1e-6 {
+		t.Fatalf("second-period-only step NPV %.6f, want %.6f", got, want)
+	}
+
+	// The par solver moves the flat spread, which only the first period still carries.
+	solved, err := swap.SolveParSpread(spec, nil, disc, disc, effective, swap.SpreadTargetRecLeg)
+	if err != nil {
+		t.Fatalf("SolveParSpread: %v", err)
+	}
+	spec.RecLegSpreadBP = solved
+	if npv := npvAt(spec); math.Abs(npv) > 1e-6 {
+		t.Fatalf("NPV %.6g at the solved spread %.6f bp", npv, solved)
+	}
+
+	// With every period scheduled the flat spread moves nothing, so there is no par spread.
+	spec.RecLeg.SpreadSchedule = map[time.Time]float64{effective: 10}
+	if _, err := swap.SolveParSpread(spec, nil, disc, disc, effective, swap.SpreadTargetRecLeg); err == nil {
+		t.Fatalf("SolveParSpread with a fully scheduled leg: expected error")
+	}
+}
+
+func TestNPV_AmortizingNotionalMatchesBullets(t *testing.T) {
+	t.Parallel()
+
+	settlement := time.Date(2026, 3, 12, 0, 0, 0, 0, time.UTC)
+	disc := curve.BuildCurve(settlement, map[string]float64{
+		"1Y": 2.07, "2Y": 2.15, "3Y": 2.24, "5Y": 2.35, "7Y": 2.48, "10Y": 2.70,
+	}, calendar.TARGET, 1)
+	proj := curve.BuildProjectionCurve(settlement, swaps.EURIBOR6MFloating, map[string]float64{
+		"1Y": 2.25, "2Y": 2.35, "3Y": 2.44, "5Y": 2.56, "7Y": 2.70, "10Y": 2.90,
+	}, disc)
+
+	// The floating leg exchanges principal, so amortization cashflows are priced too.
+	floatLeg := swaps.EURIBOR6MFloating
+	floatLeg.IncludeInitialPrincipal = true
+	floatLeg.IncludeFinalPrincipal = true
+	const notional = 50_000_000
+	base := market.SwapSpec{
+		Notional:       notional,
+		EffectiveDate:  settlement,
+		PayLeg:         swaps.EURIBORFixed,
+		RecLeg:         floatLeg,
+		PayLegSpreadBP: 245,
+		RecLegSpreadBP: 3,
+	}
+
+	// 5Y amortizing 20% of the original notional each year, against five bullets of 20%
+	// maturing on the anniversaries. Steps sit on the unadjusted anniversaries, which
+	// are also the bullets' maturities; 2028-03-12 is a Sunday.
+	amort := base
+	amort.MaturityDate = settlement.AddDate(5, 0, 0)
+	bullets := 0.0
+	for k := 1; k <= 5; k++ {
+		anniversary := settlement.AddDate(k, 0, 0)
+		if k < 5 {
+			amort.NotionalSchedule = append(amort.NotionalSchedule, market.NotionalStep{
+				EffectiveDate: anniversary,
+				Notional:      notional * float64(5-k) / 5,
+			})
+		}
+		bullet := base
+		bullet.Notional = notional / 5
+		bullet.MaturityDate = anniversary
+		npv, err := swap.NPV(bullet, nil, proj, disc, settlement)
+		if err != nil {
+			t.Fatalf("NPV %dY bullet: %v", k, err)
+		}
+		bullets += npv
+	}
+
+	got, err := swap.NPV(amort, nil, proj, disc, settlement)
+	if err != nil {
+		t.Fatalf("NPV amortizing: %v", err)
+	}
+	if math.Abs(got-bullets) > 1e-6 {
+		t.Fatalf("amortizing NPV %.6f, sum of bullets %.6f", got, bullets)
+	}
+	flat := amort
+	flat.NotionalSchedule = nil
+	if flatNPV, _ := swap.NPV(flat, nil, proj, disc, settlement); math.Abs(flatNPV-got) < 1 {
+		t.Fatalf("schedule had no effect: flat %.2f, amortizing %.2f", flatNPV, got)
+	}
+
+	// The par solve's PV01 uses the stepped notional, so one Newton step (then the
+	// convergence check) suffices.
+	amort.PayLegSpreadBP = 0
+	par, err := swap.SolveParSpreadWithMaxIter(amort, nil, proj, disc, settlement, swap.SpreadTargetPayLeg, 2)
+	if err != nil {
+		t.Fatalf("SolveParSpread: %v", err)
+	}
+	amort.PayLegSpreadBP = par
+	if npv, _ := swap.NPV(amort, nil, proj, disc, settlement); math.Abs(npv) > 1e-6 {
+		t.Fatalf("NPV at par coupon %.4fbp = %.8f", par, npv)
+	}
+}
+
+func TestPrincipalPV_DelayFinalPrincipal(t *testing.T) {
+	t.Parallel()
+
+	settlement := time.Date(2026, 1, 13, 0, 0, 0, 0, time.UTC)
+	disc := curve.BuildCurve(settlement, map[string]float64{
+		"1Y": 3.6, "2Y": 3.4, "3Y": 3.4, "5Y": 3.5, "7Y": 3.6, "10Y": 3.8,
+	}, calendar.FD, 1)
+
+	// Zero-coupon fixed legs isolate the final principal: the receive leg takes the
+	// notional back at the end, the pay leg has no exchanges.
+	recLeg := swaps.SOFRFixed
+	recLeg.IncludeFinalPrincipal = true
+	payLeg := swaps.SOFRFixed
+	spec := market.SwapSpec{
+		Notional:      10_000_000,
+		EffectiveDate: settlement,
+		MaturityDate:  time.Date(2031, 1, 13, 0, 0, 0, 0, time.UTC),
+		PayLeg:        payLeg,
+		RecLeg:        recLeg,
+	}
+
+	periods, err := swap.GenerateSchedule(spec.EffectiveDate, spec.MaturityDate, recLeg)
+	if err != nil {
+		t.Fatalf("GenerateSchedule: %v", err)
+	}
+	lastPay := periods[len(periods)-1].PayDate
+	if !lastPay.After(spec.MaturityDate) {
+		t.Fatalf("T+2 last pay date %s not after maturity", lastPay.Format("2006-01-02"))
+	}
+
+	npvAt := func(spec market.SwapSpec) float64 {
+		t.Helper()
+		npv, err := swap.NPV(spec, nil, nil, disc, settlement)
+		if err != nil {
+			t.Fatalf("NPV: %v", err)
+		}
+		return npv
+	}
+
+	if got, want := npvAt(spec), spec.Notional*disc.DF(spec.MaturityDate); math.Abs(got-want) > 1e-6 {
+		t.Fatalf("default final principal PV %.6f, want %.6f (DF at maturity)", got, want)
+	}
+
+	spec.RecLeg.DelayFinalPrincipal = true
+	if got, want := npvAt(spec), spec.Notional*disc.DF(lastPay); math.Abs(got-want) > 1e-6 {
+		t.Fatalf("delayed final principal PV %.6f, want %.6f (DF at last coupon pay date)", got, want)
+	}
+}
+
+func TestNPV_UsesRealizedIBORFixing(t *testing.T) {
+	t.Parallel()
+
+	curveDate := time.Date(2026, 5, 20, 0, 0, 0, 0, time.UTC)
+	params := swap.InterestRateSwapParams{
+		DataSource:     swap.DataSourceBGN,
+		ClearingHouse:  swap.ClearingHouseOTC,
+		CurveDate:      curveDate,
+		TradeDate:      curveDate,
+		ValuationDate:  curveDate,
+		EffectiveDate:  time.Date(2026, 3, 12, 0, 0, 0, 0, time.UTC),
+		MaturityDate:   time.Date(2031, 3, 12, 0, 0, 0, 0, time.UTC),
+		Notional:       10_000_000,
+		PayLeg:         swaps.EURIBORFixed,
+		RecLeg:         swaps.EURIBOR6MFloating,
+		DiscountingOIS: swaps.ESTRFloating,
+		OISQuotes:      map[string]float64{"1Y": 2.07, "2Y": 2.15, "5Y": 2.35, "10Y": 2.70},
+		RecLegQuotes:   map[string]float64{"1Y": 2.25, "2Y": 2.35, "5Y": 2.56, "10Y": 2.90},
+		PayLegSpreadBP: 250,
+	}
+	projected, err := swap.InterestRateSwap(params)
+	if err != nil {
+		t.Fatalf("InterestRateSwap: %v", err)
+	}
+	base, err := projected.NPV()
+	if err != nil {
+		t.Fatalf("NPV: %v", err)
+	}
+
+	// The first 6M period fixed on 2026-03-10 and is in progress at valuation; its
+	// coupon is the published fixing, the rest stay projected.
+	periods, err := swap.GenerateSchedule(params.EffectiveDate, params.MaturityDate, params.RecLeg)
+	if err != nil {
+		t.Fatalf("GenerateSchedule: %v", err)
+	}
+	first := periods[0]
+	repo := market.NewMapFixingRepo()
+	repo.Add(market.EURIBOR6M, first.FixingDate, 2.612)
+	params.Fixings = repo
+	trade, err := swap.InterestRateSwap(params)
+	if err != nil {
+		t.Fatalf("InterestRateSwap: %v", err)
+	}
+	got, err := trade.NPV()
+	if err != nil {
+		t.Fatalf("NPV with fixing: %v", err)
+	}
+
+	proj, disc := trade.RecProjCurve, trade.DiscountCurve
+	accrual := utils.YearFraction(first.StartDate, first.EndDate, string(params.RecLeg.DayCount))
+	forward := (proj.DF(first.StartDate)/proj.DF(first.EndDate) - 1) / accrual
+	want := base + params.Notional*accrual*(0.02612-forward)*disc.DF(first.PayDate)
+	if math.Abs(got-want) > 1e-6 {
+		t.Fatalf("NPV with fixing=%.6f, want %.6f (projected-only %.6f)", got, want, base)
+	}
+
+	// A past fixing date without a published fixing is an error.
+	params.Fixings = market.NewMapFixingRepo()
+	if trade, err = swap.InterestRateSwap(params); err != nil {
+		t.Fatalf("InterestRateSwap: %v", err)
+	}
+	if _, err := trade.NPV(); err == nil {
+		t.Fatalf("expected error for missing EURIBOR fixing")
+	}
+}
+
+func TestPVByLeg_FirstFloatingFixing(t *testing.T) {
+	t.Parallel()
+
+	// A 1Y-forward 5Y swap: the first period fixes a year out, yet its stub rate is agreed.
+	curveDate := time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)
+	trade, err := swap.InterestRateSwap(swap.InterestRateSwapParams{
+		DataSource:        swap.DataSourceBGN,
+		ClearingHouse:     swap.ClearingHouseOTC,
+		CurveDate:         curveDate,
+		TradeDate:         curveDate,
+		ForwardTenorYears: 1,
+		SwapTenorYears:    5,
+		Notional:          10_000_000,
+		PayLeg:            swaps.EURIBORFixed,
+		RecLeg:            swaps.EURIBOR6MFloating,
+		DiscountingOIS:    swaps.ESTRFloating,
+		OISQuotes:         map[string]float64{"1Y": 2.07, "2Y": 2.15, "5Y": 2.35, "10Y": 2.70},
+		RecLegQuotes:      map[string]float64{"1Y": 2.25, "2Y": 2.35, "5Y": 2.56, "10Y": 2.90},
+		PayLegSpreadBP:    250,
+		RecLegSpreadBP:    10,
+	})
+	if err != nil {
+		t.Fatalf("InterestRateSwap: %v", err)
+	}
+	pvAt := func(rate float64) swap.PV {
+		trade.Spec.FirstFloatingFixing = &rate
+		pv, err := trade.PVByLeg()
+		if err != nil {
+			t.Fatalf("PVByLeg: %v", err)
+		}
+		return pv
+	}
+	low, high := pvAt(0.03), pvAt(0.035)
+
+	periods, err := swap.GenerateSchedule(trade.Spec.EffectiveDate, trade.Spec.MaturityDate, trade.Spec.RecLeg)
+	if err != nil {
+		t.Fatalf("GenerateSchedule: %v", err)
+	}
+	first := periods[0]
+	accrual := utils.YearFraction(first.StartDate, first.EndDate, string(trade.Spec.RecLeg.DayCount))
+	want := trade.Spec.Notional * accrual * 0.005 * trade.DiscountCurve.DF(first.PayDate)
+	if got := high.RecLegPV - low.RecLegPV; math.Abs(got-want) > 1e-6 {
+		t.Fatalf("receive leg PV moved %.6f, want %.6f", got, want)
+	}
+	if high.PayLegPV != low.PayLegPV {
+		t.Fatalf("fixed leg PV moved: %.6f -> %.6f", low.PayLegPV, high.PayLegPV)
+	}
+}
+
+func TestLegPV_ForwardFromFixingDate(t *testing.T) {
+	t.Parallel()
+
+	settlement := time.Date(2026, 3, 12, 0, 0, 0, 0, time.UTC)
+	disc := curve.BuildCurve(settlement, map[string]float64{
+		"1Y": 2.07, "2Y": 2.15, "3Y": 2.24, "5Y": 2.35,
+	}, calendar.TARGET, 1)
+	proj := curve.BuildProjectionCurve(settlement, swaps.EURIBOR6MFloating, map[string]float64{
+		"1Y": 2.25, "2Y": 2.45, "3Y": 2.64, "5Y": 2.86,
+	}, disc)
+
+	// Receive a forward-starting EURIBOR 6M leg against a zero fixed coupon, so the NPV
+	// is the floating coupons alone.
+	floatLeg := swaps.EURIBOR6MFloating
+	floatLeg.IncludeInitialPrincipal = false
+	floatLeg.IncludeFinalPrincipal = false
+	fixedLeg := swaps.EURIBORFixed
+	spec := market.SwapSpec{
+		Notional:      10_000_000,
+		EffectiveDate: time.Date(2027, 3, 12, 0, 0, 0, 0, time.UTC),
+		MaturityDate:  time.Date(2029, 3, 12, 0, 0, 0, 0, time.UTC),
+		PayLeg:        fixedLeg,
+		RecLeg:        floatLeg,
+	}
+	standard, err := swap.NPV(spec, nil, proj, disc, settlement)
+	if err != nil {
+		t.Fatalf("NPV: %v", err)
+	}
+
+	spec.RecLeg.ForwardFromFixingDate = true
+	fromFixing, err := swap.NPV(spec, nil, proj, disc, settlement)
+	if err != nil {
+		t.Fatalf("NPV(ForwardFromFixingDate): %v", err)
+	}
+
+	periods, err := swap.GenerateSchedule(spec.EffectiveDate, spec.MaturityDate, spec.RecLeg)
+	if err != nil {
+		t.Fatalf("GenerateSchedule: %v", err)
+	}
+	want := 0.0
+	for _, p := range periods {
+		// Each forward runs from the period's T-2 fixing to the T-2 fixing of its end.
+		next := calendar.AddBusinessDays(calendar.TARGET, p.EndDate, -floatLeg.FixingLagDays)
+		fwd := (proj.DF(p.FixingDate)/proj.DF(next) - 1) / utils.YearFraction(p.FixingDate, next, "ACT/360")
+		want += spec.Notional * utils.YearFraction(p.StartDate, p.EndDate, "ACT/360") * fwd * disc.DF(p.PayDate)
+	}
+	if math.Abs(fromFixing-want) > 1e-6 {
+		t.Fatalf("fixing-date forwards NPV %.6f, want %.6f", fromFixing, want)
+	}
+	if math.Abs(fromFixing-standard) < 1 {
+		t.Fatalf("fixing-date forwards had no effect: %.6f vs %.6f", fromFixing, standard)
+	}
+}
+
+func TestLegPV_InArrearsConvexityVol(t *testing.T) {
+	t.Parallel()
+
+	settlement := time.Date(2026, 3, 12, 0, 0, 0, 0, time.UTC)
+	disc := curve.BuildCurve(settlement, map[string]float64{
+		"1Y": 2.07, "2Y": 2.15, "3Y": 2.24, "5Y": 2.35,
+	}, calendar.TARGET, 1)
+	proj := curve.BuildProjectionCurve(settlement, swaps.EURIBOR6MFloating, map[string]float64{
+		"1Y": 2.25, "2Y": 2.45, "3Y": 2.64, "5Y": 2.86,
+	}, disc)
+
+	// Receive EURIBOR 6M against a zero fixed coupon, so the NPV is the floating coupons.
+	floatLeg := swaps.EURIBOR6MFloating
+	floatLeg.IncludeInitialPrincipal = false
+	floatLeg.IncludeFinalPrincipal = false
+	spec := market.SwapSpec{
+		Notional:      10_000_000,
+		EffectiveDate: time.Date(2027, 3, 12, 0, 0, 0, 0, time.UTC),
+		MaturityDate:  time.Date(2031, 3, 12, 0, 0, 0, 0, time.UTC),
+		PayLeg:        swaps.EURIBORFixed,
+		RecLeg:        floatLeg,
+	}
+	npv := func(reset market.ResetPosition, vol float64) float64 {
+		s := spec
+		s.RecLeg.ResetPosition = reset
+		s.RecLeg.ConvexityVol = vol
+		v, err := swap.NPV(s, nil, proj, disc, settlement)
+		if err != nil {
+			t.Fatalf("NPV(%s, vol %g): %v", reset, vol, err)
+		}
+		return v
+	}
+
+	inAdvance := npv(market.ResetInAdvance, 0)
+	if got := npv(market.ResetInArrears, 0); got != inAdvance {
+		t.Fatalf("in-arrears NPV without vol %.6f, want in-advance %.6f", got, inAdvance)
+	}
+	if got := npv(market.ResetInAdvance, 0.2); got != inAdvance {
+		t.Fatalf("vol moved the in-advance NPV: %.6f vs %.6f", got, inAdvance)
+	}
+
+	arrearsLeg := spec.RecLeg
+	arrearsLeg.ResetPosition = market.ResetInArrears
+	periods, err := swap.GenerateSchedule(spec.EffectiveDate, spec.MaturityDate, arrearsLeg)
+	if err != nil {
+		t.Fatalf("GenerateSchedule: %v", err)
+	}
+	want := inAdvance
+	for _, p := range periods {
+		delta := utils.YearFraction(p.StartDate, p.EndDate, "ACT/360")
+		fwd := (proj.DF(p.StartDate)/proj.DF(p.EndDate) - 1) / delta
+		tFix := utils.YearFraction(settlement, p.FixingDate, "ACT/365F")
+		want += spec.Notional * delta * (fwd * fwd * 0.04 * tFix * delta / (1 + fwd*delta)) * disc.DF(p.PayDate)
+	}
+	got := npv(market.ResetInArrears, 0.2)
+	if math.Abs(got-want) > 1e-6 {
+		t.Fatalf("in-arrears NPV with 20%% vol %.6f, want %.6f", got, want)
+	}
+	if got-inAdvance < 100 {
+		t.Fatalf("convexity adjustment worth %.2f on 10mm 4Y, want a material positive amount", got-inAdvance)
+	}
+
+	spec.RecLeg.ConvexityVol = -0.1
+	if _, err := swap.NPV(spec, nil, proj, disc, settlement); err == nil {
+		t.Fatalf("expected error for a negative convexity vol")
+	}
+}
+
+func TestLegPV_Gearing(t *testing.T) {
+	t.Parallel()
+
+	settlement := time.Date(2026, 3, 12, 0, 0, 0, 0, time.UTC)
+	disc := curve.BuildCurve(settlement, map[string]float64{
+		"1Y": 2.07, "2Y": 2.15, "3Y": 2.24, "5Y": 2.35,
+	}, calendar.TARGET, 1)
+	euribor := map[string]float64{"1Y": 2.25, "2Y": 2.45, "3Y": 2.64, "5Y": 2.86}
+	bumped := make(map[string]float64, len(euribor))
+	for k, v := range euribor {
+		bumped[k] = v + 0.01
+	}
+	proj := curve.BuildProjectionCurve(settlement, swaps.EURIBOR6MFloating, euribor, disc)
+	projUp := curve.BuildProjectionCurve(settlement, swaps.EURIBOR6MFloating, bumped, disc)
+
+	// Receive EURIBOR 6M + 15bp against a 2.5% fixed coupon.
+	floatLeg := swaps.EURIBOR6MFloating
+	floatLeg.IncludeInitialPrincipal = false
+	floatLeg.IncludeFinalPrincipal = false
+	spec := market.SwapSpec{
+		Notional:       10_000_000,
+		EffectiveDate:  time.Date(2026, 3, 12, 0, 0, 0, 0, time.UTC),
+		MaturityDate:   time.Date(2031, 3, 12, 0, 0, 0, 0, time.UTC),
+		PayLeg:         swaps.EURIBORFixed,
+		RecLeg:         floatLeg,
+		PayLegSpreadBP: 250,
+		RecLegSpreadBP: 15,
+	}
+	npv := func(gearing float64, p swap.ProjectionCurve) float64 {
+		s := spec
+		s.RecLeg.Gearing = gearing
+		v, err := swap.NPV(s, nil, p, disc, settlement)
+		if err != nil {
+			t.Fatalf("NPV(gearing %g): %v", gearing, err)
+		}
+		return v
+	}
+
+	if ungeared, one := npv(0, proj), npv(1, proj); ungeared != one {
+		t.Fatalf("gearing 1 NPV %.8f, unset %.8f", one, ungeared)
+	}
+	// Only the index is geared: a 1bp move in the projection curve moves the leg twice as much.
+	sens1 := npv(1, projUp) - npv(1, proj)
+	sens2 := npv(2, projUp) - npv(2, proj)
+	if math.Abs(sens2-2*sens1) > 1e-6 || math.Abs(sens1) < 1 {
+		t.Fatalf("index sensitivity with gearing 2 %.6f, want twice %.6f", sens2, sens1)
+	}
+
+	// The par spread pays for the extra index coupon; the spread's PV01 is unchanged.
+	geared := spec
+	geared.RecLeg.Gearing = 2
+	spread1, err := swap.SolveParSpread(spec, nil, proj, disc, settlement, swap.SpreadTargetRecLeg)
+	if err != nil {
+		t.Fatalf("SolveParSpread: %v", err)
+	}
+	spread2, err := swap.SolveParSpread(geared, nil, proj, disc, settlement, swap.SpreadTargetRecLeg)
+	if err != nil {
+		t.Fatalf("SolveParSpread(gearing 2): %v", err)
+	}
+	geared.RecLegSpreadBP = spread2
+	if v, err := swap.NPV(geared, nil, proj, disc, settlement); err != nil || math.Abs(v) > 1e-4 {
+		t.Fatalf("NPV at geared par spread %.6fbp: %.6f, %v", spread2, v, err)
+	}
+	if spread2 >= spread1 {
+		t.Fatalf("geared par spread %.4fbp not below ungeared %.4fbp", spread2, spread1)
+	}
+}
+
+func TestCashflowReport_CouponFloor(t *testing.T) {
+	t.Parallel()
+
+	// A 2020-style EUR curve: EURIBOR forwards below zero for the first few years.
+	curveDate := time.Date(2020, 6, 10, 0, 0, 0, 0, time.UTC)
+	trade, err := swap.InterestRateSwap(swap.InterestRateSwapParams{
+		DataSource:     swap.DataSourceBGN,
+		ClearingHouse:  swap.ClearingHouseOTC,
+		CurveDate:      curveDate,
+		TradeDate:      curveDate,
+		SwapTenorYears: 10,
+		Notional:       10_000_000,
+		PayLeg:         swaps.EURIBORFixed,
+		RecLeg:         swaps.EURIBOR6MFloating,
+		DiscountingOIS: swaps.ESTRFloating,
+		OISQuotes:      map[string]float64{"1Y": -0.52, "2Y": -0.50, "5Y": -0.38, "10Y": -0.12},
+		RecLegQuotes:   map[string]float64{"1Y": -0.30, "2Y": -0.28, "5Y": -0.12, "10Y": 0.35},
+	})
+	if err != nil {
+		t.Fatalf("InterestRateSwap: %v", err)
+	}
+	unfloored, err := trade.PVByLeg()
+	if err != nil {
+		t.Fatalf("PVByLeg: %v", err)
+	}
+
+	zero := 0.0
+	check := func(spreadBP float64, beforeSpread bool) float64 {
+		trade.Spec.RecLegSpreadBP = spreadBP
+		trade.Spec.RecLeg.CouponFloor = &zero
+		trade.Spec.RecLeg.ClampBeforeSpread = beforeSpread
+		report, err := trade.CashflowReport()
+		if err != nil {
+			t.Fatalf("CashflowReport: %v", err)
+		}
+		floored, open := 0, 0
+		for _, r := range report.RecLeg {
+			if r.Type != swap.CashflowCoupon {
+				continue
+			}
+			index, want := r.ForwardRate, r.ForwardRate+spreadBP*1e-4
+			switch {
+			case beforeSpread && index < 0:
+				want, floored = spreadBP*1e-4, floored+1
+			case !beforeSpread && want < 0:
+				want, floored = 0, floored+1
+			default:
+				open++
+			}
+			if math.Abs(r.Rate-want) > 1e-15 {
+				t.Fatalf("%s: rate %.8f, want %.8f (forward %.8f)", r.AccrualStart.Format("2006-01-02"), r.Rate, want, r.ForwardRate)
+			}
+		}
+		if floored == 0 || open == 0 {
+			t.Fatalf("want floored and unfloored periods, got %d and %d", floored, open)
+		}
+		pv, err := trade.PVByLeg()
+		if err != nil {
+			t.Fatalf("PVByLeg: %v", err)
+		}
+		return pv.RecLegPV
+	}
+
+	if got := check(0, false); got <= unfloored.RecLegPV {
+		t.Fatalf("floored receive leg PV %.2f not above unfloored %.2f", got, unfloored.RecLegPV)
+	}
+	// A 20bp spread on top of a floored index differs from flooring index + spread.
+	if before, after := check(20, true), check(20, false); before <= after {
+		t.Fatalf("floor before spread PV %.2f, after %.2f: want before > after", before, after)
+	}
+
+	capRate := -0.01
+	trade.Spec.RecLeg.CouponCap = &capRate
+	if _, err := trade.PVByLeg(); err == nil {
+		t.Fatalf("expected an error for a floor above the cap")
+	}
+}
+
+func TestNPVWithLegDiscounting(t *testing.T) {
+	t.Parallel()
+
+	settlement := time.Date(2026, 3, 12, 0, 0, 0, 0, time.UTC)
+	estr := map[string]float64{"1Y": 2.07, "2Y": 2.15, "5Y": 2.35, "10Y": 2.70}
+	// The receive leg is collateralised in another currency: its discount curve sits 15bp higher.
+	shifted := make(map[string]float64, len(estr))
+	for k, v := range estr {
+		shifted[k] = v + 0.15
+	}
+	discPay := curve.BuildCurve(settlement, estr, calendar.TARGET, 1)
+	discRec := curve.BuildCurve(settlement, shifted, calendar.TARGET, 1)
+	proj := curve.BuildProjectionCurve(settlement, swaps.EURIBOR6MFloating, map[string]float64{
+		"1Y": 2.25, "2Y": 2.35, "5Y": 2.56, "10Y": 2.90,
+	}, discPay)
+
+	spec := market.SwapSpec{
+		Notional:       10_000_000,
+		EffectiveDate:  settlement,
+		MaturityDate:   time.Date(2033, 3, 14, 0, 0, 0, 0, time.UTC),
+		PayLeg:         swaps.EURIBORFixed,
+		RecLeg:         swaps.EURIBOR6MFloating,
+		PayLegSpreadBP: 250,
+	}
+
+	pvPay, err := swap.PVByLeg(spec, nil, proj, discPay, settlement)
+	if err != nil {
+		t.Fatalf("PVByLeg on pay curve: %v", err)
+	}
+	pvRec, err := swap.PVByLeg(spec, nil, proj, discRec, settlement)
+	if err != nil {
+		t.Fatalf("PVByLeg on receive curve: %v", err)
+	}
+
+	got, err := swap.PVByLegWithLegDiscounting(spec, nil, proj, discPay, discRec, settlement)
+	if err != nil {
+		t.Fatalf("PVByLegWithLegDiscounting: %v", err)
+	}
+	if got.PayLegPV != pvPay.PayLegPV || got.RecLegPV != pvRec.RecLegPV {
+		t.Fatalf("legs = (%.6f, %.6f), want (%.6f, %.6f)", got.PayLegPV, got.RecLegPV, pvPay.PayLegPV, pvRec.RecLegPV)
+	}
+	npv, err := swap.NPVWithLegDiscounting(spec, nil, proj, discPay, discRec, settlement)
+	if err != nil {
+		t.Fatalf("NPVWithLegDiscounting: %v", err)
+	}
+	if npv != got.TotalPV {
+		t.Fatalf("NPVWithLegDiscounting=%.6f, PVByLeg total %.6f", npv, got.TotalPV)
+	}
+	// Higher discounting shrinks the receive leg, so the mismatch costs the receiver.
+	if single := pvPay.TotalPV; npv >= single {
+		t.Fatalf("NPV with higher receive-leg discounting %.2f not below single-curve %.2f", npv, single)
+	}
+
+	// Same curve on both legs reproduces NPV.
+	same, err := swap.NPVWithLegDiscounting(spec, nil, proj, discPay, discPay, settlement)
+	if err != nil {
+		t.Fatalf("NPVWithLegDiscounting: %v", err)
+	}
+	if want, _ := swap.NPV(spec, nil, proj, discPay, settlement); same != want {
+		t.Fatalf("single-curve NPVWithLegDiscounting=%.6f, NPV=%.6f", same, want)
+	}
+}
+
+// npvForSpreadsTrade is a 10Y EURIBOR 6M swap paying fixed, shared by the
+// NPVForSpreads test and benchmarks.
+func npvForSpreadsTrade(tb testing.TB) *swap.SwapTrade {
+	tb.Helper()
+
+	curveDate := time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)
+	trade, err := swap.InterestRateSwap(swap.InterestRateSwapParams{
+		DataSource:     swap.DataSourceBGN,
+		ClearingHouse:  swap.ClearingHouseOTC,
+		CurveDate:      curveDate,
+		TradeDate:      curveDate,
+		SwapTenorYears: 10,
+		Notional:       10_000_000,
+		PayLeg:         swaps.EURIBORFixed,
+		RecLeg:         swaps.EURIBOR6MFloating,
+		DiscountingOIS: swaps.ESTRFloating,
+		OISQuotes:      map[string]float64{"1Y": 2.06795, "2Y": 2.153975, "5Y": 2.3495, "10Y": 2.6955},
+		RecLegQuotes:   map[string]float64{"1Y": 2.25, "2Y": 2.35, "5Y": 2.56, "10Y": 2.90},
+		PayLegSpreadBP: 260,
+	})
+	if err != nil {
+		tb.Fatalf("InterestRateSwap: %v", err)
+	}
+	return trade
+}
+
+var npvForSpreadsGrid = []float64{-50, -10, 0, 3.5, 25, 100}
+
+func TestSwapTrade_NPVForSpreads(t *testing.T) {
+	t.Parallel()
+
+	trade := npvForSpreadsTrade(t)
+	for _, target := range []swap.SpreadTarget{swap.SpreadTargetPayLeg, swap.SpreadTargetRecLeg} {
+		got, err := trade.NPVForSpreads(target, npvForSpreadsGrid)
+		if err != nil {
+			t.Fatalf("NPVForSpreads(%d): %v", target, err)
+		}
+		for i, bp := range npvForSpreadsGrid {
+			spec := trade.Spec
+			if target == swap.SpreadTargetPayLeg {
+				spec.PayLegSpreadBP = bp
+			} else {
+				spec.RecLegSpreadBP = bp
+			}
+			want, err := swap.NPV(spec, trade.PayProjCurve, trade.RecProjCurve, trade.DiscountCurve, trade.ValuationDate)
+			if err != nil {
+				t.Fatalf("NPV: %v", err)
+			}
+			if math.Abs(got[i]-want) > 1e-6 {
+				t.Errorf("target %d spread %.1fbp: NPVForSpreads %.6f, NPV %.6f", target, bp, got[i], want)
+			}
+		}
+	}
+	if trade.Spec.PayLegSpreadBP != 260 || trade.Spec.RecLegSpreadBP != 0 {
+		t.Fatalf("NPVForSpreads modified the trade spreads")
+	}
+}
+
+func BenchmarkSwapTrade_NPVForSpreads(b *testing.B) {
+	trade := npvForSpreadsTrade(b)
+	for b.Loop() {
+		if _, err := trade.NPVForSpreads(swap.SpreadTargetRecLeg, npvForSpreadsGrid); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkSwapTrade_NPVPerSpread(b *testing.B) {
+	trade := npvForSpreadsTrade(b)
+	for b.Loop() {
+		spec := trade.Spec
+		for _, bp := range npvForSpreadsGrid {
+			spec.RecLegSpreadBP = bp
+			if _, err := swap.NPV(spec, trade.PayProjCurve, trade.RecProjCurve, trade.DiscountCurve, trade.ValuationDate); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
+
+func TestSwapTrade_SolveParFixedRate(t *testing.T) {
+	t.Parallel()
+
+	curveDate := time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)
+	build := func(direction swap.Position, recLeg market.LegConvention) (*swap.SwapTrade, error) {
+		return swap.InterestRateSwap(swap.InterestRateSwapParams{
+			DataSource:     swap.DataSourceBGN,
+			ClearingHouse:  swap.ClearingHouseOTC,
+			CurveDate:      curveDate,
+			TradeDate:      curveDate,
+			SwapTenorYears: 5,
+			Notional:       10_000_000,
+			Direction:      direction,
+			PayLeg:         swaps.EURIBORFixed,
+			RecLeg:         recLeg,
+			DiscountingOIS: swaps.ESTRFloating,
+			OISQuotes:      map[string]float64{"1Y": 2.06795, "2Y": 2.153975, "5Y": 2.3495, "10Y": 2.6955},
+			PayLegQuotes:   map[string]float64{"1Y": 2.25, "2Y": 2.35, "5Y": 2.56, "10Y": 2.90},
+			RecLegQuotes:   map[string]float64{"1Y": 2.25, "2Y": 2.35, "5Y": 2.56, "10Y": 2.90},
+			PayLegSpreadBP: 265,
+		})
+	}
+
+	payer, err := build("", swaps.EURIBOR6MFloating)
+	if err != nil {
+		t.Fatalf("InterestRateSwap: %v", err)
+	}
+	ratePct, pv, err := payer.SolveParFixedRate()
+	if err != nil {
+		t.Fatalf("SolveParFixedRate: %v", err)
+	}
+	if math.Abs(pv.TotalPV) > 1e-6 {
+		t.Fatalf("NPV at the par fixed rate: %.6f", pv.TotalPV)
+	}
+	reference, err := build("", swaps.EURIBOR6MFloating)
+	if err != nil {
+		t.Fatalf("InterestRateSwap: %v", err)
+	}
+	spreadBP, _, err := reference.SolveParSpread(swap.SpreadTargetPayLeg)
+	if err != nil {
+		t.Fatalf("SolveParSpread: %v", err)
+	}
+	if ratePct != spreadBP/100 || payer.Spec.PayLegSpreadBP != spreadBP {
+		t.Fatalf("SolveParFixedRate %.10f%%, SolveParSpread(SpreadTargetPayLeg)/100 %.10f%%", ratePct, spreadBP/100)
+	}
+
+	// A receiver has its fixed leg on the receive side; the par rate is the same.
+	receiver, err := build(swap.PositionReceive, swaps.EURIBOR6MFloating)
+	if err != nil {
+		t.Fatalf("InterestRateSwap(REC): %v", err)
+	}
+	recPct, _, err := receiver.SolveParFixedRate()
+	if err != nil {
+		t.Fatalf("SolveParFixedRate(REC): %v", err)
+	}
+	if math.Abs(recPct-ratePct) > 1e-10 || receiver.Spec.RecLegSpreadBP != recPct*100 {
+		t.Fatalf("receiver par rate %.10f%%, payer %.10f%%", recPct, ratePct)
+	}
+
+	fixedFixed, err := build("", swaps.EURIBORFixed)
+	if err != nil {
+		t.Fatalf("InterestRateSwap(fixed/fixed): %v", err)
+	}
+	if _, _, err := fixedFixed.SolveParFixedRate(); err == nil {
+		t.Fatalf("expected an error with both legs fixed")
+	}
+}
+
+func TestSwapTrade_FairLevels(t *testing.T) {
+	t.Parallel()
+
+	curveDate := time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)
+	estrQuotes := map[string]float64{"1Y": 2.06795, "2Y": 2.153975, "5Y": 2.3495, "10Y": 2.6955}
+	euriborQuotes := map[string]float64{"1Y": 2.25, "2Y": 2.35, "5Y": 2.56, "10Y": 2.90}
+
+	trade, err := swap.InterestRateSwap(swap.InterestRateSwapParams{
+		DataSource:     swap.DataSourceBGN,
+		ClearingHouse:  swap.ClearingHouseOTC,
+		CurveDate:      curveDate,
+		TradeDate:      curveDate,
+		SwapTenorYears: 5,
+		Notional:       10_000_000,
+		PayLeg:         swaps.EURIBORFixed,
+		RecLeg:         swaps.EURIBOR6MFloating,
+		DiscountingOIS: swaps.ESTRFloating,
+		OISQuotes:      estrQuotes,
+		RecLegQuotes:   euriborQuotes,
+		PayLegSpreadBP: 265,
+	})
+	if err != nil {
+		t.Fatalf("InterestRateSwap: %v", err)
+	}
+
+	fixedPct, floatBP, err := trade.FairLevels()
+	if err != nil {
+		t.Fatalf("FairLevels: %v", err)
+	}
+	if trade.Spec.PayLegSpreadBP != 265 || trade.Spec.RecLegSpreadBP != 0 {
+		t.Fatalf("FairLevels modified the trade spec: pay=%.6f rec=%.6f", trade.Spec.PayLegSpreadBP, trade.Spec.RecLegSpreadBP)
+	}
+
+	npvAt := func(payBP, recBP float64) float64 {
+		spec := trade.Spec
+		spec.PayLegSpreadBP = payBP
+		spec.RecLegSpreadBP = recBP
+		npv, err := swap.NPV(spec, trade.PayProjCurve, trade.RecProjCurve, trade.DiscountCurve, trade.ValuationDate)
+		if err != nil {
+			t.Fatalf("NPV: %v", err)
+		}
+		return npv
+	}
+
+	if npv := npvAt(fixedPct*100, 0); math.Abs(npv) > 1e-4 {
+		t.Fatalf("NPV at fair fixed rate %.6f%%: %.6f", fixedPct, npv)
+	}
+	if npv := npvAt(265, floatBP); math.Abs(npv) > 1e-4 {
+		t.Fatalf("NPV at fair float spread %.6f bp: %.6f", floatBP, npv)
+	}
+
+	// The float spread compensates the off-market fixed coupon PV01 for PV01.
+	pv01Fixed := npvAt(264, 0) - npvAt(265, 0)
+	pv01Float := npvAt(265, 1) - npvAt(265, 0)
+	want := (265 - fixedPct*100) * pv01Fixed / pv01Float
+	if math.Abs(floatBP-want) > 1e-6 {
+		t.Fatalf("float spread %.8f bp inconsistent with fixed rate: want %.8f bp", floatBP, want)
+	}
+}
+
+func TestSwapTrade_ForwardParRate5Y5Y(t *testing.T) {
+	t.Parallel()
+
+	curveDate := time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)
+	estrQuotes := map[string]float64{"1Y": 2.06795, "2Y": 2.153975, "5Y": 2.3495, "10Y": 2.6955, "15Y": 2.85}
+	euriborQuotes := map[string]float64{"1Y": 2.25, "2Y": 2.35, "5Y": 2.56, "10Y": 2.90, "15Y": 3.05}
+
+	floatLeg := swaps.EURIBOR6MFloating
+	floatLeg.IncludeInitialPrincipal = false
+	floatLeg.IncludeFinalPrincipal = false
+	trade, err := swap.InterestRateSwap(swap.InterestRateSwapParams{
+		DataSource:        swap.DataSourceBGN,
+		ClearingHouse:     swap.ClearingHouseOTC,
+		CurveDate:         curveDate,
+		TradeDate:         curveDate,
+		ForwardTenorYears: 5,
+		SwapTenorYears:    5,
+		Notional:          10_000_000,
+		PayLeg:            swaps.EURIBORFixed,
+		RecLeg:            floatLeg,
+		DiscountingOIS:    swaps.ESTRFloating,
+		OISQuotes:         estrQuotes,
+		RecLegQuotes:      euriborQuotes,
+		PayLegSpreadBP:    300,
+	})
+	if err != nil {
+		t.Fatalf("InterestRateSwap: %v", err)
+	}
+
+	fwd, err := trade.ForwardParRate()
+	if err != nil {
+		t.Fatalf("ForwardParRate: %v", err)
+	}
+	solvedPct, _, err := trade.FairLevels()
+	if err != nil {
+		t.Fatalf("FairLevels: %v", err)
+	}
+	if math.Abs(fwd-solvedPct) > 1e-8 {
+		t.Fatalf("5Y5Y forward par rate %.10f%% vs solved fixed rate %.10f%%", fwd, solvedPct)
+	}
+
+	// A 5Y5Y forward sits above the 5Y spot rate on an upward-sloping curve.
+	if fwd <= euriborQuotes["5Y"] {
+		t.Fatalf("5Y5Y forward %.6f%% should exceed the 5Y par quote %.6f%%", fwd, euriborQuotes["5Y"])
+	}
+}
+
+func TestOISPresets_ParRateRepricesQuote(t *testing.T) {
+	t.Parallel()
+
+	curveDate := time.Date(2026, 1, 9, 0, 0, 0, 0, time.UTC)
+	quotes := map[string]float64{"1Y": 3.48945, "2Y": 3.3717, "3Y": 3.3905, "5Y": 3.49207, "7Y": 3.62035, "10Y": 3.8005}
+
+	for _, tc := range []struct {
+		name   string
+		preset swaps.OISPreset
+	}{
+		{"SOFR", swaps.OISSOFR},
+		{"CORRA", swaps.OISCORRA},
+	} {
+		fixedLeg := tc.preset.FixedLeg
+		floatLeg := tc.preset.FloatLeg
+		floatLeg.IncludeInitialPrincipal = false
+		floatLeg.IncludeFinalPrincipal = false
+
+		trade, err := swap.InterestRateSwap(swap.InterestRateSwapParams{
+			DataSource:     swap.DataSourceBGN,
+			ClearingHouse:  swap.ClearingHouseOTC,
+			CurveDate:      curveDate,
+			TradeDate:      curveDate,
+			SwapTenorYears: 5,
+			Notional:       10_000_000,
+			PayLeg:         floatLeg,
+			RecLeg:         fixedLeg,
+			DiscountingOIS: tc.preset.FloatLeg,
+			OISQuotes:      quotes,
+			PayLegQuotes:   quotes,
+		})
+		if err != nil {
+			t.Fatalf("%s: InterestRateSwap: %v", tc.name, err)
+		}
+
+		parBP, pv, err := trade.SolveParSpread(swap.SpreadTargetRecLeg)
+		if err != nil {
+			t.Fatalf("%s: SolveParSpread: %v", tc.name, err)
+		}
+		if math.Abs(pv.TotalPV) > 1e-4 {
+			t.Fatalf("%s: NPV at par %.6f, want ~0", tc.name, pv.TotalPV)
+		}
+		// A spot-starting swap on a quoted tenor reprices its bootstrap quote.
+		if diffBP := parBP - quotes["5Y"]*100; math.Abs(diffBP) > 0.1 {
+			t.Fatalf("%s: 5Y par rate %.6f%% vs quote %.6f%% (%.3f bp)", tc.name, parBP/100, quotes["5Y"], diffBP)
+		}
+	}
+}
+
+func TestSwapTrade_ParSwapRateRounded(t *testing.T) {
+	t.Parallel()
+
+	curveDate := time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)
+	estrQuotes := map[string]float64{"1Y": 2.06795, "2Y": 2.153975, "5Y": 2.3495, "10Y": 2.6955}
+	euriborQuotes := map[string]float64{"1Y": 2.25, "2Y": 2.35, "5Y": 2.56, "10Y": 2.90}
+
+	trade, err := swap.InterestRateSwap(swap.InterestRateSwapParams{
+		DataSource:     swap.DataSourceBGN,
+		ClearingHouse:  swap.ClearingHouseLCH,
+		CurveDate:      curveDate,
+		TradeDate:      curveDate,
+		SwapTenorYears: 7,
+		Notional:       10_000_000,
+		PayLeg:         swaps.EURIBORFixed,
+		RecLeg:         swaps.EURIBOR6MFloating,
+		DiscountingOIS: swaps.ESTRFloating,
+		OISQuotes:      estrQuotes,
+		RecLegQuotes:   euriborQuotes,
+	})
+	if err != nil {
+		t.Fatalf("InterestRateSwap: %v", err)
+	}
+	raw, _, err := trade.FairLevels()
+	if err != nil {
+		t.Fatalf("FairLevels: %v", err)
+	}
+
+	for _, tc := range []struct {
+		venue swap.ClearingHouse
+		step  float64 // precision in percent
+	}{
+		{swap.ClearingHouseLCH, 1e-5},
+		{swap.ClearingHouseKRX, 1e-4},
+	} {
+		got, err := trade.ParSwapRateRounded(tc.venue)
+		if err != nil {
+			t.Fatalf("ParSwapRateRounded(%s): %v", tc.venue, err)
+		}
+		if math.Abs(got-raw) > tc.step/2+1e-12 {
+			t.Fatalf("%s: rounded %.10f too far from raw %.10f", tc.venue, got, raw)
+		}
+		if steps := got / tc.step; math.Abs(steps-math.Round(steps)) > 1e-6 {
+			t.Fatalf("%s: %.10f is not a multiple of %g", tc.venue, got, tc.step)
+		}
+	}
+
+	if _, err := trade.ParSwapRateRounded(swap.ClearingHouseOTC); err == nil {
+		t.Fatalf("expected error for a venue without par rate rounding")
+	}
+}
+
+func TestSwapTrade_CurveSnapshotRepricesParQuotes(t *testing.T) {
+	t.Parallel()
+
+	curveDate := time.Date(2026, 1, 9, 0, 0, 0, 0, time.UTC)
+	quotes := map[string]float64{"1Y": 3.48945, "2Y": 3.3717, "5Y": 3.49207, "10Y": 3.8005}
+	fixedLeg := swaps.SOFRFixed
+	floatLeg := swaps.SOFRFloating
+	floatLeg.IncludeInitialPrincipal = false
+	floatLeg.IncludeFinalPrincipal = false
+
+	trade, err := swap.InterestRateSwap(swap.InterestRateSwapParams{
+		DataSource:     swap.DataSourceBGN,
+		ClearingHouse:  swap.ClearingHouseOTC,
+		CurveDate:      curveDate,
+		TradeDate:      curveDate,
+		SwapTenorYears: 5,
+		Notional:       1_000_000,
+		PayLeg:         fixedLeg,
+		RecLeg:         floatLeg,
+		DiscountingOIS: floatLeg,
+		OISQuotes:      quotes,
+		RecLegQuotes:   quotes,
+	})
+	if err != nil {
+		t.Fatalf("InterestRateSwap: %v", err)
+	}
+
+	disc, projPay, projRec, err := trade.CurveSnapshot()
+	if err != nil {
+		t.Fatalf("CurveSnapshot: %v", err)
+	}
+	if len(projPay.Dates) != 0 {
+		t.Fatalf("expected empty pay projection snapshot for fixed leg, got %d nodes", len(projPay.Dates))
+	}
+	if len(disc.Dates) == 0 || len(disc.Dates) != len(disc.DiscountFactors) || len(projRec.Dates) == 0 {
+		t.Fatalf("unexpected snapshot sizes: disc=%d/%d rec=%d", len(disc.Dates), len(disc.DiscountFactors), len(projRec.Dates))
+	}
+
+	dfs := make(map[time.Time]float64, len(disc.Dates))
+	for i, d := range disc.Dates {
+		dfs[d] = disc.DiscountFactors[i]
+	}
+	rebuilt := curve.NewCurveFromDFs(disc.Settlement, dfs, calendar.FD, 0)
+
+	for tenor, years := range map[string]int{"1Y": 1, "2Y": 2, "5Y": 5, "10Y": 10} {
+		spec := market.SwapSpec{
+			Notional:      1_000_000,
+			EffectiveDate: disc.Settlement,
+			MaturityDate:  calendar.Adjust(calendar.FD, disc.Settlement.AddDate(years, 0, 0)),
+			PayLeg:        fixedLeg,
+			RecLeg:        floatLeg,
+		}
+		parBP, err := swap.SolveParSpread(spec, nil, rebuilt, rebuilt, disc.Settlement, swap.SpreadTargetPayLeg)
+		if err != nil {
+			t.Fatalf("%s: SolveParSpread: %v", tenor, err)
+		}
+		// Tolerance covers schedule differences between the bootstrap's fixed coupons and GenerateSchedule.
+		if diff := math.Abs(parBP/100.0 - quotes[tenor]); diff > 0.005 {
+			t.Fatalf("%s: snapshot par rate %.6f%% vs quote %.6f%% (diff %.4f bp)", tenor, parBP/100.0, quotes[tenor], diff*100)
+		}
+	}
+}
+
+func TestLegPV_CompoundsSubPeriodResets(t *testing.T) {
+	t.Parallel()
+
+	effective := time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC)
+	maturity := time.Date(2025, 7, 2, 0, 0, 0, 0, time.UTC)
+	mid := time.Date(2025, 4, 2, 0, 0, 0, 0, time.UTC)
+	notional := 1_000_000.0
+
+	// 3% simple ACT/360 forwards on each 3M sub-period.
+	df1 := 1.0 / (1.0 + 0.03*utils.YearFraction(effective, mid, "ACT/360"))
+	df2 := df1 / (1.0 + 0.03*utils.YearFraction(mid, maturity, "ACT/360"))
+	disc := curve.NewCurveFromDFs(effective, map[time.Time]float64{effective: 1.0, mid: df1, maturity: df2}, calendar.TARGET, 0)
+
+	floatLeg := swaps.EURIBOR3MFloating
+	floatLeg.PayFrequency = market.FreqSemi
+	floatLeg.FixingLagDays = 0
+	floatLeg.IncludeInitialPrincipal = false
+	floatLeg.IncludeFinalPrincipal = false
+	zeroFixed := swaps.EURIBORFixed
+	zeroFixed.PayFrequency = market.FreqSemi
+
+	fixingPct := 2.0
+	spec := market.SwapSpec{
+		Notional:            notional,
+		EffectiveDate:       effective,
+		MaturityDate:        maturity,
+		PayLeg:              zeroFixed,
+		RecLeg:              floatLeg,
+		RecLegFirstResetPct: &fixingPct,
+	}
+	got, err := swap.NPV(spec, nil, disc, disc, effective)
+	if err != nil {
+		t.Fatalf("NPV: %v", err)
+	}
+
+	tau1 := utils.YearFraction(effective, mid, "ACT/360")
+	tau2 := utils.YearFraction(mid, maturity, "ACT/360")
+	compounded := notional * ((1+0.02*tau1)*(1+0.03*tau2) - 1) * df2
+	singleForward := notional * 0.02 * (tau1 + tau2) * df2
+
+	if math.Abs(got-compounded) > 1e-6 {
+		t.Fatalf("compounded coupon PV mismatch: got %.6f want %.6f (single-forward would be %.6f)", got, compounded, singleForward)
+	}
+	if math.Abs(got-singleForward) < 100 {
+		t.Fatalf("expected compounding to differ from applying the fixing to the whole period: got %.6f single %.6f", got, singleForward)
+	}
+
+	// Without a fixing, compounding forwards off one curve telescopes to the 6M forward.
+	spec.RecLegFirstResetPct = nil
+	got, err = swap.NPV(spec, nil, disc, disc, effective)
+	if err != nil {
+		t.Fatalf("NPV: %v", err)
+	}
+	want := notional * (1.0/df2 - 1.0) * df2
+	if math.Abs(got-want) > 1e-6 {
+		t.Fatalf("curve-implied compounded coupon PV mismatch: got %.6f want %.6f", got, want)
+	}
+}
+
+func TestNPV_FirstResetPeriodSelection(t *testing.T) {
+	t.Parallel()
+
+	// A 1Y quarterly EURIBOR leg valued in its second period, after the first coupon paid.
+	effective := time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC)
+	maturity := time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC)
+	valuation := time.Date(2025, 5, 15, 0, 0, 0, 0, time.UTC)
+	tau := func(d time.Time) float64 { return utils.YearFraction(effective, d, "ACT/365F") }
+	disc := curve.NewCurveFromDFs(effective, map[time.Time]float64{
+		effective:                 1,
+		maturity:                  math.Exp(-0.025 * tau(maturity)),
+		maturity.AddDate(1, 0, 0): math.Exp(-0.025 * tau(maturity.AddDate(1, 0, 0))),
+	}, calendar.TARGET, 0)
+
+	floatLeg := swaps.EURIBOR3MFloating
+	floatLeg.FixingLagDays = 0
+	floatLeg.IncludeInitialPrincipal = false
+	floatLeg.IncludeFinalPrincipal = false
+	staleReset := 9.0
+	spec := market.SwapSpec{
+		Notional:      1_000_000,
+		EffectiveDate: effective,
+		MaturityDate:  maturity,
+		PayLeg:        swaps.EURIBORFixed,
+		RecLeg:        floatLeg,
+	}
+	base, err := swap.NPV(spec, nil, disc, disc, valuation)
+	if err != nil {
+		t.Fatalf("NPV: %v", err)
+	}
+
+	// The override belongs to the period starting at EffectiveDate, already paid here.
+	spec.RecLegFirstResetPct = &staleReset
+	got, err := swap.NPV(spec, nil, disc, disc, valuation)
+	if err != nil {
+		t.Fatalf("NPV: %v", err)
+	}
+	if math.Abs(got-base) > 1e-9 {
+		t.Fatalf("paid first period's reset moved NPV: %.6f vs %.6f", got, base)
+	}
+
+	// Opting in moves it onto the current period.
+	spec.FirstResetOnCurrentPeriod = true
+	if got, err = swap.NPV(spec, nil, disc, disc, valuation); err != nil {
+		t.Fatalf("NPV: %v", err)
+	}
+	periods, err := swap.GenerateSchedule(effective, maturity, floatLeg)
+	if err != nil {
+		t.Fatalf("GenerateSchedule: %v", err)
+	}
+	cur := periods[1]
+	accrual := utils.YearFraction(cur.StartDate, cur.EndDate, string(floatLeg.DayCount))
+	forward := (disc.DF(cur.StartDate)/disc.DF(cur.EndDate) - 1) / accrual
+	want := base + spec.Notional*accrual*(staleReset/100-forward)*disc.DF(cur.PayDate)
+	if math.Abs(got-want) > 1e-6 {
+		t.Fatalf("current-period reset NPV %.6f, want %.6f", got, want)
+	}
+}
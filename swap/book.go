@@ -0,0 +1,215 @@
+package swap
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/meenmo/molib/swap/market"
+	"github.com/meenmo/molib/utils"
+)
+
+// dv01ShiftBP is the parallel zero-rate bump used for DV01.
+const dv01ShiftBP = 1.0
+
+// shiftedCurve applies a parallel shift to the continuously-compounded zero rates of a
+// base curve, measured ACT/365F from anchor.
+type shiftedCurve struct {
+	base    ProjectionCurve
+	anchor  time.Time
+	shiftBP float64
+}
+
+func (c shiftedCurve) DF(t time.Time) float64 {
+	tau := utils.YearFraction(c.anchor, t, "ACT/365F")
+	return c.base.DF(t) * math.Exp(-c.shiftBP*1e-4*tau)
+}
+
+func (c shiftedCurve) ZeroRateAt(t time.Time) float64 {
+	if dc, ok := c.base.(DiscountCurve); ok {
+		return dc.ZeroRateAt(t) + c.shiftBP*1e-2
+	}
+	tau := utils.YearFraction(c.anchor, t, "ACT/365F")
+	if tau == 0 {
+		return 0
+	}
+	return -math.Log(c.DF(t)) / tau * 100
+}
+
+// DV01 returns the NPV change for a +1bp parallel shift of the discount and projection
+// curves (zero rates, continuously compounded, measured from the valuation date).
+func (t *SwapTrade) DV01() (float64, error) {
+	if isNilInterface(t.DiscountCurve) {
+		return 0, ErrNilCurve
+	}
+	return t.shiftedNPVPerBP(dv01ShiftBP, func(c ProjectionCurve) ProjectionCurve {
+		return shiftedCurve{base: c, anchor: t.ValuationDate, shiftBP: dv01ShiftBP}
+	})
+}
+
+// ParallelDV01 returns the NPV change per bp for a parallel shift of bumpBP in the zero
+// rates of the discount and projection curves. Curves implementing ZeroShifter are
+// shifted natively (zeros measured from the curve's settlement) with no rebootstrap;
+// others are wrapped as in DV01.
+func (t *SwapTrade) ParallelDV01(bumpBP float64) (float64, error) {
+	if isNilInterface(t.DiscountCurve) {
+		return 0, ErrNilCurve
+	}
+	if bumpBP == 0 {
+		return 0, fmt.Errorf("ParallelDV01: bumpBP must be non-zero")
+	}
+	return t.shiftedNPVPerBP(bumpBP, func(c ProjectionCurve) ProjectionCurve {
+		if zs, ok := asZeroShifter(c); ok {
+			return zs.ShiftZero(bumpBP)
+		}
+		return shiftedCurve{base: c, anchor: t.ValuationDate, shiftBP: bumpBP}
+	})
+}
+
+// shiftedNPVPerBP reprices the trade with every curve passed through shift (nil
+// projection curves stay nil) and returns the NPV change divided by bumpBP.
+func (t *SwapTrade) shiftedNPVPerBP(bumpBP float64, shift func(ProjectionCurve) ProjectionCurve) (float64, error) {
+	shiftOrNil := func(c ProjectionCurve) ProjectionCurve {
+		if isNilInterface(c) {
+			return nil
+		}
+		return shift(c)
+	}
+
+	base, err := t.NPV()
+	if err != nil {
+		return 0, err
+	}
+	disc := shift(t.DiscountCurve).(DiscountCurve)
+	bumped, err := NPV(t.Spec, shiftOrNil(t.PayProjCurve), shiftOrNil(t.RecProjCurve), disc, t.ValuationDate)
+	if err != nil {
+		return 0, err
+	}
+	return (bumped - base) / bumpBP, nil
+}
+
+// PrincipalDV01 returns the change in PV of the spec's notional exchanges alone (both legs)
+// for a +1bp parallel shift of the discount curve's zero rates, measured from the
+// valuation date. Coupon flows are excluded, isolating the principal risk of notional-
+// exchanging legs (cross-currency swaps, FRNs) from total DV01.
+func PrincipalDV01(spec market.SwapSpec, discCurve DiscountCurve, valuationDate time.Time) (float64, error) {
+	if isNilInterface(discCurve) {
+		return 0, ErrNilCurve
+	}
+	if err := validateSwapSpec(spec); err != nil {
+		return 0, fmt.Errorf("PrincipalDV01: %w", err)
+	}
+
+	pv := func(c DiscountCurve) float64 {
+		return principalPV(spec, spec.PayLeg, c, valuationDate, true) +
+			principalPV(spec, spec.RecLeg, c, valuationDate, false)
+	}
+	bumped := shiftedCurve{base: discCurve, anchor: valuationDate, shiftBP: dv01ShiftBP}
+	return (pv(bumped) - pv(discCurve)) / dv01ShiftBP, nil
+}
+
+// DiscountNodeDeltas returns the trade's discounting risk ladder: the NPV change per unit
+// change in each discount-curve node DF, keyed by node tenor as in
+// curve.Curve.NodeSensitivities. Each cashflow still due contributes its PV/DF times the
+// partial of DF at its pay date; projected coupons are held fixed, so this is the
+// sensitivity to discounting alone, assembled analytically with no curve rebuild.
+//
+// The discount curve must implement NodeSensitivityCurve and return sensitivities.
+func (t *SwapTrade) DiscountNodeDeltas() (map[float64]float64, error) {
+	if isNilInterface(t.DiscountCurve) {
+		return nil, ErrNilCurve
+	}
+	nsc, ok := t.DiscountCurve.(NodeSensitivityCurve)
+	if !ok {
+		return nil, fmt.Errorf("DiscountNodeDeltas: discount curve %T has no node sensitivities", t.DiscountCurve)
+	}
+	report, err := t.CashflowReport()
+	if err != nil {
+		return nil, fmt.Errorf("DiscountNodeDeltas: %w", err)
+	}
+
+	var dates []time.Time
+	var amounts []float64 // PV/DF: the signed cashflow each DF multiplies
+	for _, rows := range [][]CashflowRow{report.PayLeg, report.RecLeg} {
+		for _, row := range rows {
+			if row.DF == 0 {
+				continue
+			}
+			dates = append(dates, row.PayDate)
+			amounts = append(amounts, row.PV/row.DF)
+		}
+	}
+	sens := nsc.NodeSensitivities(dates)
+	if sens == nil {
+		return nil, fmt.Errorf("DiscountNodeDeltas: discount curve %T has no node sensitivities", t.DiscountCurve)
+	}
+
+	deltas := make(map[float64]float64, len(sens))
+	for tenor, row := range sens {
+		for i, d := range row {
+			deltas[tenor] += amounts[i] * d
+		}
+	}
+	return deltas, nil
+}
+
+// NetBook sums NPV and DV01 across a book of trades, e.g. to report the residual risk
+// of back-to-back or compressible positions.
+func NetBook(trades []*SwapTrade) (netNPV float64, netDV01 float64, err error) {
+	for i, t := range trades {
+		if t == nil {
+			return 0, 0, fmt.Errorf("NetBook: trade %d is nil", i)
+		}
+		npv, err := t.NPV()
+		if err != nil {
+			return 0, 0, fmt.Errorf("NetBook: trade %d: %w", i, err)
+		}
+		dv01, err := t.DV01()
+		if err != nil {
+			return 0, 0, fmt.Errorf("NetBook: trade %d: %w", i, err)
+		}
+		netNPV += npv
+		netDV01 += dv01
+	}
+	return netNPV, netDV01, nil
+}
+
+// OffsettingPairs greedily pairs trades whose combined NPV is within npvTolerance of zero
+// and whose combined DV01 is within dv01Tolerance of zero, returning index pairs into
+// trades. The tolerances are separate because DV01 is several orders of magnitude smaller
+// than NPV for the same notional. Each trade appears at most once.
+func OffsettingPairs(trades []*SwapTrade, npvTolerance, dv01Tolerance float64) ([][2]int, error) {
+	npvs := make([]float64, len(trades))
+	dv01s := make([]float64, len(trades))
+	for i, t := range trades {
+		if t == nil {
+			return nil, fmt.Errorf("OffsettingPairs: trade %d is nil", i)
+		}
+		var err error
+		if npvs[i], err = t.NPV(); err != nil {
+			return nil, fmt.Errorf("OffsettingPairs: trade %d: %w", i, err)
+		}
+		if dv01s[i], err = t.DV01(); err != nil {
+			return nil, fmt.Errorf("OffsettingPairs: trade %d: %w", i, err)
+		}
+	}
+
+	used := make([]bool, len(trades))
+	var pairs [][2]int
+	for i := range trades {
+		if used[i] {
+			continue
+		}
+		for j := i + 1; j < len(trades); j++ {
+			if used[j] {
+				continue
+			}
+			if math.Abs(npvs[i]+npvs[j]) <= npvTolerance && math.Abs(dv01s[i]+dv01s[j]) <= dv01Tolerance {
+				used[i], used[j] = true, true
+				pairs = append(pairs, [2]int{i, j})
+				break
+			}
+		}
+	}
+	return pairs, nil
+}
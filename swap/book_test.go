@@ -0,0 +1,246 @@
+package swap_test
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/meenmo/molib/calendar"
+	"github.com/meenmo/molib/instruments/swaps"
+	"github.com/meenmo/molib/swap"
+	"github.com/meenmo/molib/swap/curve"
+	"github.com/meenmo/molib/swap/market"
+	"github.com/meenmo/molib/utils"
+)
+
+func TestNetBook_OffsettingSwaps(t *testing.T) {
+	t.Parallel()
+
+	curveDate := time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)
+	estrQuotes := map[string]float64{
+		"1Y":  2.06795,
+		"2Y":  2.153975,
+		"3Y":  2.24,
+		"5Y":  2.3495,
+		"7Y":  2.484,
+		"10Y": 2.6955,
+	}
+
+	build := func(payLeg, recLeg market.LegConvention, payBP, recBP float64, tenor int) *swap.SwapTrade {
+		trade, err := swap.InterestRateSwap(swap.InterestRateSwapParams{
+			DataSource:     swap.DataSourceBGN,
+			ClearingHouse:  swap.ClearingHouseOTC,
+			CurveDate:      curveDate,
+			TradeDate:      curveDate,
+			SwapTenorYears: tenor,
+			Notional:       10_000_000,
+			PayLeg:         payLeg,
+			RecLeg:         recLeg,
+			DiscountingOIS: swaps.ESTRFloating,
+			OISQuotes:      estrQuotes,
+			PayLegQuotes:   estrQuotes,
+			RecLegQuotes:   estrQuotes,
+			PayLegSpreadBP: payBP,
+			RecLegSpreadBP: recBP,
+		})
+		if err != nil {
+			t.Fatalf("InterestRateSwap: %v", err)
+		}
+		return trade
+	}
+
+	floatLeg := swaps.ESTRFloating
+	floatLeg.IncludeInitialPrincipal = false
+	floatLeg.IncludeFinalPrincipal = false
+
+	payer := build(swaps.ESTRFixed, floatLeg, 240, 0, 5)
+	receiver := build(floatLeg, swaps.ESTRFixed, 0, 240, 5)
+	other := build(swaps.ESTRFixed, floatLeg, 260, 0, 10)
+
+	dv01, err := payer.DV01()
+	if err != nil {
+		t.Fatalf("DV01: %v", err)
+	}
+	// A 10mm 5Y payer gains roughly notional * annuity * 1bp when rates rise.
+	if dv01 < 4_000 || dv01 > 5_500 {
+		t.Fatalf("payer DV01 out of range: %.2f", dv01)
+	}
+
+	// The native curve shift agrees with the wrapped one up to the two days between the
+	// curve settlement and the valuation date it is measured from.
+	parallel, err := payer.ParallelDV01(1)
+	if err != nil {
+		t.Fatalf("ParallelDV01: %v", err)
+	}
+	if math.Abs(parallel-dv01) > 0.01*math.Abs(dv01) {
+		t.Fatalf("ParallelDV01 %.2f, DV01 %.2f", parallel, dv01)
+	}
+	if _, err := payer.ParallelDV01(0); err == nil {
+		t.Fatalf("expected error for zero bump")
+	}
+
+	netNPV, netDV01, err := swap.NetBook([]*swap.SwapTrade{payer, receiver})
+	if err != nil {
+		t.Fatalf("NetBook: %v", err)
+	}
+	if math.Abs(netNPV) > 1e-6 || math.Abs(netDV01) > 1e-6 {
+		t.Fatalf("expected offsetting swaps to net to zero: npv=%.8f dv01=%.8f", netNPV, netDV01)
+	}
+
+	pairs, err := swap.OffsettingPairs([]*swap.SwapTrade{payer, other, receiver}, 1e-6, 1e-6)
+	if err != nil {
+		t.Fatalf("OffsettingPairs: %v", err)
+	}
+	if len(pairs) != 1 || pairs[0] != [2]int{0, 2} {
+		t.Fatalf("expected payer/receiver pair, got %v", pairs)
+	}
+
+	if _, _, err := swap.NetBook([]*swap.SwapTrade{payer, nil}); err == nil {
+		t.Fatalf("expected error for nil trade")
+	}
+}
+
+func TestPrincipalDV01_MatchesPrincipalOnlyTrade(t *testing.T) {
+	t.Parallel()
+
+	curveDate := time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)
+	estrQuotes := map[string]float64{"1Y": 2.06795, "2Y": 2.153975, "5Y": 2.3495, "10Y": 2.6955}
+	trade, err := swap.InterestRateSwap(swap.InterestRateSwapParams{
+		DataSource:        swap.DataSourceBGN,
+		ClearingHouse:     swap.ClearingHouseOTC,
+		CurveDate:         curveDate,
+		TradeDate:         curveDate,
+		ForwardTenorYears: 1,
+		SwapTenorYears:    5,
+		Notional:          10_000_000,
+		PayLeg:            swaps.ESTRFixed,
+		RecLeg:            swaps.ESTRFloating, // exchanges notional at start and maturity
+		DiscountingOIS:    swaps.ESTRFloating,
+		OISQuotes:         estrQuotes,
+		RecLegQuotes:      estrQuotes,
+		PayLegSpreadBP:    245,
+	})
+	if err != nil {
+		t.Fatalf("InterestRateSwap: %v", err)
+	}
+
+	got, err := swap.PrincipalDV01(trade.Spec, trade.DiscountCurve, trade.ValuationDate)
+	if err != nil {
+		t.Fatalf("PrincipalDV01: %v", err)
+	}
+
+	// Strip the coupons: zero-coupon fixed legs keep only the receive leg's notional flows.
+	principalOnly := trade.Spec
+	principalOnly.PayLegSpreadBP = 0
+	principalOnly.RecLegSpreadBP = 0
+	principalOnly.RecLeg = swaps.ESTRFixed
+	principalOnly.RecLeg.IncludeInitialPrincipal = true
+	principalOnly.RecLeg.IncludeFinalPrincipal = true
+
+	bumped := zeroShifted{base: trade.DiscountCurve, anchor: trade.ValuationDate, shiftBP: 1}
+	dv01 := func(spec market.SwapSpec) float64 {
+		base, err := swap.NPV(spec, nil, nil, trade.DiscountCurve, trade.ValuationDate)
+		if err != nil {
+			t.Fatalf("NPV: %v", err)
+		}
+		up, err := swap.NPV(spec, nil, nil, bumped, trade.ValuationDate)
+		if err != nil {
+			t.Fatalf("NPV: %v", err)
+		}
+		return up - base
+	}
+	want := dv01(principalOnly)
+	if math.Abs(got-want) > 1e-6 {
+		t.Fatalf("PrincipalDV01=%.6f, principal-only trade DV01=%.6f", got, want)
+	}
+	// Receiving the final notional and paying the initial one: longer flow dominates.
+	if got >= 0 {
+		t.Fatalf("PrincipalDV01=%.6f, want negative for a forward-start notional receiver", got)
+	}
+}
+
+// zeroShifted shifts a discount curve's continuously-compounded zero rates (ACT/365F from anchor).
+type zeroShifted struct {
+	base    swap.DiscountCurve
+	anchor  time.Time
+	shiftBP float64
+}
+
+func (c zeroShifted) DF(t time.Time) float64 {
+	return c.base.DF(t) * math.Exp(-c.shiftBP*1e-4*utils.YearFraction(c.anchor, t, "ACT/365F"))
+}
+
+func (c zeroShifted) ZeroRateAt(t time.Time) float64 {
+	return c.base.ZeroRateAt(t) + c.shiftBP*1e-2
+}
+
+func TestDiscountNodeDeltas_MatchFiniteDifferences(t *testing.T) {
+	t.Parallel()
+
+	curveDate := time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)
+	estrQuotes := map[string]float64{"1Y": 2.06795, "2Y": 2.153975, "5Y": 2.3495, "10Y": 2.6955}
+	trade, err := swap.InterestRateSwap(swap.InterestRateSwapParams{
+		DataSource:     swap.DataSourceBGN,
+		ClearingHouse:  swap.ClearingHouseOTC,
+		CurveDate:      curveDate,
+		TradeDate:      curveDate,
+		SwapTenorYears: 5,
+		Notional:       10_000_000,
+		PayLeg:         swaps.ESTRFixed,
+		RecLeg:         swaps.ESTRFloating,
+		DiscountingOIS: swaps.ESTRFloating,
+		OISQuotes:      estrQuotes,
+		RecLegQuotes:   estrQuotes,
+		PayLegSpreadBP: 245,
+	})
+	if err != nil {
+		t.Fatalf("InterestRateSwap: %v", err)
+	}
+
+	// Discount on the node DFs alone, so bumping one node in a rebuild is a finite-
+	// difference partial. Projection stays on the original curve.
+	disc := trade.DiscountCurve.(*curve.Curve)
+	settlement, nodes := disc.Settlement(), disc.PillarDFs()
+	rebuilt := func(node time.Time, h float64) *curve.Curve {
+		dfs := make(map[time.Time]float64, len(nodes))
+		for d, df := range nodes {
+			dfs[d] = df
+		}
+		dfs[node] += h
+		return curve.NewCurveFromDFs(settlement, dfs, calendar.TARGET, 0)
+	}
+	trade.DiscountCurve = rebuilt(settlement, 0)
+
+	deltas, err := trade.DiscountNodeDeltas()
+	if err != nil {
+		t.Fatalf("DiscountNodeDeltas: %v", err)
+	}
+	if len(deltas) == 0 {
+		t.Fatalf("empty ladder")
+	}
+
+	npv := func(c swap.DiscountCurve) float64 {
+		v, err := swap.NPV(trade.Spec, trade.PayProjCurve, trade.RecProjCurve, c, trade.ValuationDate)
+		if err != nil {
+			t.Fatalf("NPV: %v", err)
+		}
+		return v
+	}
+	const h = 1e-4
+	checked := 0
+	for node := range nodes {
+		tenor := utils.YearFraction(settlement, node, "ACT/365F")
+		got, ok := deltas[tenor]
+		if !ok {
+			continue
+		}
+		fd := (npv(rebuilt(node, h)) - npv(rebuilt(node, -h))) / (2 * h)
+		if math.Abs(got-fd) > 1e-6*math.Max(1, math.Abs(fd)) {
+			t.Errorf("dNPV/dDF(%s) = %.4f, finite difference %.4f", node.Format("2006-01-02"), got, fd)
+		}
+		checked++
+	}
+	if checked != len(deltas) {
+		t.Fatalf("checked %d of %d ladder nodes", checked, len(deltas))
+	}
+}
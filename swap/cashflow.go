@@ -0,0 +1,98 @@
+package swap
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+// Cashflow row types in a CashflowReport.
+const (
+	CashflowCoupon    = "COUPON"
+	CashflowPrincipal = "PRINCIPAL"
+)
+
+// CashflowRow is one cashflow of a leg as priced by PVByLeg. Amounts and PVs are signed
+// from the holder's perspective: negative on the pay leg, positive on the receive leg
+// (principal exchanges follow principalPV).
+type CashflowRow struct {
+	Type string // CashflowCoupon or CashflowPrincipal
+
+	PayDate time.Time
+	// Accrual period of a coupon; zero for principal exchanges.
+	AccrualStart time.Time
+	AccrualEnd   time.Time
+	AccrualDays  int
+	Notional     float64
+
+	// ForwardRate is the floating rate before gearing and spread (projected, fixed or
+	// compounded as the leg prices it); zero on a fixed leg. Rate is the all-in coupon
+	// rate. Both are decimals.
+	ForwardRate float64
+	Rate        float64
+
+	Amount float64 // coupon or principal paid on PayDate
+	DF     float64
+	PV     float64
+}
+
+// CashflowReport lists the cashflows still due at ValuationDate on each leg, in the order
+// PVByLeg discounts them: coupons, then principal exchanges.
+type CashflowReport struct {
+	ValuationDate time.Time
+	PayLeg        []CashflowRow
+	RecLeg        []CashflowRow
+}
+
+// CashflowReport returns the trade's cashflows per leg. The PV column of each leg sums to
+// that leg's PV in PVByLeg.
+func (t *SwapTrade) CashflowReport() (*CashflowReport, error) {
+	report := &CashflowReport{ValuationDate: t.ValuationDate}
+	if _, err := pvByLeg("CashflowReport", t.Spec, t.PayProjCurve, t.RecProjCurve, t.DiscountCurve, t.DiscountCurve, t.ValuationDate, report); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// cashflowCSVHeader is the column layout of WriteCSV. Rates are in percent; leg is PAY or
+// RECEIVE (see StreamPay, StreamReceive).
+var cashflowCSVHeader = []string{
+	"leg", "type", "accrual_start", "accrual_end", "pay_date", "accrual_days",
+	"notional", "forward_pct", "rate_pct", "amount", "df", "pv",
+}
+
+// WriteCSV writes the report as CSV with a header row, pay leg first. Dates are
+// YYYY-MM-DD; columns that do not apply to a row (accrual dates and rates of a principal
+// exchange) are empty.
+func (r *CashflowReport) WriteCSV(w io.Writer) error {
+	const layout = "2006-01-02"
+	num := func(v float64) string { return strconv.FormatFloat(v, 'f', -1, 64) }
+
+	cw := csv.NewWriter(w)
+	if err := cw.Write(cashflowCSVHeader); err != nil {
+		return fmt.Errorf("WriteCSV: %w", err)
+	}
+	for _, leg := range []struct {
+		name string
+		rows []CashflowRow
+	}{{StreamPay, r.PayLeg}, {StreamReceive, r.RecLeg}} {
+		for _, row := range leg.rows {
+			rec := []string{leg.name, row.Type, "", "", row.PayDate.Format(layout), "", "", "", "", num(row.Amount), num(row.DF), num(row.PV)}
+			if row.Type == CashflowCoupon {
+				rec[2], rec[3] = row.AccrualStart.Format(layout), row.AccrualEnd.Format(layout)
+				rec[5], rec[6] = strconv.Itoa(row.AccrualDays), num(row.Notional)
+				rec[7], rec[8] = num(row.ForwardRate*100), num(row.Rate*100)
+			}
+			if err := cw.Write(rec); err != nil {
+				return fmt.Errorf("WriteCSV: %w", err)
+			}
+		}
+	}
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		return fmt.Errorf("WriteCSV: %w", err)
+	}
+	return nil
+}
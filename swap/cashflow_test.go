@@ -0,0 +1,66 @@
+package swap_test
+
+import (
+	"bytes"
+	"encoding/csv"
+	"math"
+	"testing"
+
+	"github.com/meenmo/molib/swap"
+)
+
+func TestSwapTrade_CashflowReport(t *testing.T) {
+	t.Parallel()
+
+	trade := npvForSpreadsTrade(t)
+	trade.Spec.PayLeg.IncludeInitialPrincipal, trade.Spec.PayLeg.IncludeFinalPrincipal = true, true
+	trade.Spec.RecLeg.IncludeInitialPrincipal, trade.Spec.RecLeg.IncludeFinalPrincipal = true, true
+
+	report, err := trade.CashflowReport()
+	if err != nil {
+		t.Fatalf("CashflowReport: %v", err)
+	}
+	pv, err := trade.PVByLeg()
+	if err != nil {
+		t.Fatalf("PVByLeg: %v", err)
+	}
+
+	sum := func(rows []swap.CashflowRow) (total float64, principals int) {
+		for _, r := range rows {
+			total += r.PV
+			if r.Type == swap.CashflowPrincipal {
+				principals++
+			}
+		}
+		return total, principals
+	}
+	payPV, payPrincipals := sum(report.PayLeg)
+	recPV, recPrincipals := sum(report.RecLeg)
+	if math.Abs(payPV-pv.PayLegPV) > 1e-6 || math.Abs(recPV-pv.RecLegPV) > 1e-6 {
+		t.Fatalf("leg PV columns %.6f / %.6f, PVByLeg %.6f / %.6f", payPV, recPV, pv.PayLegPV, pv.RecLegPV)
+	}
+	if math.Abs(payPV+recPV-pv.TotalPV) > 1e-6 {
+		t.Fatalf("PV column sums to %.6f, TotalPV %.6f", payPV+recPV, pv.TotalPV)
+	}
+	if payPrincipals != 2 || recPrincipals != 2 {
+		t.Fatalf("principal rows pay=%d rec=%d, want 2 each", payPrincipals, recPrincipals)
+	}
+	if first := report.PayLeg[0]; first.Type != swap.CashflowCoupon || first.Amount >= 0 || math.Abs(first.Rate-0.026) > 1e-12 {
+		t.Fatalf("first pay row %+v, want a paid 2.6%% fixed coupon", first)
+	}
+
+	var buf bytes.Buffer
+	if err := report.WriteCSV(&buf); err != nil {
+		t.Fatalf("WriteCSV: %v", err)
+	}
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("read CSV: %v", err)
+	}
+	if got, want := len(records), 1+len(report.PayLeg)+len(report.RecLeg); got != want {
+		t.Fatalf("CSV has %d records, want %d", got, want)
+	}
+	if records[0][0] != "leg" || records[1][0] != swap.StreamPay || records[len(records)-1][0] != swap.StreamReceive {
+		t.Fatalf("unexpected CSV layout: header %v, first %v", records[0], records[1])
+	}
+}
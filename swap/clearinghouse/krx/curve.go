@@ -59,7 +59,7 @@ func (crv Curve) buildSwapCurve() map[time.Time]float64 {
 			d1, d2 := adjacentQuotedDates(d, paymentDates, crv.swapQuotes)
 			r1 := crv.swapQuotes[dateToTenor[d1]]
 			r2 := crv.swapQuotes[dateToTenor[d2]]
-			swap[d] = (r1 + (r2-r1)*utils.Days(d1, d)/utils.Days(d1, d2)) / 100
+			swap[d] = utils.Interp1D([]float64{0, utils.Days(d1, d2)}, []float64{r1, r2}, utils.Days(d1, d), false) / 100
 		}
 	}
 	return swap
@@ -108,6 +108,9 @@ func (crv Curve) buildZeroCurve() map[time.Time]float64 {
 	return zc
 }
 
+// ZeroRateAt returns the zero rate (percent, continuous, ACT/365) at pymtDate, linearly
+// interpolated between payment dates. KRX interpolates zeros, so DF is derived from this
+// rate rather than the other way round.
 func (crv Curve) ZeroRateAt(pymtDate time.Time) float64 {
 	if zr, ok := crv.zeroRates[pymtDate]; ok {
 		return zr
@@ -115,7 +118,7 @@ func (crv Curve) ZeroRateAt(pymtDate time.Time) float64 {
 	d1, d2 := utils.AdjacentDates(pymtDate, crv.paymentDates)
 	r1 := crv.zeroRates[d1]
 	r2 := crv.zeroRates[d2]
-	return utils.RoundTo(r1+(r2-r1)*utils.Days(d1, pymtDate)/utils.Days(d1, d2), 12)
+	return utils.RoundTo(utils.Interp1D([]float64{0, utils.Days(d1, d2)}, []float64{r1, r2}, utils.Days(d1, pymtDate), false), 12)
 }
 
 // DF returns the discount factor at pymtDate using the curve's zero-rate interpolation.
@@ -0,0 +1,42 @@
+package krx_test
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	krx "github.com/meenmo/molib/swap/clearinghouse/krx"
+	"github.com/meenmo/molib/utils"
+)
+
+func TestCurve_ZeroRateConsistentWithDF(t *testing.T) {
+	t.Parallel()
+
+	quotes := krx.ParSwapQuotes{
+		0:    2.5524458035,
+		0.25: 2.76,
+		0.5:  2.7225,
+		1:    2.7225,
+		2:    2.8075,
+		3:    2.8882142857,
+		5:    3.0189285714,
+		10:   3.1578571429,
+		20:   3.0946428571,
+	}
+	c := krx.BootstrapCurve("2025-11-21", quotes)
+	settlement := time.Date(2025, 11, 21, 0, 0, 0, 0, time.UTC)
+
+	for _, d := range []time.Time{
+		time.Date(2025, 12, 3, 0, 0, 0, 0, time.UTC),
+		time.Date(2026, 2, 23, 0, 0, 0, 0, time.UTC),
+		time.Date(2028, 7, 14, 0, 0, 0, 0, time.UTC),
+		time.Date(2035, 11, 21, 0, 0, 0, 0, time.UTC),
+		time.Date(2044, 1, 25, 0, 0, 0, 0, time.UTC),
+	} {
+		tau := utils.Days(settlement, d) / 365
+		want := math.Exp(-c.ZeroRateAt(d) / 100 * tau)
+		if got := c.DF(d); math.Abs(got-want) > 1e-12 {
+			t.Errorf("DF(%s)=%.14f but exp(-z*t)=%.14f", d.Format("2006-01-02"), got, want)
+		}
+	}
+}
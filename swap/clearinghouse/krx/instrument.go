@@ -13,6 +13,27 @@ const (
 // ParSwapQuotes maps year-based tenors (e.g., 0, 0.25, 1, 5) to quoted par swap rates.
 type ParSwapQuotes map[float64]float64
 
+// Override returns a copy of q with the quote at tenorYears set to rate (percent), adding
+// the tenor if absent. q is not modified.
+func (q ParSwapQuotes) Override(tenorYears float64, rate float64) ParSwapQuotes {
+	out := make(ParSwapQuotes, len(q)+1)
+	for tenor, r := range q {
+		out[tenor] = r
+	}
+	out[tenorYears] = rate
+	return out
+}
+
+// Bump returns a copy of q with every quote shifted by bumpBP basis points. q is not
+// modified.
+func (q ParSwapQuotes) Bump(bumpBP float64) ParSwapQuotes {
+	out := make(ParSwapQuotes, len(q))
+	for tenor, r := range q {
+		out[tenor] = r + bumpBP/100.0
+	}
+	return out
+}
+
 // InterestRateSwap captures the key economic terms for pricing.
 type InterestRateSwap struct {
 	EffectiveDate   string
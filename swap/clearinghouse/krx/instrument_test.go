@@ -0,0 +1,54 @@
+package krx_test
+
+import (
+	"math"
+	"testing"
+
+	krx "github.com/meenmo/molib/swap/clearinghouse/krx"
+)
+
+func TestParSwapQuotes_OverrideAndBump(t *testing.T) {
+	t.Parallel()
+
+	base := krx.ParSwapQuotes{0: 2.55, 0.25: 2.76, 1: 2.7225, 5: 3.0189, 10: 3.1579}
+	orig := krx.ParSwapQuotes{}
+	for tenor, r := range base {
+		orig[tenor] = r
+	}
+
+	over := base.Override(5, 3.10)
+	if over[5] != 3.10 {
+		t.Fatalf("Override: 5Y = %v, want 3.10", over[5])
+	}
+	for tenor, r := range base {
+		if tenor != 5 && over[tenor] != r {
+			t.Errorf("Override changed %vY: %v -> %v", tenor, r, over[tenor])
+		}
+	}
+	if len(over) != len(base) {
+		t.Errorf("Override has %d tenors, want %d", len(over), len(base))
+	}
+	if added := base.Override(30, 2.95); added[30] != 2.95 || len(added) != len(base)+1 {
+		t.Errorf("Override of a new tenor = %v", added)
+	}
+
+	bumped := base.Bump(10)
+	if len(bumped) != len(base) {
+		t.Fatalf("Bump has %d tenors, want %d", len(bumped), len(base))
+	}
+	for tenor, r := range base {
+		if math.Abs(bumped[tenor]-(r+0.10)) > 1e-12 {
+			t.Errorf("Bump(10) at %vY = %v, want %v", tenor, bumped[tenor], r+0.10)
+		}
+	}
+
+	// Neither helper mutates the receiver.
+	for tenor, r := range orig {
+		if base[tenor] != r {
+			t.Fatalf("receiver mutated at %vY: %v -> %v", tenor, r, base[tenor])
+		}
+	}
+	if len(base) != len(orig) {
+		t.Fatalf("receiver has %d tenors, want %d", len(base), len(orig))
+	}
+}
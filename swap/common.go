@@ -1,12 +1,16 @@
 package swap
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"math"
 	"reflect"
+	"sort"
 	"time"
 
 	"github.com/meenmo/molib/calendar"
+	"github.com/meenmo/molib/swap/curve"
 	"github.com/meenmo/molib/swap/market"
 	"github.com/meenmo/molib/utils"
 )
@@ -56,11 +60,33 @@ func SpotEffectiveMaturityWithSpotLag(tradeDate time.Time, cal calendar.Calendar
 	return spot, effective, maturity
 }
 
+// SpotEffectiveMaturityForwardFromSpot is the spot-plus-tenor alternative to
+// SpotEffectiveMaturityWithSpotLag for forward-starting swaps.
+//
+// Conventions:
+// - spot = tradeDate + spotLagBD business days on cal
+// - effective = spot (+ forwardTenorYears, adjusted following); the spot lag is not re-applied
+// - maturity = effective (+ swapTenorYears, adjusted following)
+//
+// For forwardTenorYears == 0 it returns the same dates as SpotEffectiveMaturityWithSpotLag.
+func SpotEffectiveMaturityForwardFromSpot(tradeDate time.Time, cal calendar.CalendarID, spotLagBD, forwardTenorYears, swapTenorYears int) (spot, effective, maturity time.Time) {
+	spot = calendar.AddBusinessDays(cal, tradeDate, spotLagBD)
+	effective = spot
+	if forwardTenorYears > 0 {
+		effective = calendar.AdjustFollowing(cal, spot.AddDate(forwardTenorYears, 0, 0))
+	}
+	maturity = calendar.AdjustFollowing(cal, effective.AddDate(swapTenorYears, 0, 0))
+	return spot, effective, maturity
+}
+
 // GenerateSchedule builds the payment schedule for a leg.
 //
 // It returns business-day adjusted StartDate/EndDate/PayDate along with integer accrual days.
-// When leg.ScheduleDirection is ScheduleBackward, periods are generated from maturity
-// backward (Bloomberg SWPM convention for IBOR swaps), creating a front stub if needed.
+// When leg.StubConvention is set it alone places the stub (see market.StubConvention).
+// Otherwise, when leg.ScheduleDirection is ScheduleBackward, periods are generated from
+// maturity backward (Bloomberg SWPM convention for IBOR swaps), creating a front stub if
+// needed, merged into the next period when it would be a week or shorter; forward
+// generation leaves any short stub at the back.
 func GenerateSchedule(effective, maturity time.Time, leg market.LegConvention) ([]SchedulePeriod, error) {
 	if maturity.Before(effective) {
 		return nil, fmt.Errorf("GenerateSchedule: maturity %s before effective %s", maturity.Format("2006-01-02"), effective.Format("2006-01-02"))
@@ -68,30 +94,168 @@ func GenerateSchedule(effective, maturity time.Time, leg market.LegConvention) (
 	if leg.PayFrequency <= 0 {
 		return nil, fmt.Errorf("GenerateSchedule: unsupported pay frequency %d", leg.PayFrequency)
 	}
+	if leg.FixingLagDays < 0 {
+		return nil, fmt.Errorf("GenerateSchedule: negative fixing lag %d (lags count business days before the reference date)", leg.FixingLagDays)
+	}
+	if leg.RateCutoffDays < 0 {
+		return nil, fmt.Errorf("GenerateSchedule: negative rate cutoff %d", leg.RateCutoffDays)
+	}
+	if leg.LookbackDays < 0 {
+		return nil, fmt.Errorf("GenerateSchedule: negative lookback %d", leg.LookbackDays)
+	}
+	if !knownAdjustment(leg.BusinessDayAdjustment) {
+		return nil, fmt.Errorf("GenerateSchedule: unsupported business day adjustment %q", leg.BusinessDayAdjustment)
+	}
+	switch leg.StubConvention {
+	case "", market.StubNone, market.StubShortFront, market.StubLongFront, market.StubShortBack, market.StubLongBack:
+	default:
+		return nil, fmt.Errorf("GenerateSchedule: unsupported stub convention %q", leg.StubConvention)
+	}
 
-	// Use backward generation if specified (Bloomberg SWPM convention for IBOR)
-	if leg.ScheduleDirection == market.ScheduleBackward {
-		return generateScheduleBackward(effective, maturity, leg)
+	var (
+		periods []SchedulePeriod
+		err     error
+	)
+	// EndOfMonth snaps period ends to month end only for a month-end effective date.
+	eom := leg.EndOfMonth && calendar.IsEndOfMonth(leg.Calendar, effective)
+	if leg.StubConvention != "" {
+		periods, err = generateScheduleWithStub(effective, maturity, leg, eom)
+	} else if leg.ScheduleDirection == market.ScheduleBackward {
+		// Backward generation (Bloomberg SWPM convention for IBOR)
+		periods, err = generateScheduleBackward(effective, maturity, leg, eom)
+	} else {
+		// Default: forward generation from effective date
+		periods, err = generateScheduleForward(effective, maturity, leg, eom)
+	}
+	if err != nil {
+		return nil, err
+	}
+	for i := 1; i < len(periods); i++ {
+		if periods[i].PayDate.Before(periods[i-1].PayDate) {
+			return nil, fmt.Errorf("GenerateSchedule: pay date %s of period %d before %s of period %d",
+				periods[i].PayDate.Format("2006-01-02"), i, periods[i-1].PayDate.Format("2006-01-02"), i-1)
+		}
+	}
+	return periods, nil
+}
+
+// PayDateCollisions returns the indices of periods paying on the same date as the period
+// before them. Pay dates from GenerateSchedule are non-decreasing, but a short stub inside
+// a holiday cluster can adjust onto its neighbour's dates (e.g. a JPY stub ending over New
+// Year); each period remains a separate cashflow, so callers may want to merge or flag it.
+func PayDateCollisions(periods []SchedulePeriod) []int {
+	var idx []int
+	for i := 1; i < len(periods); i++ {
+		if periods[i].PayDate.Equal(periods[i-1].PayDate) {
+			idx = append(idx, i)
+		}
+	}
+	return idx
+}
+
+// AnniversarySchedule returns count adjusted anniversary dates of start, one every
+// freqMonths months (the first is start + freqMonths). Each date is rolled from start
+// directly rather than from the previous date, so short months do not drift later dates.
+//
+// With BackwardEOM and a start on the last calendar day of its month, every date rolls to
+// month end; otherwise dates follow EDATE. Dates are then adjusted on cal with adj (empty
+// means ModifiedFollowing).
+//
+// This lives in swap rather than calendar because market (which defines the roll and
+// adjustment conventions) already imports calendar.
+func AnniversarySchedule(start time.Time, freqMonths, count int, cal calendar.CalendarID, roll market.RollConvention, adj market.BusinessDayAdjustment) []time.Time {
+	if freqMonths <= 0 {
+		panic(fmt.Sprintf("AnniversarySchedule: freqMonths must be positive, got %d", freqMonths))
+	}
+	if !knownAdjustment(adj) {
+		panic(fmt.Sprintf("AnniversarySchedule: unsupported business day adjustment %q", adj))
+	}
+
+	eom := roll == market.BackwardEOM && start.AddDate(0, 0, 1).Month() != start.Month()
+	dates := make([]time.Time, 0, count)
+	for i := 1; i <= count; i++ {
+		d := calendar.AddMonth(start, i*freqMonths)
+		if eom {
+			d = monthEnd(d)
+		}
+		dates = append(dates, adjustDate(cal, adj, d))
+	}
+	return dates
+}
+
+// ScheduleKey returns a stable key for the schedule GenerateSchedule produces, built from
+// every period's start, end, pay and fixing dates. Legs whose conventions differ but
+// generate the same dates share a key, so it can be used to cache DFs per schedule.
+func ScheduleKey(effective, maturity time.Time, leg market.LegConvention) (string, error) {
+	periods, err := GenerateSchedule(effective, maturity, leg)
+	if err != nil {
+		return "", fmt.Errorf("ScheduleKey: %w", err)
+	}
+	h := sha256.New()
+	for _, p := range periods {
+		fmt.Fprintf(h, "%s|%s|%s|%s;",
+			p.StartDate.Format("2006-01-02"),
+			p.EndDate.Format("2006-01-02"),
+			p.PayDate.Format("2006-01-02"),
+			p.FixingDate.Format("2006-01-02"))
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// knownAdjustment reports whether adj is a supported business day adjustment.
+func knownAdjustment(adj market.BusinessDayAdjustment) bool {
+	switch adj {
+	case "", market.ModifiedFollowing, market.Preceding, market.ModifiedPreceding:
+		return true
+	default:
+		return false
+	}
+}
+
+// adjustDate rolls t onto a business day of cal under adj (empty means ModifiedFollowing).
+func adjustDate(cal calendar.CalendarID, adj market.BusinessDayAdjustment, t time.Time) time.Time {
+	switch adj {
+	case market.Preceding:
+		return calendar.AdjustPreceding(cal, t)
+	case market.ModifiedPreceding:
+		return calendar.AdjustModifiedPreceding(cal, t)
+	default:
+		return calendar.Adjust(cal, t)
 	}
+}
 
-	// Default: forward generation from effective date
-	return generateScheduleForward(effective, maturity, leg)
+// periodFixingDate returns the fixing date of an accrual period on the leg's fixing
+// calendar (falling back to the payment calendar).
+//
+// Lags are counted backward in business days, so a positive FixingLagDays always
+// fixes before the reference date:
+//   - in advance: reference date is the accrual start (e.g. EURIBOR, lag 2 = T-2).
+//   - in arrears: reference date is the accrual end, moved back by RateCutoffDays,
+//     LookbackDays and FixingLagDays (the last overnight fixing observed for the period).
+func periodFixingDate(leg market.LegConvention, accrualStart, accrualEnd time.Time) time.Time {
+	fixCal := leg.FixingCalendar
+	if fixCal == "" {
+		fixCal = leg.Calendar
+	}
+	if leg.ResetPosition == market.ResetInArrears {
+		return calendar.AddBusinessDays(fixCal, accrualEnd, -(leg.RateCutoffDays + leg.LookbackDays + leg.FixingLagDays))
+	}
+	return calendar.AddBusinessDays(fixCal, accrualStart, -leg.FixingLagDays)
 }
 
 // generateScheduleForward generates periods rolling forward from effective date.
-func generateScheduleForward(effective, maturity time.Time, leg market.LegConvention) ([]SchedulePeriod, error) {
+// With eom, every unadjusted period end before maturity snaps to the last day of its month.
+func generateScheduleForward(effective, maturity time.Time, leg market.LegConvention, eom bool) ([]SchedulePeriod, error) {
 	periods := make([]SchedulePeriod, 0, 64)
 	months := int(leg.PayFrequency)
 	start := effective
 	var prevAdjustedEnd time.Time // Track the previous period's adjusted end for chaining
 
-	for start.Before(maturity) {
+	for i := 1; start.Before(maturity); i++ {
 		// Generate unadjusted period end, then cap to maturity to create a final stub if needed.
-		var endUnadj time.Time
-		if leg.RollConvention == market.BackwardEOM {
-			endUnadj = utils.AddMonth(start, months)
-		} else {
-			endUnadj = start.AddDate(0, months, 0)
+		endUnadj := rollDate(effective, i*months, leg.RollConvention)
+		if eom {
+			endUnadj = monthEnd(endUnadj)
 		}
 		if endUnadj.After(maturity) {
 			endUnadj = maturity
@@ -105,20 +269,12 @@ func generateScheduleForward(effective, maturity time.Time, leg market.LegConven
 		if isOIS && !prevAdjustedEnd.IsZero() {
 			accrualStart = prevAdjustedEnd
 		} else {
-			accrualStart = calendar.Adjust(leg.Calendar, start)
+			accrualStart = adjustDate(leg.Calendar, leg.BusinessDayAdjustment, start)
 		}
-		accrualEnd := calendar.Adjust(leg.Calendar, endUnadj)
+		accrualEnd := adjustDate(leg.Calendar, leg.BusinessDayAdjustment, endUnadj)
 		paymentDate := calendar.AddBusinessDays(leg.Calendar, accrualEnd, leg.PayDelayDays)
 
-		fixCal := leg.FixingCalendar
-		if fixCal == "" {
-			fixCal = leg.Calendar
-		}
-
-		fixingDate := calendar.AddBusinessDays(fixCal, accrualStart, -leg.FixingLagDays)
-		if leg.ResetPosition == market.ResetInArrears {
-			fixingDate = calendar.AddBusinessDays(fixCal, accrualEnd, -(leg.RateCutoffDays + leg.FixingLagDays))
-		}
+		fixingDate := periodFixingDate(leg, accrualStart, accrualEnd)
 
 		periods = append(periods, SchedulePeriod{
 			StartDate:   accrualStart,
@@ -140,22 +296,38 @@ func generateScheduleForward(effective, maturity time.Time, leg market.LegConven
 	return periods, nil
 }
 
+// rollDate returns anchor moved by months under roll: clamped to month end (EDATE) for
+// BackwardEOM, Go date normalisation otherwise (Feb 29 + 1Y = Mar 1). Schedules roll every
+// date from one anchor rather than from the previous date, so a date clamped in a short
+// month (Feb 29 -> Feb 28 in a non-leap year) does not pull later dates off the anchor day.
+func rollDate(anchor time.Time, months int, roll market.RollConvention) time.Time {
+	if roll == market.BackwardEOM {
+		return utils.AddMonth(anchor, months)
+	}
+	return anchor.AddDate(0, months, 0)
+}
+
+// monthEnd returns the last calendar day of t's month.
+func monthEnd(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month()+1, 0, 0, 0, 0, 0, t.Location())
+}
+
 // generateScheduleBackward generates periods rolling backward from maturity date.
 // This matches Bloomberg SWPM convention for IBOR swaps, where intermediate dates
 // align with maturity and the first period becomes a front stub if needed.
-func generateScheduleBackward(effective, maturity time.Time, leg market.LegConvention) ([]SchedulePeriod, error) {
+// With eom, every rolled date before maturity snaps to the last day of its month.
+func generateScheduleBackward(effective, maturity time.Time, leg market.LegConvention, eom bool) ([]SchedulePeriod, error) {
 	months := int(leg.PayFrequency)
 
 	// Generate unadjusted dates backward from maturity
 	// Stop when we reach or pass effective date
 	var unadjustedDates []time.Time
 	current := maturity
-	for current.After(effective) {
+	for i := 1; current.After(effective); i++ {
 		unadjustedDates = append([]time.Time{current}, unadjustedDates...)
-		if leg.RollConvention == market.BackwardEOM {
-			current = utils.AddMonth(current, -months)
-		} else {
-			current = current.AddDate(0, -months, 0)
+		current = rollDate(maturity, -i*months, leg.RollConvention)
+		if eom {
+			current = monthEnd(current)
 		}
 	}
 
@@ -173,27 +345,72 @@ func generateScheduleBackward(effective, maturity time.Time, leg market.LegConve
 
 	// Prepend effective date as the start of the first (potentially stub) period
 	unadjustedDates = append([]time.Time{effective}, unadjustedDates...)
+	return periodsFromDates(unadjustedDates, leg), nil
+}
 
-	// Build periods from consecutive date pairs
-	periods := make([]SchedulePeriod, 0, len(unadjustedDates)-1)
-	for i := 0; i < len(unadjustedDates)-1; i++ {
-		startUnadj := unadjustedDates[i]
-		endUnadj := unadjustedDates[i+1]
+// generateScheduleWithStub generates periods whose stub is placed by leg.StubConvention.
+// With eom, every rolled date snaps to the last day of its month.
+func generateScheduleWithStub(effective, maturity time.Time, leg market.LegConvention, eom bool) ([]SchedulePeriod, error) {
+	months := int(leg.PayFrequency)
+	roll := func(anchor time.Time, i int) time.Time {
+		d := rollDate(anchor, i*months, leg.RollConvention)
+		if eom {
+			d = monthEnd(d)
+		}
+		return d
+	}
 
-		accrualStart := calendar.Adjust(leg.Calendar, startUnadj)
-		accrualEnd := calendar.Adjust(leg.Calendar, endUnadj)
+	var dates []time.Time
+	stub := false
+	switch leg.StubConvention {
+	case market.StubShortFront, market.StubLongFront:
+		// Regular dates roll back from maturity; whatever is left at the front is the stub.
+		dates = []time.Time{maturity}
+		for i := 1; ; i++ {
+			d := roll(maturity, -i)
+			if !d.After(effective) {
+				stub = d.Before(effective)
+				break
+			}
+			dates = append([]time.Time{d}, dates...)
+		}
+		if stub && leg.StubConvention == market.StubLongFront && len(dates) > 1 {
+			dates = dates[1:]
+		}
+		dates = append([]time.Time{effective}, dates...)
+	default:
+		// Regular dates roll forward from effective; whatever is left at the back is the stub.
+		dates = []time.Time{effective}
+		for i := 1; ; i++ {
+			d := roll(effective, i)
+			if !d.Before(maturity) {
+				stub = d.After(maturity)
+				break
+			}
+			dates = append(dates, d)
+		}
+		if stub && leg.StubConvention == market.StubLongBack && len(dates) > 1 {
+			dates = dates[:len(dates)-1]
+		}
+		dates = append(dates, maturity)
+	}
+	if stub && leg.StubConvention == market.StubNone {
+		return nil, fmt.Errorf("GenerateSchedule: %s to %s is not a whole number of %d-month periods (stub convention %s)",
+			effective.Format("2006-01-02"), maturity.Format("2006-01-02"), months, market.StubNone)
+	}
+	return periodsFromDates(dates, leg), nil
+}
 
-		paymentDate := calendar.AddBusinessDays(leg.Calendar, accrualEnd, leg.PayDelayDays)
+// periodsFromDates builds one period per consecutive pair of unadjusted dates.
+func periodsFromDates(dates []time.Time, leg market.LegConvention) []SchedulePeriod {
+	periods := make([]SchedulePeriod, 0, len(dates)-1)
+	for i := 0; i < len(dates)-1; i++ {
+		accrualStart := adjustDate(leg.Calendar, leg.BusinessDayAdjustment, dates[i])
+		accrualEnd := adjustDate(leg.Calendar, leg.BusinessDayAdjustment, dates[i+1])
 
-		fixCal := leg.FixingCalendar
-		if fixCal == "" {
-			fixCal = leg.Calendar
-		}
+		paymentDate := calendar.AddBusinessDays(leg.Calendar, accrualEnd, leg.PayDelayDays)
 
-		fixingDate := calendar.AddBusinessDays(fixCal, accrualStart, -leg.FixingLagDays)
-		if leg.ResetPosition == market.ResetInArrears {
-			fixingDate = calendar.AddBusinessDays(fixCal, accrualEnd, -(leg.RateCutoffDays + leg.FixingLagDays))
-		}
+		fixingDate := periodFixingDate(leg, accrualStart, accrualEnd)
 
 		periods = append(periods, SchedulePeriod{
 			StartDate:   accrualStart,
@@ -203,8 +420,20 @@ func generateScheduleBackward(effective, maturity time.Time, leg market.LegConve
 			FixingDate:  fixingDate,
 		})
 	}
+	return periods
+}
 
-	return periods, nil
+// horizonChecker is implemented by curves with a maximum horizon (e.g. *curve.Curve).
+type horizonChecker interface {
+	CheckHorizon(t time.Time) error
+}
+
+// checkHorizon reports an error if c limits its horizon and t lies beyond it.
+func checkHorizon(c any, t time.Time) error {
+	if hc, ok := c.(horizonChecker); ok {
+		return hc.CheckHorizon(t)
+	}
+	return nil
 }
 
 // GetDiscountFactors returns discount factors for the given dates using the curve's interpolation rules.
@@ -214,6 +443,9 @@ func GetDiscountFactors(curve DiscountCurve, dates []time.Time) ([]float64, erro
 	}
 	dfs := make([]float64, len(dates))
 	for i, d := range dates {
+		if err := checkHorizon(curve, d); err != nil {
+			return nil, fmt.Errorf("GetDiscountFactors: %w", err)
+		}
 		dfs[i] = curve.DF(d)
 	}
 	return dfs, nil
@@ -226,6 +458,9 @@ func GetZeroRates(curve DiscountCurve, dates []time.Time) ([]float64, error) {
 	}
 	zeros := make([]float64, len(dates))
 	for i, d := range dates {
+		if err := checkHorizon(curve, d); err != nil {
+			return nil, fmt.Errorf("GetZeroRates: %w", err)
+		}
 		zeros[i] = curve.ZeroRateAt(d)
 	}
 	return zeros, nil
@@ -241,6 +476,101 @@ func forwardRate(projCurve ProjectionCurve, start, end time.Time, dayCount strin
 	return (dfStart/dfEnd - 1.0) / alpha
 }
 
+// periodForward returns the simple forward (decimal) projected for an in-advance period:
+// over its accrual dates, or with leg.ForwardFromFixingDate from its fixing date to the
+// fixing date of its accrual end.
+func periodForward(projCurve ProjectionCurve, leg market.LegConvention, p SchedulePeriod) float64 {
+	if leg.ForwardFromFixingDate && leg.ResetPosition != market.ResetInArrears {
+		// An in-advance period starting at EndDate fixes FixingLagDays before it.
+		nextFixing := periodFixingDate(leg, p.EndDate, p.EndDate)
+		return forwardRate(projCurve, p.FixingDate, nextFixing, string(leg.DayCount))
+	}
+	return forwardRate(projCurve, p.StartDate, p.EndDate, string(leg.DayCount))
+}
+
+// legGearing returns the multiplier applied to a leg's floating rate: leg.Gearing, or 1
+// when unset.
+func legGearing(leg market.LegConvention) float64 {
+	if leg.Gearing == 0 {
+		return 1
+	}
+	return leg.Gearing
+}
+
+// couponRate returns a period's all-in rate from its geared index rate and spread, clamped
+// to the leg's CouponFloor and CouponCap on floating legs.
+func couponRate(leg market.LegConvention, index, spread float64) float64 {
+	if leg.LegType != market.LegFloating || (leg.CouponFloor == nil && leg.CouponCap == nil) {
+		return index + spread
+	}
+	clamp := func(r float64) float64 {
+		if leg.CouponFloor != nil {
+			r = math.Max(r, *leg.CouponFloor)
+		}
+		if leg.CouponCap != nil {
+			r = math.Min(r, *leg.CouponCap)
+		}
+		return r
+	}
+	if leg.ClampBeforeSpread {
+		return clamp(index) + spread
+	}
+	return clamp(index + spread)
+}
+
+// inArrearsConvexityAdjustment returns the timing adjustment added to the forward fwd of an
+// in-arrears term-index period with accrual δ: under a lognormal forward with volatility
+// leg.ConvexityVol, a rate fixed at the period end and paid then is worth
+// F + F²σ²tδ/(1+Fδ), t the ACT/365F years from valuationDate to the fixing. It is zero for
+// overnight or in-advance legs, a zero vol, or a fixing not after valuationDate.
+func inArrearsConvexityAdjustment(leg market.LegConvention, p SchedulePeriod, fwd, accrual float64, valuationDate time.Time) float64 {
+	if leg.ConvexityVol == 0 || leg.ResetPosition != market.ResetInArrears || market.IsOvernight(leg.ReferenceIndex) {
+		return 0
+	}
+	t := utils.YearFraction(valuationDate, p.FixingDate, "ACT/365F")
+	if t <= 0 {
+		return 0
+	}
+	vol := leg.ConvexityVol
+	return fwd * fwd * vol * vol * t * accrual / (1 + fwd*accrual)
+}
+
+// compoundsResets reports whether an IBOR leg resets more often than it pays,
+// in which case each coupon compounds the sub-period fixings.
+func compoundsResets(leg market.LegConvention) bool {
+	return !market.IsOvernight(leg.ReferenceIndex) &&
+		leg.ResetFrequency > 0 &&
+		leg.ResetFrequency < leg.PayFrequency
+}
+
+// compoundedIBORRate returns the simple rate for a payment period that compounds
+// IBOR fixings reset every leg.ResetFrequency months (spread excluded).
+//
+// firstFixingPct, when non-nil, replaces the forward for the first sub-period (in percent).
+func compoundedIBORRate(projCurve ProjectionCurve, p SchedulePeriod, leg market.LegConvention, firstFixingPct *float64) float64 {
+	dayCount := string(leg.DayCount)
+	total := utils.YearFraction(p.StartDate, p.EndDate, dayCount)
+	if total == 0 {
+		return 0
+	}
+
+	growth := 1.0
+	start := p.StartDate
+	for k := 1; start.Before(p.EndDate); k++ {
+		end := adjustDate(leg.Calendar, leg.BusinessDayAdjustment, utils.AddMonth(p.StartDate, k*int(leg.ResetFrequency)))
+		if !end.Before(p.EndDate) {
+			end = p.EndDate
+		}
+		rate := forwardRate(projCurve, start, end, dayCount)
+		if k == 1 && firstFixingPct != nil {
+			rate = *firstFixingPct / 100.0
+		}
+		growth *= 1.0 + rate*utils.YearFraction(start, end, dayCount)
+		start = end
+	}
+	return (growth - 1.0) / total
+}
+
 // GetForwardRates returns simple forward rates for each schedule period of a floating leg.
 //
 // Rate is returned as a decimal (e.g., 0.025 == 2.5%).
@@ -270,6 +600,17 @@ func GetForwardRates(projCurve ProjectionCurve, effective, maturity time.Time, l
 	return out, nil
 }
 
+// couponDecimals returns the minor-unit precision of the currency paid on cal:
+// zero for JPY and KRW, two (cents) otherwise.
+func couponDecimals(cal calendar.CalendarID) uint32 {
+	switch cal {
+	case calendar.JP, calendar.KR:
+		return 0
+	default:
+		return 2
+	}
+}
+
 func validateSwapSpec(spec market.SwapSpec) error {
 	if spec.MaturityDate.Before(spec.EffectiveDate) {
 		return fmt.Errorf("maturity %s before effective %s", spec.MaturityDate.Format("2006-01-02"), spec.EffectiveDate.Format("2006-01-02"))
@@ -288,6 +629,21 @@ func legPV(
 	valuationDate time.Time,
 	spreadBP float64,
 	isPayLeg bool,
+) (float64, error) {
+	return legCashflowsPV(spec, leg, projCurve, discCurve, valuationDate, spreadBP, isPayLeg, nil)
+}
+
+// legCashflowsPV is legPV, also appending each cashflow it discounts to rows when rows
+// is non-nil.
+func legCashflowsPV(
+	spec market.SwapSpec,
+	leg market.LegConvention,
+	projCurve ProjectionCurve,
+	discCurve DiscountCurve,
+	valuationDate time.Time,
+	spreadBP float64,
+	isPayLeg bool,
+	rows *[]CashflowRow,
 ) (float64, error) {
 	if isNilInterface(discCurve) {
 		return 0, ErrNilCurve
@@ -300,6 +656,24 @@ func legPV(
 	if err != nil {
 		return 0, err
 	}
+	if n := len(periods); n > 0 {
+		last := periods[n-1]
+		if err := checkHorizon(discCurve, last.PayDate); err != nil {
+			return 0, err
+		}
+		if leg.LegType == market.LegFloating {
+			if err := checkHorizon(projCurve, last.EndDate); err != nil {
+				return 0, err
+			}
+		}
+	}
+
+	if leg.ConvexityVol < 0 {
+		return 0, fmt.Errorf("legPV: negative convexity vol %g", leg.ConvexityVol)
+	}
+	if leg.CouponFloor != nil && leg.CouponCap != nil && *leg.CouponFloor > *leg.CouponCap {
+		return 0, fmt.Errorf("legPV: coupon floor %g above cap %g", *leg.CouponFloor, *leg.CouponCap)
+	}
 
 	spread := spreadBP * 1e-4
 
@@ -316,7 +690,8 @@ func legPV(
 	}
 
 	totalPV := 0.0
-	for _, p := range periods {
+	firstUnpaid := true // with FirstResetOnCurrentPeriod, overrides apply to the first period not yet paid
+	for i, p := range periods {
 		if p.PayDate.Before(valuationDate) {
 			continue
 		}
@@ -325,74 +700,254 @@ func legPV(
 
 		base := 0.0
 		if leg.LegType == market.LegFloating {
-			if firstResetOverride != nil && p.StartDate.Equal(spec.EffectiveDate) {
-				base = *firstResetOverride / 100.0
-			} else {
-				base = forwardRate(projCurve, p.StartDate, p.EndDate, string(leg.DayCount))
+			var fixingPct *float64
+			if spec.FirstResetOnCurrentPeriod && firstUnpaid ||
+				!spec.FirstResetOnCurrentPeriod && p.StartDate.Equal(spec.EffectiveDate) {
+				fixingPct = firstResetOverride
 			}
+			switch {
+			case i == 0 && spec.FirstFloatingFixing != nil:
+				base = *spec.FirstFloatingFixing
+			case compoundsResets(leg):
+				base = compoundedIBORRate(projCurve, p, leg, fixingPct)
+			case fixingPct != nil:
+				base = *fixingPct / 100.0
+			case spec.Fixings != nil && market.IsOvernight(leg.ReferenceIndex) &&
+				overnightObservationStart(leg, p.StartDate).Before(valuationDate):
+				base, err = blendedOvernightRate(leg, p, projCurve, valuationDate, spec.Fixings)
+				if err != nil {
+					return 0, err
+				}
+			case spec.Fixings != nil && !market.IsOvernight(leg.ReferenceIndex) &&
+				!p.FixingDate.After(valuationDate):
+				base, err = realizedFixingRate(leg, p, projCurve, valuationDate, spec.Fixings)
+				if err != nil {
+					return 0, err
+				}
+			case market.IsOvernight(leg.ReferenceIndex) && leg.ResetPosition == market.ResetInArrears:
+				base, err = ProjectedOvernightRate(projCurve, leg, p.StartDate, p.EndDate)
+				if err != nil {
+					return 0, err
+				}
+			default:
+				base = periodForward(projCurve, leg, p)
+				base += inArrearsConvexityAdjustment(leg, p, base, accrual, valuationDate)
+			}
+		}
+		periodSpread := spread
+		if bp, ok := scheduledSpreadBP(leg, p.StartDate); ok {
+			periodSpread = bp * 1e-4
 		}
-		rate := base + spread
+		rate := couponRate(leg, legGearing(leg)*base, periodSpread)
+		firstUnpaid = false
 
-		payment := spec.Notional * accrual * rate
+		payment := notionalAt(spec, p.StartDate) * accrual * rate
+		if leg.RoundCoupons {
+			payment = utils.RoundTo(payment, couponDecimals(leg.Calendar))
+		}
 		df := discCurve.DF(p.PayDate)
 		totalPV += signCoupon * payment * df
+		if rows != nil {
+			*rows = append(*rows, CashflowRow{
+				Type:         CashflowCoupon,
+				PayDate:      p.PayDate,
+				AccrualStart: p.StartDate,
+				AccrualEnd:   p.EndDate,
+				AccrualDays:  p.AccrualDays,
+				Notional:     notionalAt(spec, p.StartDate),
+				ForwardRate:  base,
+				Rate:         rate,
+				Amount:       signCoupon * payment,
+				DF:           df,
+				PV:           signCoupon * payment * df,
+			})
+		}
 	}
 
+	totalPV += principalCashflowsPV(spec, leg, discCurve, valuationDate, isPayLeg, rows)
+	return totalPV, nil
+}
+
+// principalPV returns the PV of a leg's notional exchanges still due at valuationDate:
+// the leg's holder pays the notional at the effective date and receives it at maturity
+// (signs reversed for the pay leg; see finalPrincipalDate). Under a NotionalSchedule, the
+// holder also receives each step-down (pays each step-up) on the step date, and receives
+// the remaining notional at maturity; those exchanges follow IncludeFinalPrincipal.
+func principalPV(spec market.SwapSpec, leg market.LegConvention, discCurve DiscountCurve, valuationDate time.Time, isPayLeg bool) float64 {
+	return principalCashflowsPV(spec, leg, discCurve, valuationDate, isPayLeg, nil)
+}
+
+// principalCashflowsPV is principalPV, also appending each exchange to rows when rows is
+// non-nil.
+func principalCashflowsPV(spec market.SwapSpec, leg market.LegConvention, discCurve DiscountCurve, valuationDate time.Time, isPayLeg bool, rows *[]CashflowRow) float64 {
+	pv := 0.0
+	exchange := func(date time.Time, amount float64) {
+		df := discCurve.DF(date)
+		pv += amount * df
+		if rows != nil {
+			*rows = append(*rows, CashflowRow{Type: CashflowPrincipal, PayDate: date, Amount: amount, DF: df, PV: amount * df})
+		}
+	}
+	initial := notionalAt(spec, spec.EffectiveDate)
 	if leg.IncludeInitialPrincipal && !spec.EffectiveDate.Before(valuationDate) {
 		sign := -1.0
 		if isPayLeg {
 			sign = 1.0
 		}
-		totalPV += sign * spec.Notional * discCurve.DF(spec.EffectiveDate)
+		exchange(spec.EffectiveDate, sign*initial)
 	}
-	if leg.IncludeFinalPrincipal && !spec.MaturityDate.Before(valuationDate) {
+	if leg.IncludeFinalPrincipal {
 		sign := 1.0
 		if isPayLeg {
 			sign = -1.0
 		}
-		totalPV += sign * spec.Notional * discCurve.DF(spec.MaturityDate)
+		outstanding := initial
+		steps := append([]market.NotionalStep(nil), spec.NotionalSchedule...)
+		sort.SliceStable(steps, func(i, j int) bool { return steps[i].EffectiveDate.Before(steps[j].EffectiveDate) })
+		for _, st := range steps {
+			if !st.EffectiveDate.After(spec.EffectiveDate) || !st.EffectiveDate.Before(spec.MaturityDate) {
+				continue
+			}
+			if !st.EffectiveDate.Before(valuationDate) {
+				exchange(st.EffectiveDate, sign*(outstanding-st.Notional))
+			}
+			outstanding = st.Notional
+		}
+		if final := finalPrincipalDate(spec, leg); !final.Before(valuationDate) {
+			exchange(final, sign*outstanding)
+		}
 	}
+	return pv
+}
 
-	return totalPV, nil
+// finalPrincipalDate returns the date a leg's final principal is exchanged: the maturity
+// date, or with DelayFinalPrincipal the last coupon's pay date (PayDelayDays business
+// days after the adjusted maturity, as in GenerateSchedule).
+func finalPrincipalDate(spec market.SwapSpec, leg market.LegConvention) time.Time {
+	if !leg.DelayFinalPrincipal || leg.PayDelayDays <= 0 {
+		return spec.MaturityDate
+	}
+	return calendar.AddBusinessDays(leg.Calendar, adjustDate(leg.Calendar, leg.BusinessDayAdjustment, spec.MaturityDate), leg.PayDelayDays)
 }
 
-// NPV calculates the net present value of a swap by summing discounted cashflows across both legs.
-func NPV(spec market.SwapSpec, projPay ProjectionCurve, projRec ProjectionCurve, discCurve DiscountCurve, valuationDate time.Time) (float64, error) {
-	if err := validateSwapSpec(spec); err != nil {
-		return 0, fmt.Errorf("NPV: %w", err)
+// notionalAt returns the notional in force at t: the NotionalSchedule step with the
+// latest date on or before t, or spec.Notional if there is none.
+func notionalAt(spec market.SwapSpec, t time.Time) float64 {
+	notional := spec.Notional
+	var at time.Time
+	found := false
+	for _, st := range spec.NotionalSchedule {
+		if st.EffectiveDate.After(t) {
+			continue
+		}
+		if !found || st.EffectiveDate.After(at) {
+			at, notional, found = st.EffectiveDate, st.Notional, true
+		}
 	}
-	if isNilInterface(discCurve) {
-		return 0, ErrNilCurve
+	return notional
+}
+
+// realizedFixingRate returns the published fixing (decimal) of a term-rate period that
+// fixed on or before valuationDate. A fixing missing for an earlier date is an error; on
+// the valuation date itself the fixing may not be published yet, so the forward is used.
+func realizedFixingRate(leg market.LegConvention, p SchedulePeriod, projCurve ProjectionCurve, valuationDate time.Time, fixings market.FixingRepo) (float64, error) {
+	if pct, ok := fixings.Fixing(leg.ReferenceIndex, p.FixingDate); ok {
+		return pct / 100.0, nil
+	}
+	if p.FixingDate.Before(valuationDate) {
+		return 0, fmt.Errorf("missing %s fixing on %s", leg.ReferenceIndex, p.FixingDate.Format("2006-01-02"))
 	}
+	return periodForward(projCurve, leg, p), nil
+}
 
-	pvPay, err := legPV(spec, spec.PayLeg, projPay, discCurve, valuationDate, spec.PayLegSpreadBP, true)
-	if err != nil {
-		return 0, fmt.Errorf("NPV: pay leg: %w", err)
+// scheduledSpreadBP returns the leg's SpreadSchedule entry in force for a period starting
+// at start: the one with the latest date on or before start.
+func scheduledSpreadBP(leg market.LegConvention, start time.Time) (float64, bool) {
+	var (
+		at    time.Time
+		bp    float64
+		found bool
+	)
+	for d, v := range leg.SpreadSchedule {
+		if d.After(start) || (found && !d.After(at)) {
+			continue
+		}
+		at, bp, found = d, v, true
 	}
-	pvRec, err := legPV(spec, spec.RecLeg, projRec, discCurve, valuationDate, spec.RecLegSpreadBP, false)
-	if err != nil {
-		return 0, fmt.Errorf("NPV: receive leg: %w", err)
+	return bp, found
+}
+
+// matured reports whether valuationDate is after the maturity date and after the last
+// payment date of both legs (payment delays can push the final coupon past maturity).
+func matured(spec market.SwapSpec, valuationDate time.Time) (bool, error) {
+	if !valuationDate.After(spec.MaturityDate) {
+		return false, nil
+	}
+	for _, leg := range []market.LegConvention{spec.PayLeg, spec.RecLeg} {
+		periods, err := GenerateSchedule(spec.EffectiveDate, spec.MaturityDate, leg)
+		if err != nil {
+			return false, err
+		}
+		if n := len(periods); n > 0 && !periods[n-1].PayDate.Before(valuationDate) {
+			return false, nil
+		}
 	}
+	return true, nil
+}
 
-	return pvPay + pvRec, nil
+// NPV calculates the net present value of a swap by summing discounted cashflows across both legs.
+// It returns an error wrapping ErrMatured once every cashflow is paid as of valuationDate.
+func NPV(spec market.SwapSpec, projPay ProjectionCurve, projRec ProjectionCurve, discCurve DiscountCurve, valuationDate time.Time) (float64, error) {
+	pv, err := pvByLeg("NPV", spec, projPay, projRec, discCurve, discCurve, valuationDate, nil)
+	return pv.TotalPV, err
 }
 
 // PVByLeg calculates discounted PVs for each leg and returns the net sum.
+// Like NPV, it returns an error wrapping ErrMatured for a fully paid trade.
 func PVByLeg(spec market.SwapSpec, projPay ProjectionCurve, projRec ProjectionCurve, discCurve DiscountCurve, valuationDate time.Time) (PV, error) {
+	return pvByLeg("PVByLeg", spec, projPay, projRec, discCurve, discCurve, valuationDate, nil)
+}
+
+// NPVWithLegDiscounting is NPV with each leg discounted on its own curve, as under a CSA
+// whose collateral terms differ between the legs. NPV is the discPay == discRec case.
+func NPVWithLegDiscounting(spec market.SwapSpec, projPay ProjectionCurve, projRec ProjectionCurve, discPay, discRec DiscountCurve, valuationDate time.Time) (float64, error) {
+	pv, err := pvByLeg("NPVWithLegDiscounting", spec, projPay, projRec, discPay, discRec, valuationDate, nil)
+	return pv.TotalPV, err
+}
+
+// PVByLegWithLegDiscounting is PVByLeg with each leg discounted on its own curve.
+func PVByLegWithLegDiscounting(spec market.SwapSpec, projPay ProjectionCurve, projRec ProjectionCurve, discPay, discRec DiscountCurve, valuationDate time.Time) (PV, error) {
+	return pvByLeg("PVByLegWithLegDiscounting", spec, projPay, projRec, discPay, discRec, valuationDate, nil)
+}
+
+// pvByLeg prices both legs, each on its own discount curve. name prefixes returned errors.
+// A non-nil report collects the cashflows of each leg.
+func pvByLeg(name string, spec market.SwapSpec, projPay ProjectionCurve, projRec ProjectionCurve, discPay, discRec DiscountCurve, valuationDate time.Time, report *CashflowReport) (PV, error) {
 	if err := validateSwapSpec(spec); err != nil {
-		return PV{}, fmt.Errorf("PVByLeg: %w", err)
+		return PV{}, fmt.Errorf("%s: %w", name, err)
 	}
-	if isNilInterface(discCurve) {
+	if isNilInterface(discPay) || isNilInterface(discRec) {
 		return PV{}, ErrNilCurve
 	}
 
-	pvPay, err := legPV(spec, spec.PayLeg, projPay, discCurve, valuationDate, spec.PayLegSpreadBP, true)
+	if done, err := matured(spec, valuationDate); err != nil {
+		return PV{}, fmt.Errorf("%s: %w", name, err)
+	} else if done {
+		return PV{}, fmt.Errorf("%s: valuation %s after maturity %s: %w", name,
+			valuationDate.Format("2006-01-02"), spec.MaturityDate.Format("2006-01-02"), ErrMatured)
+	}
+
+	var payRows, recRows *[]CashflowRow
+	if report != nil {
+		payRows, recRows = &report.PayLeg, &report.RecLeg
+	}
+	pvPay, err := legCashflowsPV(spec, spec.PayLeg, projPay, discPay, valuationDate, spec.PayLegSpreadBP, true, payRows)
 	if err != nil {
-		return PV{}, fmt.Errorf("PVByLeg: pay leg: %w", err)
+		return PV{}, fmt.Errorf("%s: pay leg: %w", name, err)
 	}
-	pvRec, err := legPV(spec, spec.RecLeg, projRec, discCurve, valuationDate, spec.RecLegSpreadBP, false)
+	pvRec, err := legCashflowsPV(spec, spec.RecLeg, projRec, discRec, valuationDate, spec.RecLegSpreadBP, false, recRows)
 	if err != nil {
-		return PV{}, fmt.Errorf("PVByLeg: receive leg: %w", err)
+		return PV{}, fmt.Errorf("%s: receive leg: %w", name, err)
 	}
 	return PV{
 		PayLegPV: pvPay,
@@ -401,6 +956,10 @@ func PVByLeg(spec market.SwapSpec, projPay ProjectionCurve, projRec ProjectionCu
 	}, nil
 }
 
+// pv01TargetLegPerDec returns the NPV change per unit (decimal) of the target leg's spread.
+// The spread is added after the leg's Gearing is applied to the index, so gearing does not
+// enter it. Periods on the leg's SpreadSchedule ignore the flat spread and are left out;
+// it is an error when no period remains to carry it.
 func pv01TargetLegPerDec(spec market.SwapSpec, discCurve DiscountCurve, valuationDate time.Time, target SpreadTarget) (float64, error) {
 	if isNilInterface(discCurve) {
 		return 0, ErrNilCurve
@@ -431,8 +990,14 @@ func pv01TargetLegPerDec(spec market.SwapSpec, discCurve DiscountCurve, valuatio
 		if p.PayDate.Before(valuationDate) {
 			continue
 		}
+		if _, ok := scheduledSpreadBP(leg, p.StartDate); ok {
+			continue
+		}
 		accrual := utils.YearFraction(p.StartDate, p.EndDate, string(leg.DayCount))
-		pv01 += sign * spec.Notional * accrual * discCurve.DF(p.PayDate)
+		pv01 += sign * notionalAt(spec, p.StartDate) * accrual * discCurve.DF(p.PayDate)
+	}
+	if pv01 == 0 {
+		return 0, fmt.Errorf("pv01TargetLegPerDec: PV01 is zero for target leg (no unpaid period off its SpreadSchedule)")
 	}
 	return pv01, nil
 }
@@ -442,8 +1007,30 @@ func pv01TargetLegPerDec(spec market.SwapSpec, discCurve DiscountCurve, valuatio
 // It uses Newton-Raphson with an analytically computed PV01 (the objective is linear in spread),
 // so it typically converges in a single iteration.
 func SolveParSpread(spec market.SwapSpec, projPay ProjectionCurve, projRec ProjectionCurve, discCurve DiscountCurve, valuationDate time.Time, target SpreadTarget) (float64, error) {
+	return SolveParSpreadWithMaxIter(spec, projPay, projRec, discCurve, valuationDate, target, defaultParSpreadMaxIter)
+}
+
+const defaultParSpreadMaxIter = 10
+
+// SolveParSpreadWithMaxIter is SolveParSpread with an explicit cap on Newton iterations.
+//
+// If the cap is reached before the NPV tolerance is met, it returns the last spread
+// together with an error wrapping ErrNotConverged.
+func SolveParSpreadWithMaxIter(spec market.SwapSpec, projPay ProjectionCurve, projRec ProjectionCurve, discCurve DiscountCurve, valuationDate time.Time, target SpreadTarget, maxIter int) (float64, error) {
+	return solveSpread("SolveParSpread", spec, projPay, projRec, discCurve, valuationDate, target, 0, maxIter)
+}
+
+// SolveSpreadForNPV solves for the target leg spread (in bp) such that NPV equals targetNPV,
+// e.g. the agreed unwind or novation value. SolveParSpread is the targetNPV = 0 case.
+func SolveSpreadForNPV(spec market.SwapSpec, projPay ProjectionCurve, projRec ProjectionCurve, discCurve DiscountCurve, valuationDate time.Time, target SpreadTarget, targetNPV float64) (float64, error) {
+	return solveSpread("SolveSpreadForNPV", spec, projPay, projRec, discCurve, valuationDate, target, targetNPV, defaultParSpreadMaxIter)
+}
+
+// solveSpread runs Newton on the target leg spread using the analytic PV01 until
+// NPV - targetNPV is within tolerance. name prefixes returned errors.
+func solveSpread(name string, spec market.SwapSpec, projPay ProjectionCurve, projRec ProjectionCurve, discCurve DiscountCurve, valuationDate time.Time, target SpreadTarget, targetNPV float64, maxIter int) (float64, error) {
 	if err := validateSwapSpec(spec); err != nil {
-		return 0, fmt.Errorf("SolveParSpread: %w", err)
+		return 0, fmt.Errorf("%s: %w", name, err)
 	}
 	if isNilInterface(discCurve) {
 		return 0, ErrNilCurve
@@ -454,9 +1041,6 @@ func SolveParSpread(spec market.SwapSpec, projPay ProjectionCurve, projRec Proje
 		return 0, err
 	}
 	pv01PerBP := pv01Dec * 1e-4
-	if pv01PerBP == 0 {
-		return 0, fmt.Errorf("SolveParSpread: PV01 is zero for target leg")
-	}
 
 	spreadBP := spec.RecLegSpreadBP
 	if target == SpreadTargetPayLeg {
@@ -464,7 +1048,6 @@ func SolveParSpread(spec market.SwapSpec, projPay ProjectionCurve, projRec Proje
 	}
 
 	tolPV := 1e-10 * math.Max(1.0, math.Abs(spec.Notional))
-	maxIter := 10
 
 	for i := 0; i < maxIter; i++ {
 		tmp := spec
@@ -474,18 +1057,19 @@ func SolveParSpread(spec market.SwapSpec, projPay ProjectionCurve, projRec Proje
 		case SpreadTargetRecLeg:
 			tmp.RecLegSpreadBP = spreadBP
 		default:
-			return 0, fmt.Errorf("SolveParSpread: unknown target %d", target)
+			return 0, fmt.Errorf("%s: unknown target %d", name, target)
 		}
 
 		npv, err := NPV(tmp, projPay, projRec, discCurve, valuationDate)
 		if err != nil {
 			return 0, err
 		}
-		if math.Abs(npv) <= tolPV {
+		diff := npv - targetNPV
+		if math.Abs(diff) <= tolPV {
 			return spreadBP, nil
 		}
 
-		spreadBP = spreadBP - npv/pv01PerBP
+		spreadBP = spreadBP - diff/pv01PerBP
 	}
 
 	tmp := spec
@@ -495,7 +1079,7 @@ func SolveParSpread(spec market.SwapSpec, projPay ProjectionCurve, projRec Proje
 		tmp.RecLegSpreadBP = spreadBP
 	}
 	npv, _ := NPV(tmp, projPay, projRec, discCurve, valuationDate)
-	return spreadBP, fmt.Errorf("SolveParSpread: did not converge (spread=%.12f bp, npv=%.6g)", spreadBP, npv)
+	return spreadBP, fmt.Errorf("%s: %w (spread=%.12f bp, npv=%.6g)", name, ErrNotConverged, spreadBP, npv)
 }
 
 // ComputeOISParRateWithDiscount computes the par swap rate (in decimal) for an OIS leg
@@ -532,11 +1116,37 @@ func ComputeOISParRateWithDiscount(spec market.SwapSpec, projCurve, discCurve Di
 	return floatLegPV / annuity, nil
 }
 
+// OISParRateAnalytic computes the single-curve OIS par rate (in decimal) as
+// (DF(effective) - DF(maturity)) / annuity, where the annuity sums accrual * DF(pay)
+// over fixedLeg's schedule and both end dates are taken as adjusted by that schedule.
+// Compounded overnight forwards telescope to this closed form when projection and
+// discounting share disc and coupons pay on accrual end, so it cross-checks the
+// schedule-based ComputeOISParRateWithDiscount.
+// It returns NaN if the schedule cannot be generated or the annuity is zero.
+func OISParRateAnalytic(disc *curve.Curve, effective, maturity time.Time, fixedLeg market.LegConvention) float64 {
+	periods, err := GenerateSchedule(effective, maturity, fixedLeg)
+	if err != nil || len(periods) == 0 {
+		return math.NaN()
+	}
+
+	annuity := 0.0
+	for _, p := range periods {
+		accrual := utils.YearFraction(p.StartDate, p.EndDate, string(fixedLeg.DayCount))
+		annuity += accrual * disc.DF(p.PayDate)
+	}
+	if annuity == 0 {
+		return math.NaN()
+	}
+	start, end := periods[0].StartDate, periods[len(periods)-1].EndDate
+	return (disc.DF(start) - disc.DF(end)) / annuity
+}
+
 // SolveOISBasisSpread computes the basis spread (in bp) between two OIS curves.
 // This is the difference in par swap rates: payLegCurve par rate - recLegCurve par rate.
 // Both par rates are computed using the same discount curve (discCurve).
 // Used for OIS basis swaps where both legs reference the same overnight index
-// but from different venues (e.g., LCHS vs JSCC TONAR).
+// from different venues (e.g., LCHS vs JSCC TONAR), or two overnight indices
+// (e.g., SOFR vs Fed Funds).
 func SolveOISBasisSpread(spec market.SwapSpec, payProjCurve, recProjCurve, discCurve DiscountCurve, valuationDate time.Time) (float64, error) {
 	// Pay leg par rate: projection from payProjCurve, discount from discCurve
 	payParRate, err := ComputeOISParRateWithDiscount(spec, payProjCurve, discCurve, valuationDate, spec.PayLeg)
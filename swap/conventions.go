@@ -45,6 +45,28 @@ func GetDayCountConvention(cal calendar.CalendarID) DayCountConvention {
 			FixedFreqMonths: 6,
 		}
 
+	case calendar.FD:
+		// USD conventions (SOFR; no IBOR leg after LIBOR cessation):
+		// - OIS (SOFR): ACT/360
+		// - Fixed: ACT/360, annual
+		return DayCountConvention{
+			OIS:             "ACT/360",
+			FloatIBOR:       "ACT/360",
+			FixedIBOR:       "ACT/360",
+			FixedFreqMonths: 12,
+		}
+
+	case calendar.CA:
+		// CAD conventions (CORRA; CDOR ceased in 2024):
+		// - OIS (CORRA): ACT/365F
+		// - Fixed: ACT/365F, annual
+		return DayCountConvention{
+			OIS:             "ACT/365F",
+			FloatIBOR:       "ACT/365F",
+			FixedIBOR:       "ACT/365F",
+			FixedFreqMonths: 12,
+		}
+
 	case calendar.KR:
 		// KRW conventions:
 		// - OIS: ACT/365F
@@ -0,0 +1,40 @@
+package swap_test
+
+import (
+	"testing"
+
+	"github.com/meenmo/molib/instruments/swaps"
+	"github.com/meenmo/molib/swap"
+	"github.com/meenmo/molib/swap/market"
+)
+
+// The per-calendar day count table must agree with the OIS leg presets in
+// instruments/swaps so the two convention sources stay in parity.
+func TestGetDayCountConvention_MatchesOISPresets(t *testing.T) {
+	t.Parallel()
+
+	pairs := []struct {
+		name  string
+		float market.LegConvention
+	}{
+		{"SOFR", swaps.SOFRFloating},
+		{"ESTR", swaps.ESTRFloating},
+		{"TONAR", swaps.TONARFloating},
+		{"CORRA", swaps.CORRAFloating},
+	}
+
+	for _, p := range pairs {
+		conv := swap.GetDayCountConvention(p.float.Calendar)
+		if conv.OIS != string(p.float.DayCount) {
+			t.Errorf("%s: OIS day count %s, preset %s", p.name, conv.OIS, p.float.DayCount)
+		}
+	}
+
+	usd := swap.GetDayCountConvention(swaps.SOFRFixed.Calendar)
+	if usd.FixedIBOR != string(swaps.SOFRFixed.DayCount) {
+		t.Errorf("SOFR fixed day count %s, preset %s", usd.FixedIBOR, swaps.SOFRFixed.DayCount)
+	}
+	if usd.FixedFreqMonths != int(swaps.SOFRFixed.PayFrequency) {
+		t.Errorf("SOFR fixed frequency %d months, preset %d", usd.FixedFreqMonths, swaps.SOFRFixed.PayFrequency)
+	}
+}
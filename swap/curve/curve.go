@@ -1,13 +1,20 @@
 package curve
 
 import (
+	"errors"
+	"fmt"
 	"math"
+	"sync"
 	"time"
 
 	"github.com/meenmo/molib/calendar"
+	"github.com/meenmo/molib/swap/market"
 	"github.com/meenmo/molib/utils"
 )
 
+// ErrBeyondHorizon is returned when a curve is queried past its configured maximum horizon.
+var ErrBeyondHorizon = errors.New("date beyond curve horizon")
+
 type Curve struct {
 	settlement      time.Time
 	parQuotes       map[float64]float64 // tenor (years) -> percent
@@ -19,8 +26,30 @@ type Curve struct {
 	freqMonths      int
 	curveDayCount   string
 	fixedLegDC      FixedLegDayCount // day count for fixed leg during bootstrap
+	extrapolation   Extrapolation
+	maxHorizon      time.Time // zero means unlimited
+	interp          InterpMethod
+	spline          *zeroSpline // bootstrapped pillars, set for MonotoneCubicZero
+	nss             *NSSParams  // fitted function, set by FitNSS; overrides the nodes
+
+	// dfCache memoizes DF by date (time.Time -> float64) once the curve is built. It is nil
+	// during construction, when the bootstrap reads DF while nodes still move; copies that
+	// change DF get a fresh one.
+	dfCache *sync.Map
 }
 
+// Extrapolation selects how DF behaves beyond the last curve node.
+type Extrapolation string
+
+const (
+	// ExtrapolateFlatForward extends the last node segment's instantaneous forward (default).
+	ExtrapolateFlatForward Extrapolation = ""
+	// ExtrapolateFlatZero holds the last node's continuously-compounded zero rate flat.
+	ExtrapolateFlatZero Extrapolation = "FLAT_ZERO"
+	// ExtrapolateFlatDF holds the last node's DF constant (zero forward beyond it).
+	ExtrapolateFlatDF Extrapolation = "FLAT_DF"
+)
+
 // defaultCurveDayCount returns the time basis for curve construction.
 // Following market convention (and QuantLib), the curve time axis uses ACT/365F
 // for interpolation and zero rate calculations, regardless of currency.
@@ -40,50 +69,77 @@ const (
 	FixedLegDayCountIBOR FixedLegDayCount = "IBOR" // 30/360 for EUR, ACT/365F for JPY (IBOR IRS convention)
 )
 
+// QuoteUnit is the unit par quotes are given in.
+type QuoteUnit string
+
+const (
+	QuotePercent QuoteUnit = ""        // 2.5 means 2.5% (default)
+	QuoteDecimal QuoteUnit = "DECIMAL" // 0.025 means 2.5%
+)
+
+// parseQuotes keys quotes by tenor in years and converts them to percent, the unit the
+// bootstrap works in. At most one unit may be given; none means QuotePercent. It panics
+// on quotes that fail market.Quotes.Validate (bad tenors, duplicate tenors).
+func parseQuotes(quotes market.Quotes, unit []QuoteUnit) map[float64]float64 {
+	scale := quoteScale(unit)
+	parsed := make(map[float64]float64, len(quotes))
+	for _, p := range quotes.Sorted() {
+		parsed[p.Years] = p.Rate * scale
+	}
+	return parsed
+}
+
+// quoteScale returns the factor converting quotes in unit to percent.
+func quoteScale(unit []QuoteUnit) float64 {
+	if len(unit) > 1 {
+		panic(fmt.Sprintf("curve: at most one QuoteUnit, got %d", len(unit)))
+	}
+	if len(unit) == 1 {
+		switch unit[0] {
+		case QuotePercent:
+		case QuoteDecimal:
+			return 100.0
+		default:
+			panic(fmt.Sprintf("curve: unknown QuoteUnit %q", unit[0]))
+		}
+	}
+	return 1.0
+}
+
+// SpotSettlement returns the settlement date a curve built on curveDate should use:
+// curveDate plus spotLagDays business days on cal. Par quotes are for swaps starting
+// at spot, so passing curveDate itself (lag 0) shifts every pillar by the spot lag.
+// swap.InterestRateSwap settles its curves this way.
+func SpotSettlement(curveDate time.Time, cal calendar.CalendarID, spotLagDays int) time.Time {
+	return calendar.AddBusinessDays(cal, curveDate, spotLagDays)
+}
+
 // BuildCurve creates a par/zero curve using KRX-like bootstrap with 3M spacing.
 // Uses OIS conventions (ACT/360 for EUR) for the fixed leg.
-func BuildCurve(settlement time.Time, quotes map[string]float64, cal calendar.CalendarID, freqMonths int) *Curve {
-	parsed := make(map[float64]float64)
-	for k, v := range quotes {
-		parsed[tenorToYears(k)] = v
+// Quotes are in percent unless a QuoteUnit is given. It is BuildCurveFromInstruments with
+// a Swap per quote, on a freqMonths grid.
+func BuildCurve(settlement time.Time, quotes market.Quotes, cal calendar.CalendarID, freqMonths int, unit ...QuoteUnit) *Curve {
+	return newInstrumentCurve(settlement, swapInstruments(quotes, unit), cal, freqMonths, FixedLegDayCountOIS, LogLinear)
+}
+
+// BuildCurveWithInterp is BuildCurve with a choice of interpolation. The method is used
+// throughout: to fill non-quoted grid dates during bootstrap, and in DF and ZeroRateAt.
+func BuildCurveWithInterp(settlement time.Time, quotes market.Quotes, cal calendar.CalendarID, freqMonths int, interp InterpMethod, unit ...QuoteUnit) *Curve {
+	switch interp {
+	case LogLinear, MonotoneCubicZero:
+	default:
+		panic(fmt.Sprintf("curve: unknown InterpMethod %q", interp))
 	}
-	c := &Curve{
-		settlement:    settlement,
-		parQuotes:     parsed,
-		cal:           cal,
-		freqMonths:    freqMonths,
-		curveDayCount: defaultCurveDayCount(cal),
-		fixedLegDC:    FixedLegDayCountOIS,
-	}
-	c.paymentDates = c.generatePaymentDates()
-	c.parRates = c.buildParCurve()
-	c.discountFactors = c.bootstrapDiscountFactors()
-	c.zeros = c.buildZero()
-	return c
+	return newInstrumentCurve(settlement, swapInstruments(quotes, unit), cal, freqMonths, FixedLegDayCountOIS, interp)
 }
 
 // BuildIBORDiscountCurve creates a discount curve from IBOR swap quotes.
 // Uses IBOR IRS conventions (30/360 for EUR fixed leg) instead of OIS conventions.
 // This is appropriate for pre-2020 IBOR discounting where swaps were discounted
 // at the same IBOR rate (e.g., EURIBOR 6M discounting for EUR swaps).
-func BuildIBORDiscountCurve(settlement time.Time, quotes map[string]float64, cal calendar.CalendarID, freqMonths int) *Curve {
-	parsed := make(map[float64]float64)
-	for k, v := range quotes {
-		parsed[tenorToYears(k)] = v
-	}
-	c := &Curve{
-		settlement:    settlement,
-		parQuotes:     parsed,
-		cal:           cal,
-		freqMonths:    freqMonths,
-		curveDayCount: defaultCurveDayCount(cal),
-		fixedLegDC:    FixedLegDayCountIBOR,
-	}
-	c.paymentDates = c.generatePaymentDates()
-	c.parRates = c.buildParCurve()
-	c.discountFactors = c.bootstrapDiscountFactors()
-	c.zeros = c.buildZero()
-	return c
+// Quotes are in percent unless a QuoteUnit is given.
+func BuildIBORDiscountCurve(settlement time.Time, quotes market.Quotes, cal calendar.CalendarID, freqMonths int, unit ...QuoteUnit) *Curve {
+	return newInstrumentCurve(settlement, swapInstruments(quotes, unit), cal, freqMonths, FixedLegDayCountIBOR, LogLinear)
 }
 
 // NewCurveFromDFs creates a curve from explicitly provided discount factors.
@@ -97,6 +153,10 @@ func BuildIBORDiscountCurve(settlement time.Time, quotes map[string]float64, cal
 //
 // To avoid interpolation affecting results, provide DFs at all cashflow payment dates and
 // call with freqMonths <= 0.
+//
+// A supplied settlement DF within round-off of 1 is snapped to exactly 1.0, as for every
+// Curve, so DF(settlement) is always 1. It panics on a materially different one (e.g. DFs
+// read from the valuation date rather than spot): rebase such DFs to settlement first.
 func NewCurveFromDFs(settlement time.Time, dfs map[time.Time]float64, cal calendar.CalendarID, freqMonths int) *Curve {
 	c := &Curve{
 		settlement:      settlement,
@@ -124,6 +184,11 @@ func NewCurveFromDFs(settlement time.Time, dfs map[time.Time]float64, cal calend
 		c.paymentDates = c.generatePaymentDates()
 		for _, d := range c.paymentDates {
 			if _, ok := c.discountFactors[d]; !ok {
+				if d.Equal(settlement) {
+					// Anchor at settlement rather than extrapolating to it.
+					c.discountFactors[d] = 1.0
+					continue
+				}
 				c.discountFactors[d] = c.interpolateDF(d, inputDates, dfs)
 			}
 		}
@@ -132,7 +197,9 @@ func NewCurveFromDFs(settlement time.Time, dfs map[time.Time]float64, cal calend
 		c.paymentDates = inputDates
 	}
 
+	c.anchorSettlement()
 	c.zeros = c.buildZero()
+	c.enableDFCache()
 	return c
 }
 
@@ -199,7 +266,8 @@ func (c *Curve) buildParCurve() map[time.Time]float64 {
 			d1, d2 := c.adjacentQuotedDates(d, dateToTenor)
 			r1 := c.parQuotes[dateToTenor[d1]]
 			r2 := c.parQuotes[dateToTenor[d2]]
-			par[d] = (r1 + (r2-r1)*utils.Days(d1, d)/utils.Days(d1, d2)) / 100.0
+			rate := utils.Interp1D([]float64{0, utils.Days(d1, d2)}, []float64{r1, r2}, utils.Days(d1, d), false)
+			par[d] = rate / 100.0
 		}
 	}
 	return par
@@ -222,6 +290,10 @@ func (c *Curve) bootstrapDiscountFactors() map[time.Time]float64 {
 	}
 	lastQuotedDate := quotedDates[len(quotedDates)-1]
 
+	if c.interp == MonotoneCubicZero && len(quotedDates) > 1 {
+		return c.bootstrapMonotoneCubic(quotedDates[1:])
+	}
+
 	bootstrappedDates := []time.Time{dates[0]}
 	for _, maturity := range dates[1:] {
 		if maturity.After(lastQuotedDate) {
@@ -236,6 +308,9 @@ func (c *Curve) bootstrapDiscountFactors() map[time.Time]float64 {
 		bootstrappedDates = append(bootstrappedDates, maturity)
 		df[maturity] = c.solveOISDiscountFactor(bootstrappedDates, df, coupons, parRate)
 	}
+	if _, payDelay := c.fixedLegConventions(); payDelay > 0 {
+		c.resolvePaymentLagPillars(bootstrappedDates, 1, df)
+	}
 
 	// Interpolate DFs for all other payment dates using step-forward (log-linear)
 	for _, d := range dates {
@@ -264,22 +339,95 @@ func (c *Curve) bootstrapDiscountFactors() map[time.Time]float64 {
 	return df
 }
 
+// bootstrapMonotoneCubic solves DFs at the quoted pillars only, reading every coupon DF
+// off the monotone cubic zero spline, then fills the rest of the grid from the spline
+// (flat DF beyond the last pillar, as in the log-linear bootstrap). The spline is not
+// local (moving one pillar bends its neighbours' segments), so the pillars are re-solved
+// in passes until none moves. Sets c.spline for DF.
+func (c *Curve) bootstrapMonotoneCubic(pillars []time.Time) map[time.Time]float64 {
+	times := make([]float64, len(pillars))
+	dfs := make([]float64, len(pillars))
+	coupons := make([][]oisCoupon, len(pillars))
+	couponTimes := make([][]float64, len(pillars))
+	for i, d := range pillars {
+		times[i] = utils.YearFraction(c.settlement, d, c.curveDayCount)
+		dfs[i] = math.Exp(-c.parRates[d] * times[i])
+		coupons[i] = c.buildOISCoupons(d)
+		for _, cpn := range coupons[i] {
+			couponTimes[i] = append(couponTimes[i], utils.YearFraction(c.settlement, cpn.PaymentDate, c.curveDayCount))
+		}
+	}
+
+	residual := func(i int, x float64) float64 {
+		dfs[i] = x
+		s := newZeroSpline(times, dfs)
+		pv := 0.0
+		for k, cpn := range coupons[i] {
+			// Past the last pillar DF is held flat, matching the grid fill below.
+			tc := math.Min(couponTimes[i][k], s.last())
+			pv += s.discount(tc) * cpn.Accrual * c.parRates[pillars[i]]
+		}
+		return pv + x - 1.0
+	}
+
+	for pass := 0; pass < 100; pass++ {
+		moved := 0.0
+		for i := range pillars {
+			prev := dfs[i]
+			x := prev
+			for iter := 0; iter < 50; iter++ {
+				f := residual(i, x)
+				if math.Abs(f) < 1e-14 {
+					break
+				}
+				const h = 1e-7
+				fPrime := (residual(i, x+h) - f) / h
+				if math.Abs(fPrime) < 1e-15 {
+					break
+				}
+				x -= f / fPrime
+			}
+			dfs[i] = x
+			moved = math.Max(moved, math.Abs(x-prev))
+		}
+		if moved < 1e-14 {
+			break
+		}
+	}
+
+	c.spline = newZeroSpline(times, dfs)
+	df := make(map[time.Time]float64, len(c.paymentDates))
+	df[c.paymentDates[0]] = 1.0
+	for _, d := range c.paymentDates[1:] {
+		tTarget := utils.YearFraction(c.settlement, d, c.curveDayCount)
+		if tTarget > c.spline.last() {
+			tTarget = c.spline.last()
+		}
+		df[d] = utils.RoundTo(c.spline.discount(tTarget), 12)
+	}
+	return df
+}
+
 type oisCoupon struct {
 	PaymentDate time.Time
 	Accrual     float64
 }
 
 // buildOISCoupons generates fixed leg coupons for an OIS from settlement to maturity.
-// It assumes annual coupons (common for TONAR/ESTR) and applies currency-specific conventions.
-// The day count convention depends on c.fixedLegDC:
-//   - FixedLegDayCountOIS: ACT/360 for EUR (OIS convention)
-//   - FixedLegDayCountIBOR: 30/360 for EUR (IBOR IRS convention)
+// It assumes annual coupons (common for TONAR/ESTR) and applies currency-specific
+// conventions (see fixedLegConventions).
 func (c *Curve) buildOISCoupons(maturity time.Time) []oisCoupon {
-	coupons := []oisCoupon{}
+	accrualDC, payDelay := c.fixedLegConventions()
+	return c.buildFixedCoupons(c.settlement, maturity, 12, accrualDC, payDelay)
+}
 
-	// Determine conventions based on calendar and fixedLegDC
-	payDelay := 0
-	accrualDC := "ACT/365F" // Default
+// fixedLegConventions returns the accrual day count and payment lag (business days) of
+// the fixed legs the curve is bootstrapped from. The day count depends on c.fixedLegDC:
+//   - FixedLegDayCountOIS: ACT/360 for EUR (OIS convention)
+//   - FixedLegDayCountIBOR: 30/360 for EUR (IBOR IRS convention)
+func (c *Curve) fixedLegConventions() (accrualDC string, payDelay int) {
+	payDelay = 0
+	accrualDC = "ACT/365F" // Default
 
 	if c.cal == calendar.JP {
 		payDelay = 2
@@ -299,6 +447,10 @@ func (c *Curve) buildOISCoupons(maturity time.Time) []oisCoupon {
 		// GBP SONIA OIS fixed legs use ACT/365F with no payment delay.
 		accrualDC = "ACT/365F"
 		payDelay = 0
+	} else if c.cal == calendar.CA {
+		// CAD CORRA OIS fixed legs use ACT/365F with a T+1 payment lag.
+		accrualDC = "ACT/365F"
+		payDelay = 1
 	} else if c.cal == calendar.TARGET {
 		// Use 30/360 for IBOR discounting, ACT/360 for OIS
 		if c.fixedLegDC == FixedLegDayCountIBOR {
@@ -312,22 +464,29 @@ func (c *Curve) buildOISCoupons(maturity time.Time) []oisCoupon {
 			payDelay = 1
 		}
 	}
+	return accrualDC, payDelay
+}
+
+// buildFixedCoupons generates fixed coupons every months from start to maturity,
+// accruing on accrualDC and paying payDelay business days after each accrual end.
+func (c *Curve) buildFixedCoupons(start, maturity time.Time, months int, accrualDC string, payDelay int) []oisCoupon {
+	coupons := []oisCoupon{}
 
 	// Use backward schedule generation (Bloomberg SWPM convention) to avoid date drift
 	// from repeated Modified Following adjustments.
 	//
 	// This is especially important for EUR IBOR/IRS discount curves where annual fixed coupons
 	// must align to the swap maturity date.
-	months := 12
 
 	// Build unadjusted dates rolling backward from maturity.
 	unadjustedDates := []time.Time{}
+	// Each date is rolled from maturity itself so a Feb 29 maturity keeps Feb 29 in leap years.
 	current := maturity
-	for current.After(c.settlement) {
+	for i := 1; current.After(start); i++ {
 		unadjustedDates = append([]time.Time{current}, unadjustedDates...)
-		current = utils.AddMonth(current, -months)
+		current = utils.AddMonth(maturity, -i*months)
 	}
-	unadjustedDates = append([]time.Time{c.settlement}, unadjustedDates...)
+	unadjustedDates = append([]time.Time{start}, unadjustedDates...)
 
 	// Build coupons from consecutive date pairs.
 	for i := 0; i < len(unadjustedDates)-1; i++ {
@@ -401,6 +560,59 @@ func (c *Curve) solveOISDiscountFactor(quotedDates []time.Time, df map[time.Time
 	return guess
 }
 
+// resolvePaymentLagPillars re-solves the bootstrapped pillars when fixed coupons pay after
+// their accrual end. The sequential bootstrap extrapolates each swap's last coupon DF off
+// the segment being solved, while the finished curve interpolates it towards the next
+// pillar (or holds it flat past the last one), so a curve with a payment lag would miss
+// its own quotes by a fraction of a bp.
+// Each swap pillar, pillars[first:], is re-solved with every coupon DF read off the full
+// pillar set, in passes until none moves (as in bootstrapMonotoneCubic).
+func (c *Curve) resolvePaymentLagPillars(pillars []time.Time, first int, df map[time.Time]float64) {
+	coupons := make([][]oisCoupon, len(pillars))
+	for i := first; i < len(pillars); i++ {
+		coupons[i] = c.buildOISCoupons(pillars[i])
+	}
+
+	residual := func(i int, x float64) float64 {
+		df[pillars[i]] = x
+		pv := 0.0
+		last := pillars[len(pillars)-1]
+		for _, cpn := range coupons[i] {
+			d := df[last] // held flat past the last pillar, matching the grid fill
+			if !cpn.PaymentDate.After(last) {
+				d = c.getKnownDF(cpn.PaymentDate, df, pillars)
+			}
+			pv += d * cpn.Accrual * c.parRates[pillars[i]]
+		}
+		return pv + x - 1.0
+	}
+
+	for pass := 0; pass < 100; pass++ {
+		moved := 0.0
+		for i := first; i < len(pillars); i++ {
+			prev := df[pillars[i]]
+			x := prev
+			for iter := 0; iter < 50; iter++ {
+				f := residual(i, x)
+				if math.Abs(f) < 1e-14 {
+					break
+				}
+				const h = 1e-7
+				fPrime := (residual(i, x+h) - f) / h
+				if math.Abs(fPrime) < 1e-15 {
+					break
+				}
+				x -= f / fPrime
+			}
+			df[pillars[i]] = x
+			moved = math.Max(moved, math.Abs(x-prev))
+		}
+		if moved < 1e-14 {
+			break
+		}
+	}
+}
+
 // getKnownDF retrieves or interpolates a DF from already solved pillars.
 func (c *Curve) getKnownDF(t time.Time, df map[time.Time]float64, quotedDates []time.Time) float64 {
 	if val, ok := df[t]; ok {
@@ -767,18 +979,34 @@ func (c *Curve) interpolatePseudoDiscountFactor(target time.Time, pseudoDF map[t
 	return px1 * math.Exp(-forwardRate*(tTarget-t1))
 }
 
+// settlementDFRoundOff is how far from 1 a settlement DF may be and still count as
+// bootstrap or interpolation round-off.
+const settlementDFRoundOff = 1e-10
+
+// anchorSettlement snaps a settlement node within round-off of 1 to exactly 1.0; no other
+// node is touched. It panics on a settlement DF further from 1, which no curve may carry.
+func (c *Curve) anchorSettlement() {
+	df, ok := c.discountFactors[c.settlement]
+	if !ok {
+		return
+	}
+	if math.Abs(df-1.0) > settlementDFRoundOff {
+		panic(fmt.Sprintf("curve: DF at settlement %s is %.12g, not 1", c.settlement.Format("2006-01-02"), df))
+	}
+	c.discountFactors[c.settlement] = 1.0
+}
+
 func (c *Curve) buildZero() map[time.Time]float64 {
 	zc := make(map[time.Time]float64, len(c.paymentDates))
 
-	for i, d := range c.paymentDates {
-		if i == 0 {
+	for _, d := range c.paymentDates {
+		yearFrac := utils.YearFraction(c.settlement, d, c.curveDayCount)
+		if yearFrac == 0 {
+			// No time to maturity: report the short (par) rate.
 			zc[d] = utils.RoundTo(c.parRates[d]*100, 12)
-		} else {
-			df := c.discountFactors[d]
-			yearFrac := utils.YearFraction(c.settlement, d, c.curveDayCount)
-			zc[d] = utils.RoundTo(-math.Log(df)/yearFrac*100, 12)
-
+			continue
 		}
+		zc[d] = utils.RoundTo(-math.Log(c.discountFactors[d])/yearFrac*100, 12)
 	}
 	return zc
 }
@@ -810,7 +1038,46 @@ func (c *Curve) adjacentQuotedDates(target time.Time, dateToTenor map[time.Time]
 	return d1, d2
 }
 
+// ZeroRateAt returns the continuously-compounded zero rate (percent) to t, defined as
+// -ln(DF(t))/t on the curve's time axis so zeros and DFs agree at every date.
+// ParSwapRate returns the fixed rate (decimal) of a swap from effective to maturity that
+// the curve prices at par on its own discount factors: (DF(effective) − DF(maturity))
+// over the fixed annuity. Fixed coupons roll backward from maturity every fixedFreqMonths,
+// accrue on fixedDayCount and pay with the curve's own bootstrap payment lag, so for a
+// curve from BuildCurve or BuildIBORDiscountCurve, an annual swap from settlement to a
+// quoted pillar on the bootstrap day count reprices that quote.
+//
+// On an IBOR projection curve this is the single-curve par rate, not the OIS-discounted
+// quote the curve was bootstrapped to.
+func (c *Curve) ParSwapRate(effective, maturity time.Time, fixedFreqMonths int, fixedDayCount string) float64 {
+	if fixedFreqMonths <= 0 || !maturity.After(effective) {
+		return math.NaN()
+	}
+	_, payDelay := c.fixedLegConventions()
+	annuity := 0.0
+	for _, cpn := range c.buildFixedCoupons(effective, maturity, fixedFreqMonths, fixedDayCount, payDelay) {
+		annuity += cpn.Accrual * c.DF(cpn.PaymentDate)
+	}
+	if annuity == 0 {
+		return math.NaN()
+	}
+	return (c.DF(calendar.Adjust(c.cal, effective)) - c.DF(calendar.Adjust(c.cal, maturity))) / annuity
+}
+
+// ForwardRate returns the simple forward rate (decimal) from start to end implied by the
+// curve's discount factors, accrued on dayCount.
+func (c *Curve) ForwardRate(start, end time.Time, dayCount string) float64 {
+	alpha := utils.YearFraction(start, end, dayCount)
+	if alpha == 0 {
+		return 0
+	}
+	return (c.DF(start)/c.DF(end) - 1.0) / alpha
+}
+
 func (c *Curve) ZeroRateAt(t time.Time) float64 {
+	if c.nss != nil {
+		return c.nss.ZeroRate(utils.YearFraction(c.settlement, t, c.curveDayCount))
+	}
 	if z, ok := c.zeros[t]; ok {
 		return z
 	}
@@ -822,10 +1089,58 @@ func (c *Curve) ZeroRateAt(t time.Time) float64 {
 	return utils.RoundTo(-math.Log(df)/yearFrac*100, 12)
 }
 
+// DF returns the discount factor at t. Results are memoized per date: a curve is immutable
+// once built, and concurrent calls are safe.
 func (c *Curve) DF(t time.Time) float64 {
+	if c.dfCache == nil {
+		return c.computeDF(t)
+	}
+	if df, ok := c.dfCache.Load(t); ok {
+		return df.(float64)
+	}
+	df := c.computeDF(t)
+	c.dfCache.Store(t, df)
+	return df
+}
+
+// enableDFCache turns on DF memoization; constructors call it once the nodes are final.
+func (c *Curve) enableDFCache() {
+	c.dfCache = new(sync.Map)
+}
+
+// computeDF is DF without the cache.
+func (c *Curve) computeDF(t time.Time) float64 {
+	if t.Equal(c.settlement) {
+		return 1.0
+	}
+	if c.nss != nil {
+		tTarget := utils.YearFraction(c.settlement, t, c.curveDayCount)
+		return math.Exp(-c.nss.ZeroRate(tTarget) / 100 * tTarget)
+	}
 	if df, ok := c.discountFactors[t]; ok {
 		return df
 	}
+	if c.extrapolation != ExtrapolateFlatForward && len(c.paymentDates) > 0 {
+		last := c.paymentDates[len(c.paymentDates)-1]
+		if t.After(last) {
+			switch c.extrapolation {
+			case ExtrapolateFlatDF:
+				return c.discountFactors[last]
+			case ExtrapolateFlatZero:
+				tLast := utils.YearFraction(c.settlement, last, c.curveDayCount)
+				tTarget := utils.YearFraction(c.settlement, t, c.curveDayCount)
+				if tLast > 0 {
+					zero := -math.Log(c.discountFactors[last]) / tLast
+					return utils.RoundTo(math.Exp(-zero*tTarget), 12)
+				}
+			}
+		}
+	}
+	if c.spline != nil && t.After(c.settlement) {
+		if tTarget := utils.YearFraction(c.settlement, t, c.curveDayCount); tTarget <= c.spline.last() {
+			return utils.RoundTo(c.spline.discount(tTarget), 12)
+		}
+	}
 	d1, d2 := utils.AdjacentDates(t, c.paymentDates)
 	df1 := c.discountFactors[d1]
 	df2 := c.discountFactors[d2]
@@ -841,6 +1156,161 @@ func (c *Curve) DF(t time.Time) float64 {
 	return utils.RoundTo(df1*math.Exp(-forwardRate*(tTarget-t1)), 12)
 }
 
+// WithExtrapolation returns a copy of the curve that extrapolates beyond its last node
+// using mode. Node data is shared with the receiver, which is left unchanged.
+func (c *Curve) WithExtrapolation(mode Extrapolation) *Curve {
+	out := *c
+	out.extrapolation = mode
+	out.enableDFCache()
+	return &out
+}
+
+// ShiftZero returns a copy of the curve with every zero rate bumped by bumpBP: node DFs
+// become exp(−(z+bump)·t) on the curve's time axis, without re-running the bootstrap, so
+// the copy no longer reprices the par quotes (ParQuotes still reports them).
+// Interpolation and extrapolation act on the shifted nodes as before.
+func (c *Curve) ShiftZero(bumpBP float64) *Curve {
+	out := *c
+	shift := bumpBP * 1e-4
+	out.discountFactors = make(map[time.Time]float64, len(c.discountFactors))
+	for d, df := range c.discountFactors {
+		out.discountFactors[d] = df * math.Exp(-shift*utils.YearFraction(c.settlement, d, c.curveDayCount))
+	}
+	out.zeros = make(map[time.Time]float64, len(c.zeros))
+	for d, z := range c.zeros {
+		out.zeros[d] = z + bumpBP/100.0
+	}
+	if c.spline != nil {
+		out.spline = c.spline.shifted(shift)
+	}
+	if c.nss != nil {
+		p := *c.nss
+		p.Beta0 += bumpBP / 100.0
+		out.nss = &p
+	}
+	out.enableDFCache()
+	return &out
+}
+
+// NodeSensitivities returns the analytic partial derivatives of DF at each of dates with
+// respect to the curve's node DFs, from the log-linear interpolation (and extrapolation)
+// weights DF applies between nodes. Each map entry is one node, keyed by its tenor in years
+// (on a bootstrapped curve the grid tenor, so quoted pillars keep their quoted tenor; on a
+// curve from NewCurveFromDFs its ACT/365F time), holding the partials for dates in order.
+// Nodes no date depends on are omitted, as is settlement, whose DF is fixed at 1.
+//
+// It returns nil for curves whose DF is not local in the nodes (MonotoneCubicZero, FitNSS).
+func (c *Curve) NodeSensitivities(dates []time.Time) map[float64][]float64 {
+	if c.spline != nil || c.nss != nil || len(c.paymentDates) < 2 {
+		return nil
+	}
+	gridTenor := c.paymentDatesToTenor()
+	sens := make(map[float64][]float64)
+	add := func(node time.Time, i int, v float64) {
+		if node.Equal(c.settlement) || v == 0 {
+			return
+		}
+		key, ok := gridTenor[node]
+		if !ok || c.freqMonths <= 0 {
+			key = utils.YearFraction(c.settlement, node, c.curveDayCount)
+		}
+		row, ok := sens[key]
+		if !ok {
+			row = make([]float64, len(dates))
+			sens[key] = row
+		}
+		row[i] += v
+	}
+
+	last := c.paymentDates[len(c.paymentDates)-1]
+	for i, t := range dates {
+		if t.Equal(c.settlement) {
+			continue
+		}
+		if _, ok := c.discountFactors[t]; ok {
+			add(t, i, 1)
+			continue
+		}
+		tTarget := utils.YearFraction(c.settlement, t, c.curveDayCount)
+		if t.After(last) {
+			switch c.extrapolation {
+			case ExtrapolateFlatDF:
+				add(last, i, 1)
+				continue
+			case ExtrapolateFlatZero:
+				// DF(t) = DF(last)^(t/tLast)
+				if tLast := utils.YearFraction(c.settlement, last, c.curveDayCount); tLast > 0 {
+					add(last, i, tTarget/tLast*c.DF(t)/c.discountFactors[last])
+					continue
+				}
+			}
+		}
+		// DF(t) = DF(d1)^(1-w) · DF(d2)^w, w the time weight (beyond 1 when extrapolating).
+		d1, d2 := utils.AdjacentDates(t, c.paymentDates)
+		t1 := utils.YearFraction(c.settlement, d1, c.curveDayCount)
+		t2 := utils.YearFraction(c.settlement, d2, c.curveDayCount)
+		if t2 == t1 {
+			add(d1, i, 1)
+			continue
+		}
+		w := (tTarget - t1) / (t2 - t1)
+		df := c.DF(t)
+		add(d1, i, (1-w)*df/c.discountFactors[d1])
+		add(d2, i, w*df/c.discountFactors[d2])
+	}
+	return sens
+}
+
+// WithMaxHorizon returns a copy of the curve that refuses dates after horizon: CheckHorizon
+// reports ErrBeyondHorizon for them, and swap.GetDiscountFactors/NPV surface it instead of
+// extrapolating. A zero horizon removes the limit.
+func (c *Curve) WithMaxHorizon(horizon time.Time) *Curve {
+	out := *c
+	out.maxHorizon = horizon
+	return &out
+}
+
+// CheckHorizon returns an error wrapping ErrBeyondHorizon if t is after the curve's
+// maximum horizon. DF itself still extrapolates; callers that need the guard check first.
+func (c *Curve) CheckHorizon(t time.Time) error {
+	if !c.maxHorizon.IsZero() && t.After(c.maxHorizon) {
+		return fmt.Errorf("%w: %s after %s", ErrBeyondHorizon, t.Format("2006-01-02"), c.maxHorizon.Format("2006-01-02"))
+	}
+	return nil
+}
+
+// CurveSnapshot is a copy of a curve's node grid, suitable for auditing or
+// rebuilding the curve via NewCurveFromDFs.
+type CurveSnapshot struct {
+	Settlement      time.Time
+	DayCount        string
+	ParQuotes       map[float64]float64 // tenor (years) -> percent
+	Dates           []time.Time
+	DiscountFactors []float64
+	ZeroRates       []float64 // percent
+}
+
+// Snapshot returns a copy of the curve's nodes in date order.
+func (c *Curve) Snapshot() CurveSnapshot {
+	snap := CurveSnapshot{
+		Settlement:      c.settlement,
+		DayCount:        c.curveDayCount,
+		ParQuotes:       make(map[float64]float64, len(c.parQuotes)),
+		Dates:           make([]time.Time, len(c.paymentDates)),
+		DiscountFactors: make([]float64, len(c.paymentDates)),
+		ZeroRates:       make([]float64, len(c.paymentDates)),
+	}
+	for k, v := range c.parQuotes {
+		snap.ParQuotes[k] = v
+	}
+	copy(snap.Dates, c.paymentDates)
+	for i, d := range c.paymentDates {
+		snap.DiscountFactors[i] = c.discountFactors[d]
+		snap.ZeroRates[i] = c.ZeroRateAt(d)
+	}
+	return snap
+}
+
 // Settlement returns the curve's settlement date.
 func (c *Curve) Settlement() time.Time {
 	return c.settlement
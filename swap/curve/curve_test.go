@@ -2,6 +2,9 @@ package curve_test
 
 import (
 	"math"
+	"reflect"
+	"strconv"
+	"strings"
 	"testing"
 	"time"
 
@@ -10,6 +13,7 @@ import (
 	"github.com/meenmo/molib/swap"
 	"github.com/meenmo/molib/swap/curve"
 	"github.com/meenmo/molib/swap/market"
+	"github.com/meenmo/molib/utils"
 )
 
 func TestBuildCurve_RedundantInterpolatedNodeHasSmallImpactOnForwardParRate(t *testing.T) {
@@ -60,3 +64,643 @@ func TestBuildCurve_RedundantInterpolatedNodeHasSmallImpactOnForwardParRate(t *t
 		t.Fatalf("redundant 2Y node moved 2Yx5Y par rate by %.6f bp", diffBP)
 	}
 }
+
+func TestSpotSettlement_MatchesInterestRateSwapDiscountCurve(t *testing.T) {
+	t.Parallel()
+
+	curveDate := time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)
+	quotes := map[string]float64{
+		"1Y":  2.06795,
+		"2Y":  2.153975,
+		"3Y":  2.24,
+		"5Y":  2.3495,
+		"10Y": 2.6955,
+	}
+
+	trade, err := swap.InterestRateSwap(swap.InterestRateSwapParams{
+		DataSource:     swap.DataSourceBGN,
+		ClearingHouse:  swap.ClearingHouseOTC,
+		CurveDate:      curveDate,
+		TradeDate:      curveDate,
+		SwapTenorYears: 5,
+		Notional:       1_000_000,
+		PayLeg:         swaps.ESTRFixed,
+		RecLeg:         swaps.ESTRFloating,
+		DiscountingOIS: swaps.ESTRFloating,
+		OISQuotes:      quotes,
+		RecLegQuotes:   quotes,
+	})
+	if err != nil {
+		t.Fatalf("InterestRateSwap: %v", err)
+	}
+
+	settlement := curve.SpotSettlement(curveDate, calendar.TARGET, 2)
+	if want := time.Date(2026, 3, 12, 0, 0, 0, 0, time.UTC); !settlement.Equal(want) {
+		t.Fatalf("spot settlement: got %s want %s", settlement.Format("2006-01-02"), want.Format("2006-01-02"))
+	}
+
+	matched := curve.BuildCurve(settlement, quotes, calendar.TARGET, 1)
+	unlagged := curve.BuildCurve(curveDate, quotes, calendar.TARGET, 1)
+
+	for _, d := range []time.Time{
+		time.Date(2027, 3, 12, 0, 0, 0, 0, time.UTC),
+		time.Date(2029, 6, 15, 0, 0, 0, 0, time.UTC),
+		trade.Spec.MaturityDate,
+	} {
+		want := trade.DiscountCurve.DF(d)
+		if got := matched.DF(d); math.Abs(got-want) > 1e-14 {
+			t.Fatalf("DF(%s) with spot settlement: got %.14f want %.14f", d.Format("2006-01-02"), got, want)
+		}
+		if got := unlagged.DF(d); math.Abs(got-want) < 1e-8 {
+			t.Fatalf("DF(%s) settled on curve date should differ from the swap engine", d.Format("2006-01-02"))
+		}
+	}
+}
+
+func TestCurve_ExtrapolationBeyondLastPillar(t *testing.T) {
+	t.Parallel()
+
+	settlement := time.Date(2026, 3, 12, 0, 0, 0, 0, time.UTC)
+	estr := map[string]float64{
+		"1Y": 2.07, "2Y": 2.15, "5Y": 2.35, "10Y": 2.70, "20Y": 2.99, "30Y": 2.94,
+	}
+	euribor := map[string]float64{
+		"1Y": 2.25, "2Y": 2.35, "5Y": 2.56, "10Y": 2.90, "20Y": 3.15, "30Y": 3.05,
+	}
+	disc := curve.BuildCurve(settlement, estr, calendar.TARGET, 1)
+	proj := curve.BuildProjectionCurve(settlement, swaps.EURIBOR6MFloating, euribor, disc)
+	flatZero := proj.WithExtrapolation(curve.ExtrapolateFlatZero)
+
+	dates := proj.PaymentDates()
+	last := dates[len(dates)-1]
+	prev := dates[len(dates)-2]
+	start := time.Date(2066, 3, 12, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2066, 9, 14, 0, 0, 0, 0, time.UTC)
+	if !start.After(last) {
+		t.Fatalf("expected 40Y start beyond last pillar %s", last.Format("2006-01-02"))
+	}
+
+	ccFwd := func(c *curve.Curve, d1, d2 time.Time) float64 {
+		return math.Log(c.DF(d1)/c.DF(d2)) / (utils.YearFraction(d1, d2, "ACT/365F"))
+	}
+
+	// Default: the last node segment's forward carries on.
+	lastSegment := ccFwd(proj, prev, last)
+	if got := ccFwd(proj, start, end); math.Abs(got-lastSegment) > 1e-8 {
+		t.Fatalf("flat-forward 40Y forward %.10f, last segment %.10f", got, lastSegment)
+	}
+
+	// Flat zero: the forward beyond the last node equals the last zero rate.
+	lastZero := flatZero.ZeroRateAt(last) / 100
+	if got := ccFwd(flatZero, start, end); math.Abs(got-lastZero) > 1e-8 {
+		t.Fatalf("flat-zero 40Y forward %.10f, last zero %.10f", got, lastZero)
+	}
+	if math.Abs(lastZero-lastSegment) < 1e-5 {
+		t.Fatalf("expected the modes to differ: zero %.8f segment forward %.8f", lastZero, lastSegment)
+	}
+
+	// Inside the curve and on the original, nothing changes.
+	mid := time.Date(2040, 6, 15, 0, 0, 0, 0, time.UTC)
+	if proj.DF(mid) != flatZero.DF(mid) {
+		t.Fatalf("extrapolation mode changed an interior DF")
+	}
+	t.Logf("40Y 6M forward: flat-forward=%.4f%% flat-zero=%.4f%%", 100*ccFwd(proj, start, end), 100*ccFwd(flatZero, start, end))
+}
+
+func TestCurve_FlatDFExtrapolation(t *testing.T) {
+	t.Parallel()
+
+	settlement := time.Date(2026, 3, 12, 0, 0, 0, 0, time.UTC)
+	quotes := map[string]float64{"1Y": 2.07, "5Y": 2.35, "10Y": 2.70, "30Y": 2.94}
+	built := curve.BuildCurve(settlement, quotes, calendar.TARGET, 1)
+	// BuildCurve holds DF flat over its buffer grid dates past the last pillar, so take the
+	// pillar DFs alone: the last node segment then carries a real forward to extend.
+	nodes := map[time.Time]float64{settlement: 1}
+	for _, years := range []int{1, 5, 10, 30} {
+		d := calendar.Adjust(calendar.TARGET, settlement.AddDate(years, 0, 0))
+		nodes[d] = built.DF(d)
+	}
+	flatFwd := curve.NewCurveFromDFs(settlement, nodes, calendar.TARGET, 0)
+	flatDF := flatFwd.WithExtrapolation(curve.ExtrapolateFlatDF)
+
+	dates := flatFwd.PaymentDates()
+	last := dates[len(dates)-1]
+	beyond := last.AddDate(5, 0, 0)
+
+	if got, want := flatDF.DF(beyond), flatFwd.DF(last); got != want {
+		t.Fatalf("flat-DF DF(last+5Y)=%.12f, want last node DF %.12f", got, want)
+	}
+	if flatFwd.DF(beyond) >= flatFwd.DF(last) {
+		t.Fatalf("flat-forward DF(last+5Y)=%.12f should keep decaying past %.12f", flatFwd.DF(beyond), flatFwd.DF(last))
+	}
+
+	// ZeroRateAt follows the mode: a held DF dilutes the zero rate over the longer horizon.
+	tLast := utils.YearFraction(settlement, last, "ACT/365F")
+	tBeyond := utils.YearFraction(settlement, beyond, "ACT/365F")
+	want := flatDF.ZeroRateAt(last) * tLast / tBeyond
+	if got := flatDF.ZeroRateAt(beyond); math.Abs(got-want) > 1e-9 {
+		t.Fatalf("flat-DF zero at last+5Y %.10f, want %.10f", got, want)
+	}
+}
+
+func TestCurve_ZeroRateConsistentWithDF(t *testing.T) {
+	t.Parallel()
+
+	settlement := time.Date(2026, 3, 12, 0, 0, 0, 0, time.UTC)
+	quotes := map[string]float64{"1Y": 2.07, "2Y": 2.15, "5Y": 2.35, "10Y": 2.70, "30Y": 2.94}
+	built := curve.BuildCurve(settlement, quotes, calendar.TARGET, 1)
+	// DF nodes only, with no node at settlement.
+	fromDFs := curve.NewCurveFromDFs(settlement, map[time.Time]float64{
+		time.Date(2026, 9, 14, 0, 0, 0, 0, time.UTC): 0.9895,
+		time.Date(2028, 3, 13, 0, 0, 0, 0, time.UTC): 0.9580,
+		time.Date(2031, 3, 12, 0, 0, 0, 0, time.UTC): 0.8930,
+	}, calendar.TARGET, 0)
+
+	dates := []time.Time{
+		time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2026, 9, 14, 0, 0, 0, 0, time.UTC),
+		time.Date(2027, 7, 19, 0, 0, 0, 0, time.UTC),
+		time.Date(2031, 3, 12, 0, 0, 0, 0, time.UTC),
+		time.Date(2043, 11, 3, 0, 0, 0, 0, time.UTC),
+		time.Date(2060, 1, 15, 0, 0, 0, 0, time.UTC),
+	}
+	for name, c := range map[string]*curve.Curve{"BuildCurve": built, "NewCurveFromDFs": fromDFs} {
+		for _, d := range dates {
+			tau := utils.YearFraction(settlement, d, "ACT/365F")
+			want := math.Exp(-c.ZeroRateAt(d) / 100 * tau)
+			if got := c.DF(d); math.Abs(got-want) > 1e-12 {
+				t.Errorf("%s: DF(%s)=%.14f but exp(-z*t)=%.14f", name, d.Format("2006-01-02"), got, want)
+			}
+		}
+	}
+}
+
+func TestCurve_DFAtSettlementIsExactlyOne(t *testing.T) {
+	t.Parallel()
+
+	settlement := time.Date(2026, 3, 12, 0, 0, 0, 0, time.UTC)
+	estr := map[string]float64{"1Y": 2.07, "2Y": 2.15, "5Y": 2.35, "10Y": 2.70}
+	euribor := map[string]float64{"1Y": 2.25, "2Y": 2.35, "5Y": 2.56, "10Y": 2.90}
+	built := curve.BuildCurve(settlement, estr, calendar.TARGET, 1)
+	mid := time.Date(2027, 3, 12, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2031, 3, 12, 0, 0, 0, 0, time.UTC)
+
+	// Injected DFs with round-off at the settlement node.
+	roundOff := curve.NewCurveFromDFs(settlement, map[time.Time]float64{
+		settlement: 1 - 3e-13,
+		mid:        0.9790,
+		end:        0.8930,
+	}, calendar.TARGET, 0)
+
+	curves := map[string]*curve.Curve{
+		"BuildCurve":            built,
+		"BuildCurveWithInterp":  curve.BuildCurveWithInterp(settlement, estr, calendar.TARGET, 1, curve.MonotoneCubicZero),
+		"BuildIBORDiscount":     curve.BuildIBORDiscountCurve(settlement, euribor, calendar.TARGET, 1),
+		"BuildProjectionCurve":  curve.BuildProjectionCurve(settlement, swaps.EURIBOR6MFloating, euribor, built),
+		"NewCurveFromDFs":       roundOff,
+		"NewCurveFromDFs(grid)": curve.NewCurveFromDFs(settlement, map[time.Time]float64{mid: 0.9790, end: 0.8930}, calendar.TARGET, 1),
+	}
+	for name, c := range curves {
+		if got := c.DF(settlement); got != 1.0 {
+			t.Errorf("%s: DF(settlement) = %.17g, want exactly 1", name, got)
+		}
+	}
+
+	// Snapping the round-off leaves the other injected DFs exactly as given.
+	if got := roundOff.DF(end); got != 0.8930 {
+		t.Errorf("DF(%s) = %.17g, want the injected 0.893", end.Format("2006-01-02"), got)
+	}
+
+	// DFs read from the valuation date two days before spot are off 1 at settlement by more
+	// than round-off, and are rejected.
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Errorf("NewCurveFromDFs accepted a settlement DF of 0.999593")
+			}
+		}()
+		curve.NewCurveFromDFs(settlement, map[time.Time]float64{
+			settlement: 0.999593,
+			mid:        0.9790,
+			end:        0.8930,
+		}, calendar.TARGET, 0)
+	}()
+}
+
+func TestBuildCurve_DecimalQuotesMatchPercent(t *testing.T) {
+	t.Parallel()
+
+	settlement := time.Date(2026, 3, 12, 0, 0, 0, 0, time.UTC)
+	percent := map[string]float64{"1Y": 2.07, "2Y": 2.15, "5Y": 2.35, "10Y": 2.70}
+	decimal := make(map[string]float64, len(percent))
+	for k, v := range percent {
+		decimal[k] = v / 100
+	}
+	euriborPct := map[string]float64{"1Y": 2.25, "2Y": 2.35, "5Y": 2.56, "10Y": 2.90}
+	euriborDec := make(map[string]float64, len(euriborPct))
+	for k, v := range euriborPct {
+		euriborDec[k] = v / 100
+	}
+
+	ois := curve.BuildCurve(settlement, percent, calendar.TARGET, 1)
+	oisDec := curve.BuildCurve(settlement, decimal, calendar.TARGET, 1, curve.QuoteDecimal)
+	ibor := curve.BuildIBORDiscountCurve(settlement, percent, calendar.TARGET, 1, curve.QuotePercent)
+	iborDec := curve.BuildIBORDiscountCurve(settlement, decimal, calendar.TARGET, 1, curve.QuoteDecimal)
+	proj := curve.BuildProjectionCurve(settlement, swaps.EURIBOR6MFloating, euriborPct, ois)
+	projDec := curve.BuildProjectionCurve(settlement, swaps.EURIBOR6MFloating, euriborDec, oisDec, curve.QuoteDecimal)
+
+	pairs := []struct {
+		name     string
+		pct, dec *curve.Curve
+	}{
+		{"OIS", ois, oisDec},
+		{"IBOR discount", ibor, iborDec},
+		{"EURIBOR6M projection", proj, projDec},
+	}
+	for _, p := range pairs {
+		for _, d := range p.pct.PaymentDates() {
+			if diff := math.Abs(p.pct.DF(d) - p.dec.DF(d)); diff > 1e-14 {
+				t.Fatalf("%s DF(%s): percent=%.16f decimal=%.16f", p.name, d.Format("2006-01-02"), p.pct.DF(d), p.dec.DF(d))
+			}
+		}
+	}
+
+	// Decimal quotes read as percent would price near-zero rates.
+	wrong := curve.BuildCurve(settlement, decimal, calendar.TARGET, 1)
+	at := settlement.AddDate(5, 0, 0)
+	if math.Abs(wrong.DF(at)-ois.DF(at)) < 0.05 {
+		t.Fatalf("expected decimal quotes without QuoteDecimal to produce a different curve")
+	}
+}
+
+func TestBuildCurveWithInterp_MonotoneCubicForwardsContinuous(t *testing.T) {
+	t.Parallel()
+
+	settlement := time.Date(2026, 3, 12, 0, 0, 0, 0, time.UTC)
+	quotes := map[string]float64{
+		"1M": 2.02, "3M": 2.03, "6M": 2.045, "1Y": 2.07, "2Y": 2.15, "3Y": 2.24,
+		"5Y": 2.35, "7Y": 2.48, "10Y": 2.70, "15Y": 2.86, "20Y": 2.92, "30Y": 2.94,
+	}
+	logLinear := curve.BuildCurveWithInterp(settlement, quotes, calendar.TARGET, 1, curve.LogLinear)
+	cubic := curve.BuildCurveWithInterp(settlement, quotes, calendar.TARGET, 1, curve.MonotoneCubicZero)
+
+	leg := market.LegConvention{
+		LegType:               market.LegFloating,
+		ReferenceIndex:        market.EURIBOR3M,
+		DayCount:              market.Act360,
+		ResetFrequency:        market.FreqQuarterly,
+		PayFrequency:          market.FreqQuarterly,
+		BusinessDayAdjustment: market.ModifiedFollowing,
+		RollConvention:        market.BackwardEOM,
+		Calendar:              calendar.TARGET,
+		ResetPosition:         market.ResetInAdvance,
+	}
+	// A jump at a pillar shows up as a large second difference between adjacent quarters;
+	// a smooth but sloped forward curve has a small one.
+	maxKink := func(c *curve.Curve) float64 {
+		fwds, err := swap.GetForwardRates(c, settlement, settlement.AddDate(30, 0, 0), leg)
+		if err != nil {
+			t.Fatalf("GetForwardRates: %v", err)
+		}
+		kink := 0.0
+		for i, f := range fwds {
+			if f.Rate <= 0 {
+				t.Fatalf("non-positive forward %.6f%% for %s", f.Rate*100, f.StartDate.Format("2006-01-02"))
+			}
+			if i > 1 {
+				kink = math.Max(kink, math.Abs(f.Rate-2*fwds[i-1].Rate+fwds[i-2].Rate))
+			}
+		}
+		return kink
+	}
+
+	cubicKink, logKink := maxKink(cubic), maxKink(logLinear)
+	if cubicKink > 0.0005 {
+		t.Fatalf("monotone cubic forwards kink by %.2fbp between adjacent quarters", cubicKink*1e4)
+	}
+	if cubicKink >= logKink {
+		t.Fatalf("monotone cubic forwards (max kink %.2fbp) not smoother than log-linear (%.2fbp)", cubicKink*1e4, logKink*1e4)
+	}
+
+	// The default builder is log-linear; ZeroRateAt agrees with DF under the spline too.
+	if got, want := logLinear.DF(settlement.AddDate(4, 2, 0)), curve.BuildCurve(settlement, quotes, calendar.TARGET, 1).DF(settlement.AddDate(4, 2, 0)); got != want {
+		t.Fatalf("LogLinear DF %.14f differs from BuildCurve %.14f", got, want)
+	}
+	d := time.Date(2033, 7, 19, 0, 0, 0, 0, time.UTC)
+	tau := utils.YearFraction(settlement, d, "ACT/365F")
+	if got, want := cubic.DF(d), math.Exp(-cubic.ZeroRateAt(d)/100*tau); math.Abs(got-want) > 1e-12 {
+		t.Fatalf("cubic DF %.14f but exp(-z*t)=%.14f", got, want)
+	}
+}
+
+func TestBuildProjectionCurve_BootstrapsOnIndexTenor(t *testing.T) {
+	t.Parallel()
+
+	settlement := time.Date(2026, 3, 12, 0, 0, 0, 0, time.UTC)
+	disc := curve.BuildCurve(settlement, map[string]float64{"1Y": 2.06795, "2Y": 2.153975, "5Y": 2.3495, "10Y": 2.6955}, calendar.TARGET, 1)
+	quotes := map[string]float64{"1Y": 2.10, "2Y": 2.20, "5Y": 2.42, "10Y": 2.75}
+
+	// A EURIBOR 3M leg paying semi-annually.
+	leg := swaps.EURIBOR3MFloating
+	leg.PayFrequency = market.FreqSemi
+
+	got := curve.BuildProjectionCurve(settlement, leg, quotes, disc)
+	threeMonth := curve.BuildDualCurveWithFreq(settlement, quotes, disc, calendar.TARGET, 3, 1)
+	sixMonth := curve.BuildDualCurveWithFreq(settlement, quotes, disc, calendar.TARGET, 6, 1)
+
+	maxDiff6M := 0.0
+	for _, years := range []int{1, 2, 5, 10} {
+		d := calendar.Adjust(calendar.TARGET, settlement.AddDate(years, 0, 0))
+		if diff := math.Abs(got.DF(d) - threeMonth.DF(d)); diff > 1e-14 {
+			t.Errorf("%dY: DF %.12f differs from the 3M bootstrap %.12f", years, got.DF(d), threeMonth.DF(d))
+		}
+		maxDiff6M = math.Max(maxDiff6M, math.Abs(got.DF(d)-sixMonth.DF(d)))
+	}
+	if maxDiff6M < 1e-8 {
+		t.Errorf("3M and 6M bootstraps should differ; max DF difference %.2e", maxDiff6M)
+	}
+}
+
+func TestCurve_ParSwapRateRepricesInputQuotes(t *testing.T) {
+	settlement := time.Date(2026, 3, 12, 0, 0, 0, 0, time.UTC)
+	quotes := map[string]float64{
+		"1Y":  2.06795,
+		"2Y":  2.153975,
+		"3Y":  2.24,
+		"5Y":  2.3495,
+		"7Y":  2.484,
+		"10Y": 2.6955,
+		"20Y": 2.98995,
+		"30Y": 2.9435,
+	}
+
+	cases := []struct {
+		name     string
+		cal      calendar.CalendarID
+		dayCount string
+		build    func(time.Time, market.Quotes, calendar.CalendarID, int, ...curve.QuoteUnit) *curve.Curve
+	}{
+		{"ESTR OIS", calendar.TARGET, "ACT/360", curve.BuildCurve},
+		{"SONIA OIS", calendar.EN, "ACT/365F", curve.BuildCurve},
+		{"EUR IBOR discount", calendar.TARGET, "30/360", curve.BuildIBORDiscountCurve},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := tc.build(settlement, quotes, tc.cal, 12)
+			for tenor, quote := range quotes {
+				years, _ := strconv.Atoi(strings.TrimSuffix(tenor, "Y"))
+				maturity := calendar.Adjust(tc.cal, settlement.AddDate(years, 0, 0))
+				got := c.ParSwapRate(settlement, maturity, 12, tc.dayCount)
+				if diff := math.Abs(got - quote/100); diff > 1e-8 {
+					t.Errorf("%s: par rate %.10f, want %.10f (diff %.2e)", tenor, got, quote/100, diff)
+				}
+			}
+		})
+	}
+}
+
+func TestCurve_ForwardRateMatchesDiscountFactors(t *testing.T) {
+	settlement := time.Date(2026, 3, 12, 0, 0, 0, 0, time.UTC)
+	c := curve.BuildCurve(settlement, map[string]float64{"1Y": 2.06795, "2Y": 2.153975, "5Y": 2.3495, "10Y": 2.6955}, calendar.TARGET, 1)
+
+	start := time.Date(2027, 9, 14, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2028, 3, 14, 0, 0, 0, 0, time.UTC)
+	want := (c.DF(start)/c.DF(end) - 1) / utils.YearFraction(start, end, "ACT/360")
+	if got := c.ForwardRate(start, end, "ACT/360"); math.Abs(got-want) > 1e-15 {
+		t.Fatalf("forward %.12f, want %.12f", got, want)
+	}
+	if got := c.ForwardRate(start, start, "ACT/360"); got != 0 {
+		t.Fatalf("zero-length forward = %g, want 0", got)
+	}
+}
+
+func TestCurve_ShiftZero(t *testing.T) {
+	settlement := time.Date(2026, 3, 12, 0, 0, 0, 0, time.UTC)
+	quotes := map[string]float64{"1Y": 2.06795, "2Y": 2.153975, "5Y": 2.3495, "10Y": 2.6955}
+	tenY := calendar.Adjust(calendar.TARGET, settlement.AddDate(10, 0, 0))
+	offGrid := time.Date(2031, 7, 19, 0, 0, 0, 0, time.UTC)
+
+	for _, interp := range []curve.InterpMethod{curve.LogLinear, curve.MonotoneCubicZero} {
+		c := curve.BuildCurveWithInterp(settlement, quotes, calendar.TARGET, 1, interp)
+
+		same := c.ShiftZero(0)
+		for _, d := range []time.Time{settlement, offGrid, tenY, tenY.AddDate(3, 0, 0)} {
+			if got, want := same.DF(d), c.DF(d); got != want {
+				t.Fatalf("%s: ShiftZero(0) DF(%s) = %.14f, want %.14f", interp, d.Format("2006-01-02"), got, want)
+			}
+		}
+
+		// A +1bp zero shift scales the 10Y zero-coupon DF by exp(-0.0001·t).
+		up := c.ShiftZero(1)
+		tau := utils.YearFraction(settlement, tenY, "ACT/365F")
+		if got, want := up.DF(tenY), c.DF(tenY)*math.Exp(-1e-4*tau); math.Abs(got-want) > 1e-11 {
+			t.Fatalf("%s: shifted 10Y DF %.14f, want %.14f", interp, got, want)
+		}
+		if got, want := up.ZeroRateAt(offGrid), c.ZeroRateAt(offGrid)+0.01; math.Abs(got-want) > 1e-9 {
+			t.Fatalf("%s: shifted zero %.10f%%, want %.10f%%", interp, got, want)
+		}
+		if c.DF(tenY) == up.DF(tenY) {
+			t.Fatalf("%s: ShiftZero modified the base curve", interp)
+		}
+	}
+}
+
+func TestBuildCurveWithTurns_YearEndTurnRaisesStraddlingForward(t *testing.T) {
+	settlement := time.Date(2026, 3, 12, 0, 0, 0, 0, time.UTC)
+	quotes := map[string]float64{"1Y": 2.07, "2Y": 2.15, "5Y": 2.35, "10Y": 2.70}
+	turn := curve.TurnAdjustment{Date: time.Date(2026, 12, 31, 0, 0, 0, 0, time.UTC), SizeBP: 10}
+
+	smooth := curve.BuildCurve(settlement, quotes, calendar.TARGET, 1)
+	turned := curve.BuildCurveWithTurns(settlement, quotes, calendar.TARGET, 1, []curve.TurnAdjustment{turn})
+
+	for tenor, quote := range quotes {
+		years, _ := strconv.Atoi(strings.TrimSuffix(tenor, "Y"))
+		maturity := calendar.Adjust(calendar.TARGET, settlement.AddDate(years, 0, 0))
+		if got := turned.ParSwapRate(settlement, maturity, 12, "ACT/360"); math.Abs(got-quote/100) > 1e-8 {
+			t.Errorf("%s: par rate %.10f, want %.10f", tenor, got, quote/100)
+		}
+	}
+
+	leg := market.LegConvention{
+		LegType:               market.LegFloating,
+		ReferenceIndex:        market.ESTR,
+		DayCount:              market.Act360,
+		ResetFrequency:        market.FreqMonthly,
+		PayFrequency:          market.FreqMonthly,
+		BusinessDayAdjustment: market.ModifiedFollowing,
+		RollConvention:        market.BackwardEOM,
+		Calendar:              calendar.TARGET,
+	}
+	maturity := settlement.AddDate(2, 0, 0)
+	base, err := swap.GetForwardRates(smooth, settlement, maturity, leg)
+	if err != nil {
+		t.Fatalf("GetForwardRates: %v", err)
+	}
+	fwds, err := swap.GetForwardRates(turned, settlement, maturity, leg)
+	if err != nil {
+		t.Fatalf("GetForwardRates: %v", err)
+	}
+
+	// The 1Y pillar segment pays for the turn, so the months around it dip by about a
+	// bp and the straddling month rises by the rest: ~10bp over its neighbours.
+	straddled := -1
+	for i, f := range fwds {
+		if !turn.Date.Before(f.StartDate) && turn.Date.Before(f.EndDate) {
+			straddled = i
+			continue
+		}
+		if diffBP := (f.Rate - base[i].Rate) * 1e4; math.Abs(diffBP) > 2 {
+			t.Errorf("forward %s-%s moved %.3fbp, want about unchanged",
+				f.StartDate.Format("2006-01-02"), f.EndDate.Format("2006-01-02"), diffBP)
+		}
+	}
+	if straddled < 1 || straddled == len(fwds)-1 {
+		t.Fatalf("no interior period straddles the turn (index %d)", straddled)
+	}
+	move := func(i int) float64 { return (fwds[i].Rate - base[i].Rate) * 1e4 }
+	if jump := move(straddled) - (move(straddled-1)+move(straddled+1))/2; math.Abs(jump-10) > 0.5 {
+		t.Fatalf("straddling forward rose %.3fbp over its neighbours, want ~10bp", jump)
+	}
+}
+
+func TestBuildCurveFromInstruments_RepricesShortEnd(t *testing.T) {
+	settlement := time.Date(2026, 3, 12, 0, 0, 0, 0, time.UTC)
+	threeM := calendar.Adjust(calendar.TARGET, settlement.AddDate(0, 3, 0))
+	sixM := calendar.Adjust(calendar.TARGET, settlement.AddDate(0, 6, 0))
+	oneY := calendar.Adjust(calendar.TARGET, settlement.AddDate(1, 0, 0))
+
+	c := curve.BuildCurveFromInstruments(settlement, []curve.Instrument{
+		curve.Swap{Tenor: "1Y", Rate: 2.07},
+		curve.FRA{Start: threeM, End: sixM, Rate: 2.10, DayCount: "ACT/360"},
+		curve.Deposit{End: threeM, Rate: 2.00, DayCount: "ACT/360"},
+	}, calendar.TARGET)
+
+	if got := (1/c.DF(threeM) - 1) / utils.YearFraction(settlement, threeM, "ACT/360"); math.Abs(got-0.0200) > 1e-12 {
+		t.Errorf("deposit reprices at %.12f, want 0.02", got)
+	}
+	if got := c.ForwardRate(threeM, sixM, "ACT/360"); math.Abs(got-0.0210) > 1e-12 {
+		t.Errorf("3x6 FRA reprices at %.12f, want 0.021", got)
+	}
+	if got := c.ParSwapRate(settlement, oneY, 12, "ACT/360"); math.Abs(got-0.0207) > 1e-10 {
+		t.Errorf("1Y swap reprices at %.12f, want 0.0207", got)
+	}
+
+	// Swaps alone delegate to the par-grid bootstrap of BuildCurve.
+	quotes := map[string]float64{"1Y": 2.07, "2Y": 2.15, "5Y": 2.35}
+	swapsOnly := curve.BuildCurveFromInstruments(settlement, []curve.Instrument{
+		curve.Swap{Tenor: "1Y", Rate: 2.07}, curve.Swap{Tenor: "2Y", Rate: 2.15}, curve.Swap{Tenor: "5Y", Rate: 2.35},
+	}, calendar.TARGET)
+	built := curve.BuildCurve(settlement, quotes, calendar.TARGET, 1)
+	for _, d := range built.PaymentDates() {
+		if got, want := swapsOnly.DF(d), built.DF(d); got != want {
+			t.Fatalf("swaps-only DF(%s)=%.12f, BuildCurve %.12f", d.Format("2006-01-02"), got, want)
+		}
+	}
+}
+
+func TestFitNSS_RecoversGeneratingParameters(t *testing.T) {
+	settlement := time.Date(2026, 3, 12, 0, 0, 0, 0, time.UTC)
+	want := curve.NSSParams{Beta0: 4.0, Beta1: -1.5, Beta2: 2.0, Beta3: -1.0, Tau1: 1.5, Tau2: 7.0}
+	tenors := []float64{0.25, 0.5, 1, 2, 3, 4, 5, 7, 10, 12, 15, 20, 25, 30}
+	zeros := make([]float64, len(tenors))
+	for i, tn := range tenors {
+		zeros[i] = want.ZeroRate(tn)
+	}
+
+	c, got, err := curve.FitNSS(settlement, tenors, zeros, calendar.TARGET)
+	if err != nil {
+		t.Fatalf("FitNSS: %v", err)
+	}
+	if got.RMS > 1e-8 {
+		t.Fatalf("RMS residual %.3g, want ~0", got.RMS)
+	}
+	for _, p := range []struct {
+		name      string
+		got, want float64
+	}{
+		{"Beta0", got.Beta0, want.Beta0}, {"Beta1", got.Beta1, want.Beta1},
+		{"Beta2", got.Beta2, want.Beta2}, {"Beta3", got.Beta3, want.Beta3},
+		{"Tau1", got.Tau1, want.Tau1}, {"Tau2", got.Tau2, want.Tau2},
+	} {
+		if math.Abs(p.got-p.want) > 1e-4 {
+			t.Errorf("%s = %.8f, want %.8f", p.name, p.got, p.want)
+		}
+	}
+
+	// The curve evaluates the function itself, off the tenor grid as well as on it.
+	for _, d := range []time.Time{time.Date(2031, 7, 19, 0, 0, 0, 0, time.UTC), time.Date(2070, 1, 2, 0, 0, 0, 0, time.UTC)} {
+		tau := utils.YearFraction(settlement, d, "ACT/365F")
+		if z := c.ZeroRateAt(d); math.Abs(z-got.ZeroRate(tau)) > 1e-12 {
+			t.Fatalf("ZeroRateAt(%s) = %.12f, want NSS %.12f", d.Format("2006-01-02"), z, got.ZeroRate(tau))
+		}
+		if df, want := c.DF(d), math.Exp(-got.ZeroRate(tau)/100*tau); math.Abs(df-want) > 1e-14 {
+			t.Fatalf("DF(%s) = %.14f, want %.14f", d.Format("2006-01-02"), df, want)
+		}
+	}
+	if c.DF(settlement) != 1 {
+		t.Fatalf("DF(settlement) = %v, want 1", c.DF(settlement))
+	}
+
+	if _, _, err := curve.FitNSS(settlement, tenors[:5], zeros[:5], calendar.TARGET); err == nil {
+		t.Fatalf("FitNSS with 5 points: expected error")
+	}
+}
+
+func TestCurve_NodeSensitivitiesMatchFiniteDifferences(t *testing.T) {
+	settlement := time.Date(2026, 3, 12, 0, 0, 0, 0, time.UTC)
+	quotes := map[string]float64{"1Y": 2.06795, "2Y": 2.153975, "5Y": 2.3495, "10Y": 2.6955}
+	c := curve.BuildCurve(settlement, quotes, calendar.TARGET, 1)
+
+	grid := c.PaymentDates()
+	nodeByTenor := make(map[float64]time.Time, len(grid))
+	for i, d := range grid {
+		nodeByTenor[float64(i)/12.0] = d
+	}
+	fiveY := grid[60]
+	dates := []time.Time{
+		fiveY, // a quoted pillar
+		time.Date(2031, 7, 19, 0, 0, 0, 0, time.UTC), // between grid nodes
+		grid[len(grid)-1].AddDate(1, 0, 0),           // beyond the last node
+	}
+	sens := c.NodeSensitivities(dates)
+	if got := sens[5.0]; got == nil || got[0] != 1 {
+		t.Fatalf("DF(5Y) sensitivity to the 5Y pillar = %v, want 1", got)
+	}
+
+	// Rebuilding from the node DFs reproduces the curve, so bumping one node in the
+	// rebuild is a finite-difference partial of the original.
+	nodes := c.PillarDFs()
+	rebuilt := func(node time.Time, h float64) *curve.Curve {
+		dfs := make(map[time.Time]float64, len(nodes))
+		for d, df := range nodes {
+			dfs[d] = df
+		}
+		dfs[node] += h
+		return curve.NewCurveFromDFs(settlement, dfs, calendar.TARGET, 0)
+	}
+	const h = 1e-6
+	nonzero := make([]int, len(dates))
+	for tenor, row := range sens {
+		node, ok := nodeByTenor[tenor]
+		if !ok {
+			t.Fatalf("sensitivity keyed by %v, not a grid tenor", tenor)
+		}
+		up, down := rebuilt(node, h), rebuilt(node, -h)
+		for i, d := range dates {
+			fd := (up.DF(d) - down.DF(d)) / (2 * h)
+			if math.Abs(row[i]-fd) > 1e-5 {
+				t.Errorf("dDF(%s)/dDF(%s) = %.8f, finite difference %.8f", d.Format("2006-01-02"), node.Format("2006-01-02"), row[i], fd)
+			}
+			if row[i] != 0 {
+				nonzero[i]++
+			}
+		}
+	}
+	if want := []int{1, 2, 2}; !reflect.DeepEqual(nonzero, want) {
+		t.Fatalf("nodes per date %v, want %v", nonzero, want)
+	}
+
+	if s := curve.BuildCurveWithInterp(settlement, quotes, calendar.TARGET, 1, curve.MonotoneCubicZero).NodeSensitivities(dates); s != nil {
+		t.Fatalf("monotone cubic curve returned sensitivities")
+	}
+}
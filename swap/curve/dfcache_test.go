@@ -0,0 +1,80 @@
+package curve
+
+import (
+	"math"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/meenmo/molib/calendar"
+)
+
+// monthlySchedule50Y returns 50 years of monthly dates from settlement, offset by a few
+// days so most fall between curve nodes.
+func monthlySchedule50Y(settlement time.Time) []time.Time {
+	dates := make([]time.Time, 0, 600)
+	for m := 1; m <= 600; m++ {
+		dates = append(dates, settlement.AddDate(0, m, 3))
+	}
+	return dates
+}
+
+func dfCacheTestCurves() []*Curve {
+	settlement := time.Date(2026, 3, 12, 0, 0, 0, 0, time.UTC)
+	quotes := map[string]float64{"1Y": 2.07, "2Y": 2.15, "5Y": 2.35, "10Y": 2.70, "30Y": 2.95, "50Y": 2.80}
+	disc := BuildCurve(settlement, quotes, calendar.TARGET, 1)
+	return []*Curve{
+		disc,
+		disc.WithExtrapolation(ExtrapolateFlatZero),
+		disc.ShiftZero(25),
+		BuildCurveWithInterp(settlement, quotes, calendar.TARGET, 1, MonotoneCubicZero),
+		NewCurveFromDFs(settlement, disc.PillarDFs(), calendar.TARGET, 0),
+	}
+}
+
+func TestCurve_CachedDFMatchesUncached(t *testing.T) {
+	for i, c := range dfCacheTestCurves() {
+		if c.dfCache == nil {
+			t.Fatalf("curve %d: DF cache not enabled after construction", i)
+		}
+		dates := monthlySchedule50Y(c.settlement)
+		dates = append(dates, c.paymentDates...)
+
+		// Fill the cache from several goroutines at once, then read it back.
+		var wg sync.WaitGroup
+		for g := 0; g < 4; g++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for _, d := range dates {
+					c.DF(d)
+				}
+			}()
+		}
+		wg.Wait()
+		for _, d := range dates {
+			if got, want := c.DF(d), c.computeDF(d); math.Abs(got-want) > 1e-12 {
+				t.Fatalf("curve %d: cached DF(%s) = %.15f, uncached %.15f", i, d.Format("2006-01-02"), got, want)
+			}
+		}
+	}
+}
+
+func BenchmarkCurve_DF50YMonthly(b *testing.B) {
+	c := dfCacheTestCurves()[0]
+	dates := monthlySchedule50Y(c.settlement)
+	b.Run("uncached", func(b *testing.B) {
+		for b.Loop() {
+			for _, d := range dates {
+				c.computeDF(d)
+			}
+		}
+	})
+	b.Run("cached", func(b *testing.B) {
+		for b.Loop() {
+			for _, d := range dates {
+				c.DF(d)
+			}
+		}
+	})
+}
@@ -0,0 +1,220 @@
+package curve
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/meenmo/molib/calendar"
+	"github.com/meenmo/molib/swap/market"
+	"github.com/meenmo/molib/utils"
+)
+
+// Instrument is a curve bootstrap input: a Deposit, an FRA or a Swap.
+type Instrument interface {
+	instrument()
+}
+
+// Deposit is a cash deposit paying simple interest at Rate (percent) from Start to End.
+type Deposit struct {
+	Start    time.Time // zero means settlement
+	End      time.Time
+	Rate     float64
+	DayCount string // empty means ACT/360
+}
+
+// FRA is a forward rate agreement on the simple rate (percent) from Start to End.
+type FRA struct {
+	Start    time.Time
+	End      time.Time
+	Rate     float64
+	DayCount string // empty means ACT/360
+}
+
+// Swap is a par swap quote (percent) starting at settlement. Its fixed leg follows the
+// bootstrap conventions of the curve's calendar: annual coupons rolled back from
+// maturity, with the calendar's day count and payment lag (see buildOISCoupons).
+type Swap struct {
+	Tenor string // e.g. "2Y", "18M"
+	Rate  float64
+}
+
+func (Deposit) instrument() {}
+func (FRA) instrument()     {}
+func (Swap) instrument()    {}
+
+// BuildCurveFromInstruments bootstraps a curve from deposits, FRAs and par swaps on a
+// monthly grid. Deposits and FRAs are solved first, in order of end date, each fixing
+// the DF at its end; swaps then extend the curve from the last of them, one maturity at
+// a time. DFs are log-linear between the solved dates, and the curve reprices every
+// instrument.
+//
+// With swaps alone it is BuildCurve with a monthly grid. It panics on a swap maturing on
+// or before the last deposit or FRA end, on overlapping deposit/FRA end dates, and on
+// FRAs starting before settlement.
+func BuildCurveFromInstruments(settlement time.Time, instruments []Instrument, cal calendar.CalendarID) *Curve {
+	return newInstrumentCurve(settlement, instruments, cal, 1, FixedLegDayCountOIS, LogLinear)
+}
+
+// swapInstruments converts par quotes to Swap instruments in percent.
+func swapInstruments(quotes market.Quotes, unit []QuoteUnit) []Instrument {
+	scale := quoteScale(unit)
+	out := make([]Instrument, 0, len(quotes))
+	for _, p := range quotes.Sorted() {
+		out = append(out, Swap{Tenor: p.Tenor, Rate: p.Rate * scale})
+	}
+	return out
+}
+
+// newInstrumentCurve is the constructor behind BuildCurve and its variants. Swaps alone
+// bootstrap on the freqMonths grid, every grid date solved at a par rate interpolated
+// between the quotes; with deposits or FRAs only the instrument dates are solved.
+func newInstrumentCurve(settlement time.Time, instruments []Instrument, cal calendar.CalendarID, freqMonths int, fixedLegDC FixedLegDayCount, interp InterpMethod) *Curve {
+	quotes := market.Quotes{}
+	var short []Instrument
+	for _, inst := range instruments {
+		switch in := inst.(type) {
+		case Swap:
+			if err := quotes.Add(in.Tenor, in.Rate); err != nil {
+				panic(fmt.Sprintf("curve: %v", err))
+			}
+		case Deposit, FRA:
+			short = append(short, inst)
+		default:
+			panic(fmt.Sprintf("curve: unknown instrument %T", inst))
+		}
+	}
+	c := &Curve{
+		settlement:    settlement,
+		parQuotes:     parseQuotes(quotes, nil),
+		cal:           cal,
+		freqMonths:    freqMonths,
+		curveDayCount: defaultCurveDayCount(cal),
+		fixedLegDC:    fixedLegDC,
+		interp:        interp,
+	}
+	if len(short) == 0 {
+		c.paymentDates = c.generatePaymentDates()
+		c.parRates = c.buildParCurve()
+		c.discountFactors = c.bootstrapDiscountFactors()
+	} else {
+		if interp != LogLinear {
+			panic(fmt.Sprintf("curve: InterpMethod %q does not support deposits or FRAs", interp))
+		}
+		c.discountFactors = c.bootstrapInstruments(short)
+	}
+	c.anchorSettlement()
+	c.zeros = c.buildZero()
+	c.enableDFCache()
+	return c
+}
+
+// moneyMarket is a deposit or FRA reduced to what the bootstrap needs.
+type moneyMarket struct {
+	start, end time.Time
+	rate       float64 // decimal
+	dayCount   string
+}
+
+// bootstrapInstruments solves DFs at the deposit and FRA end dates, then at the swap
+// maturities in c.parQuotes, and fills a monthly grid (extended with the solved dates)
+// log-linearly between them. Sets c.paymentDates and c.parRates.
+func (c *Curve) bootstrapInstruments(short []Instrument) map[time.Time]float64 {
+	mm := make([]moneyMarket, 0, len(short))
+	for _, inst := range short {
+		var m moneyMarket
+		switch in := inst.(type) {
+		case Deposit:
+			m = moneyMarket{start: in.Start, end: in.End, rate: in.Rate / 100.0, dayCount: in.DayCount}
+			if m.start.IsZero() {
+				m.start = c.settlement
+			}
+		case FRA:
+			m = moneyMarket{start: in.Start, end: in.End, rate: in.Rate / 100.0, dayCount: in.DayCount}
+		}
+		if m.dayCount == "" {
+			m.dayCount = "ACT/360"
+		}
+		if m.start.Before(c.settlement) || !m.end.After(m.start) {
+			panic(fmt.Sprintf("curve: %T %s to %s is not a period after settlement %s", inst,
+				m.start.Format("2006-01-02"), m.end.Format("2006-01-02"), c.settlement.Format("2006-01-02")))
+		}
+		mm = append(mm, m)
+	}
+	sort.SliceStable(mm, func(i, j int) bool { return mm[i].end.Before(mm[j].end) })
+
+	df := map[time.Time]float64{c.settlement: 1.0}
+	nodes := []time.Time{c.settlement}
+	c.parRates = map[time.Time]float64{c.settlement: mm[0].rate}
+	for _, m := range mm {
+		last := nodes[len(nodes)-1]
+		if !m.end.After(last) {
+			panic(fmt.Sprintf("curve: deposit/FRA ending %s overlaps one ending %s", m.end.Format("2006-01-02"), last.Format("2006-01-02")))
+		}
+		growth := 1.0 + m.rate*utils.YearFraction(m.start, m.end, m.dayCount)
+		if !m.start.After(last) {
+			df[m.end] = c.getKnownDF(m.start, df, nodes) / growth
+		} else {
+			// DF(start) is log-linear between the last node and the unknown DF(end) = x:
+			// x = DF(last)^(1-w)·x^w / growth, so x = DF(last)·growth^(-1/(1-w)).
+			w := utils.YearFraction(last, m.start, c.curveDayCount) / utils.YearFraction(last, m.end, c.curveDayCount)
+			df[m.end] = df[last] * math.Pow(growth, -1.0/(1.0-w))
+		}
+		c.parRates[m.end] = m.rate
+		nodes = append(nodes, m.end)
+	}
+
+	firstSwap := len(nodes)
+	for _, p := range sortedTenors(c.parQuotes) {
+		maturity := calendar.Adjust(c.cal, c.settlement.AddDate(0, int(math.Round(p*12)), 0))
+		if last := nodes[len(nodes)-1]; !maturity.After(last) {
+			panic(fmt.Sprintf("curve: swap maturing %s is not after the last deposit/FRA end %s",
+				maturity.Format("2006-01-02"), last.Format("2006-01-02")))
+		}
+		nodes = append(nodes, maturity)
+		c.parRates[maturity] = c.parQuotes[p] / 100.0
+		df[maturity] = c.solveOISDiscountFactor(nodes, df, c.buildOISCoupons(maturity), c.parRates[maturity])
+	}
+	if _, payDelay := c.fixedLegConventions(); payDelay > 0 && firstSwap < len(nodes) {
+		c.resolvePaymentLagPillars(nodes, firstSwap, df)
+	}
+
+	// Monthly grid to a year past the last node (as generatePaymentDates), plus the nodes.
+	last := nodes[len(nodes)-1]
+	dates := append([]time.Time(nil), nodes...)
+	for i := 1; ; i++ {
+		d := calendar.Adjust(c.cal, c.settlement.AddDate(0, c.freqMonths*i, 0))
+		dates = append(dates, d)
+		if d.After(last.AddDate(1, 0, 0)) {
+			break
+		}
+	}
+	sort.Slice(dates, func(i, j int) bool { return dates[i].Before(dates[j]) })
+	c.paymentDates = dates[:0]
+	for _, d := range dates {
+		if n := len(c.paymentDates); n > 0 && c.paymentDates[n-1].Equal(d) {
+			continue
+		}
+		c.paymentDates = append(c.paymentDates, d)
+		if _, ok := df[d]; ok {
+			continue
+		}
+		if d.After(last) {
+			df[d] = df[last] // flat past the last node, as the swap bootstrap
+		} else {
+			df[d] = utils.RoundTo(c.getKnownDF(d, df, nodes), 12)
+		}
+	}
+	return df
+}
+
+// sortedTenors returns the tenors of quotes in increasing order.
+func sortedTenors(quotes map[float64]float64) []float64 {
+	tenors := make([]float64, 0, len(quotes))
+	for t := range quotes {
+		tenors = append(tenors, t)
+	}
+	sort.Float64s(tenors)
+	return tenors
+}
@@ -0,0 +1,270 @@
+package curve
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/meenmo/molib/calendar"
+	"github.com/meenmo/molib/utils"
+)
+
+// NSSParams are Nelson-Siegel-Svensson parameters. Betas are in the unit of the fitted
+// zero rates (percent for FitNSS); taus are in years.
+type NSSParams struct {
+	Beta0, Beta1, Beta2, Beta3 float64
+	Tau1, Tau2                 float64
+	RMS                        float64 // root-mean-square fit residual, in the betas' unit
+}
+
+// ZeroRate returns the NSS zero rate at t years:
+//
+//	β0 + β1·L(t/τ1) + β2·(L(t/τ1) − e^(−t/τ1)) + β3·(L(t/τ2) − e^(−t/τ2)),  L(x) = (1 − e^(−x))/x
+//
+// At t = 0 it is the short-rate limit β0 + β1.
+func (p NSSParams) ZeroRate(t float64) float64 {
+	f := nssLoadings(t, p.Tau1, p.Tau2)
+	return p.Beta0*f[0] + p.Beta1*f[1] + p.Beta2*f[2] + p.Beta3*f[3]
+}
+
+func nssLoadings(t, tau1, tau2 float64) [4]float64 {
+	slope := func(x float64) (float64, float64) {
+		if x < 1e-8 {
+			return 1 - x/2, 1 - x
+		}
+		e := math.Exp(-x)
+		return (1 - e) / x, e
+	}
+	l1, e1 := slope(t / tau1)
+	l2, e2 := slope(t / tau2)
+	return [4]float64{1, l1, l1 - e1, l2 - e2}
+}
+
+// FitNSS least-squares fits the six Nelson-Siegel-Svensson parameters to continuously
+// compounded zero rates (percent) at tenors (years from settlement, on the curve's ACT/365F
+// axis) and returns a curve evaluating the fitted function. The betas enter linearly, so
+// they are solved exactly for each (τ1, τ2), which is searched on a log grid and then
+// refined with Nelder-Mead.
+//
+// The curve's DF and ZeroRateAt evaluate the NSS function at any date, with no pillar
+// interpolation or extrapolation; its nodes (for Snapshot and PillarDFs) are the tenor
+// dates rolled on cal. It has no par quotes.
+func FitNSS(settlement time.Time, tenors []float64, zeroRates []float64, cal calendar.CalendarID) (*Curve, NSSParams, error) {
+	if len(tenors) != len(zeroRates) {
+		return nil, NSSParams{}, fmt.Errorf("FitNSS: %d tenors but %d zero rates", len(tenors), len(zeroRates))
+	}
+	if len(tenors) < 6 {
+		return nil, NSSParams{}, fmt.Errorf("FitNSS: need at least 6 points for 6 parameters, got %d", len(tenors))
+	}
+	for i, t := range tenors {
+		if !(t > 0) || math.IsInf(t, 0) {
+			return nil, NSSParams{}, fmt.Errorf("FitNSS: tenor %v is not a positive number of years", t)
+		}
+		if math.IsNaN(zeroRates[i]) || math.IsInf(zeroRates[i], 0) {
+			return nil, NSSParams{}, fmt.Errorf("FitNSS: zero rate at tenor %v is %v", t, zeroRates[i])
+		}
+	}
+
+	p, ok := fitNSS(tenors, zeroRates)
+	if !ok {
+		return nil, NSSParams{}, fmt.Errorf("FitNSS: no (tau1, tau2) gives a solvable fit")
+	}
+
+	c := &Curve{
+		settlement:      settlement,
+		parQuotes:       make(map[float64]float64),
+		cal:             cal,
+		curveDayCount:   defaultCurveDayCount(cal),
+		discountFactors: make(map[time.Time]float64, len(tenors)+1),
+		zeros:           make(map[time.Time]float64, len(tenors)+1),
+		nss:             &p,
+	}
+	seen := map[time.Time]bool{settlement: true}
+	c.paymentDates = append(c.paymentDates, settlement)
+	for _, t := range tenors {
+		d := calendar.Adjust(cal, settlement.AddDate(0, int(math.Round(t*12)), 0))
+		if !seen[d] {
+			seen[d] = true
+			c.paymentDates = append(c.paymentDates, d)
+		}
+	}
+	utils.SortDates(c.paymentDates)
+	for _, d := range c.paymentDates {
+		c.discountFactors[d] = c.DF(d)
+		c.zeros[d] = c.ZeroRateAt(d)
+	}
+	c.enableDFCache()
+	return c, p, nil
+}
+
+// fitNSS profiles the betas out of the fit and minimizes the residual over (ln τ1, ln τ2).
+func fitNSS(tenors, zeros []float64) (NSSParams, bool) {
+	sse := func(x [2]float64) float64 {
+		_, s, ok := nssBetas(tenors, zeros, math.Exp(x[0]), math.Exp(x[1]))
+		if !ok {
+			return math.Inf(1)
+		}
+		return s
+	}
+
+	// The residual surface has long flat valleys (a large τ2 mimics a level shift), so
+	// refine from every local minimum of the grid, not just the lowest point.
+	const gridPoints = 30
+	lo, hi := math.Log(0.05), math.Log(30.0)
+	step := (hi - lo) / (gridPoints - 1)
+	grid := make([][]float64, gridPoints)
+	for i := range grid {
+		grid[i] = make([]float64, gridPoints)
+		for j := range grid[i] {
+			grid[i][j] = math.Inf(1)
+			if i != j { // equal taus make the last two loadings collinear
+				grid[i][j] = sse([2]float64{lo + step*float64(i), lo + step*float64(j)})
+			}
+		}
+	}
+
+	x, best := [2]float64{}, math.Inf(1)
+	for i := range grid {
+		for j := range grid[i] {
+			if math.IsInf(grid[i][j], 1) || !gridLocalMin(grid, i, j) {
+				continue
+			}
+			cand := nelderMead2(sse, [2]float64{lo + step*float64(i), lo + step*float64(j)}, step)
+			if s := sse(cand); s < best {
+				x, best = cand, s
+			}
+		}
+	}
+	if math.IsInf(best, 1) {
+		return NSSParams{}, false
+	}
+
+	tau1, tau2 := math.Exp(x[0]), math.Exp(x[1])
+	beta, s, ok := nssBetas(tenors, zeros, tau1, tau2)
+	if !ok {
+		return NSSParams{}, false
+	}
+	return NSSParams{
+		Beta0: beta[0], Beta1: beta[1], Beta2: beta[2], Beta3: beta[3],
+		Tau1: tau1, Tau2: tau2,
+		RMS: math.Sqrt(s / float64(len(tenors))),
+	}, true
+}
+
+// gridLocalMin reports whether grid[i][j] is no larger than any of its neighbours.
+func gridLocalMin(grid [][]float64, i, j int) bool {
+	for di := -1; di <= 1; di++ {
+		for dj := -1; dj <= 1; dj++ {
+			ni, nj := i+di, j+dj
+			if ni >= 0 && ni < len(grid) && nj >= 0 && nj < len(grid[ni]) && grid[ni][nj] < grid[i][j] {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// nssBetas solves the linear least-squares problem for the betas at fixed taus via the
+// normal equations, returning them with the sum of squared residuals.
+func nssBetas(tenors, zeros []float64, tau1, tau2 float64) ([4]float64, float64, bool) {
+	var a [4][5]float64 // augmented XᵀX | Xᵀy
+	for i, t := range tenors {
+		f := nssLoadings(t, tau1, tau2)
+		for r := 0; r < 4; r++ {
+			for k := 0; k < 4; k++ {
+				a[r][k] += f[r] * f[k]
+			}
+			a[r][4] += f[r] * zeros[i]
+		}
+	}
+
+	// Gaussian elimination with partial pivoting.
+	for col := 0; col < 4; col++ {
+		pivot := col
+		for r := col + 1; r < 4; r++ {
+			if math.Abs(a[r][col]) > math.Abs(a[pivot][col]) {
+				pivot = r
+			}
+		}
+		if math.Abs(a[pivot][col]) < 1e-12*math.Max(1, math.Abs(a[0][0])) {
+			return [4]float64{}, 0, false
+		}
+		a[col], a[pivot] = a[pivot], a[col]
+		for r := col + 1; r < 4; r++ {
+			w := a[r][col] / a[col][col]
+			for k := col; k < 5; k++ {
+				a[r][k] -= w * a[col][k]
+			}
+		}
+	}
+	var beta [4]float64
+	for r := 3; r >= 0; r-- {
+		v := a[r][4]
+		for k := r + 1; k < 4; k++ {
+			v -= a[r][k] * beta[k]
+		}
+		beta[r] = v / a[r][r]
+	}
+
+	sse := 0.0
+	for i, t := range tenors {
+		f := nssLoadings(t, tau1, tau2)
+		res := zeros[i] - (beta[0]*f[0] + beta[1]*f[1] + beta[2]*f[2] + beta[3]*f[3])
+		sse += res * res
+	}
+	return beta, sse, true
+}
+
+// nelderMead2 minimizes f over two variables from x0 with an initial simplex of size step.
+func nelderMead2(f func([2]float64) float64, x0 [2]float64, step float64) [2]float64 {
+	pts := [3][2]float64{x0, {x0[0] + step, x0[1]}, {x0[0], x0[1] + step}}
+	val := [3]float64{f(pts[0]), f(pts[1]), f(pts[2])}
+	lerp := func(a, b [2]float64, s float64) [2]float64 {
+		return [2]float64{a[0] + s*(b[0]-a[0]), a[1] + s*(b[1]-a[1])}
+	}
+
+	for iter := 0; iter < 1000; iter++ {
+		// Order best, middle, worst.
+		for i := 1; i < 3; i++ {
+			for j := i; j > 0 && val[j] < val[j-1]; j-- {
+				pts[j], pts[j-1] = pts[j-1], pts[j]
+				val[j], val[j-1] = val[j-1], val[j]
+			}
+		}
+		if math.Abs(pts[2][0]-pts[0][0])+math.Abs(pts[2][1]-pts[0][1]) < 1e-12 {
+			break
+		}
+
+		centroid := lerp(pts[0], pts[1], 0.5)
+		reflected := lerp(centroid, pts[2], -1)
+		fr := f(reflected)
+		switch {
+		case fr < val[0]:
+			expanded := lerp(centroid, pts[2], -2)
+			if fe := f(expanded); fe < fr {
+				pts[2], val[2] = expanded, fe
+			} else {
+				pts[2], val[2] = reflected, fr
+			}
+		case fr < val[1]:
+			pts[2], val[2] = reflected, fr
+		default:
+			contracted := lerp(centroid, pts[2], 0.5)
+			if fc := f(contracted); fc < val[2] {
+				pts[2], val[2] = contracted, fc
+			} else {
+				for i := 1; i < 3; i++ {
+					pts[i] = lerp(pts[0], pts[i], 0.5)
+					val[i] = f(pts[i])
+				}
+			}
+		}
+	}
+	best := 0
+	for i := 1; i < 3; i++ {
+		if val[i] < val[best] {
+			best = i
+		}
+	}
+	return pts[best]
+}
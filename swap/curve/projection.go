@@ -12,7 +12,8 @@ import (
 //
 // For overnight indices (e.g., TONAR/ESTR/SOFR), the discount curve is also the projection curve.
 // For IBOR indices, it builds a dual curve bootstrapped using OIS discounting.
-func BuildProjectionCurve(curveDate time.Time, leg market.LegConvention, legQuotes map[string]float64, discount *Curve) *Curve {
+// Quotes are in percent unless a QuoteUnit is given.
+func BuildProjectionCurve(curveDate time.Time, leg market.LegConvention, legQuotes market.Quotes, discount *Curve, unit ...QuoteUnit) *Curve {
 	if market.IsOvernight(leg.ReferenceIndex) {
 		return discount
 	}
@@ -22,18 +23,27 @@ func BuildProjectionCurve(curveDate time.Time, leg market.LegConvention, legQuot
 	if legQuotes == nil {
 		panic(fmt.Sprintf("BuildProjectionCurve: nil quotes for %s", leg.ReferenceIndex))
 	}
-	// Use the leg's pay frequency for the floating leg periods in bootstrap,
-	// but use monthly grid for pillar interpolation (matches OIS curve precision).
-	return BuildDualCurveWithFreq(curveDate, legQuotes, discount, leg.Calendar, int(leg.PayFrequency), 1)
+	// Bootstrap on the index tenor, which is what each quoted swap's floating periods
+	// fix on (a 3M index paying semi-annually still resets quarterly), but use a monthly
+	// grid for pillar interpolation (matches OIS curve precision).
+	return BuildDualCurveWithFreq(curveDate, legQuotes, discount, leg.Calendar, projectionTenorMonths(leg), 1, unit...)
+}
+
+// projectionTenorMonths returns the floating period length, in months, used to bootstrap
+// a projection curve for leg: the reference index tenor (see market.IndexTenorMonths),
+// falling back to the pay frequency for an index that does not encode one.
+func projectionTenorMonths(leg market.LegConvention) int {
+	if months, ok := market.IndexTenorMonths(leg.ReferenceIndex); ok && months > 0 {
+		return months
+	}
+	return int(leg.PayFrequency)
 }
 
 // BuildDualCurveWithFreq creates an IBOR projection curve with separate control over
 // the floating leg frequency (for bootstrap) and the pillar grid frequency (for interpolation).
-func BuildDualCurveWithFreq(settlement time.Time, iborQuotes map[string]float64, oisCurve *Curve, cal calendar.CalendarID, floatFreqMonths, gridFreqMonths int) *Curve {
-	parsed := make(map[float64]float64)
-	for k, v := range iborQuotes {
-		parsed[tenorToYears(k)] = v
-	}
+// Quotes are in percent unless a QuoteUnit is given.
+func BuildDualCurveWithFreq(settlement time.Time, iborQuotes market.Quotes, oisCurve *Curve, cal calendar.CalendarID, floatFreqMonths, gridFreqMonths int, unit ...QuoteUnit) *Curve {
+	parsed := parseQuotes(iborQuotes, unit)
 	c := &Curve{
 		settlement:    settlement,
 		parQuotes:     parsed,
@@ -44,6 +54,8 @@ func BuildDualCurveWithFreq(settlement time.Time, iborQuotes map[string]float64,
 	c.paymentDates = c.generatePaymentDates()
 	c.parRates = c.buildParCurve()
 	c.discountFactors = c.bootstrapDualCurve(oisCurve, floatFreqMonths)
+	c.anchorSettlement()
 	c.zeros = c.buildZero()
+	c.enableDFCache()
 	return c
 }
@@ -0,0 +1,134 @@
+package curve
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/meenmo/molib/calendar"
+	"github.com/meenmo/molib/swap/market"
+	"github.com/meenmo/molib/utils"
+)
+
+// TurnAdjustment is a discrete jump in the overnight forward curve, such as a year-end
+// turn or a central bank meeting date.
+type TurnAdjustment struct {
+	// Date is the turn date. The jump applies over the curve grid period containing it:
+	// from the last grid date on or before Date to the next one.
+	Date time.Time
+	// SizeBP is the rise, in bp, of the continuously compounded forward over that period.
+	SizeBP float64
+}
+
+// turnJump is a TurnAdjustment resolved onto the curve grid.
+type turnJump struct {
+	start, end time.Time
+	jump       float64 // SizeBP × period length, in curve time
+}
+
+// BuildCurveWithTurns is BuildCurve with forward jumps at the given turns. Log-linear
+// interpolation spreads a step in the forward curve across the whole pillar segment it
+// falls in; here each turn's jump is confined to the grid period containing it, so with a
+// grid matching the floating period (freqMonths) GetForwardRates shows the spike only in
+// the period straddling the turn.
+//
+// The curve is a smooth bootstrapped curve times the turn DF factors. The smooth curve's
+// par quotes are corrected until the turned curve reprices the input quotes, so the rest
+// of the pillar segment holding a turn comes down to pay for it. It panics if a turn is
+// not between settlement and the last grid date, or if the quote correction does not
+// converge (e.g. turns too large for the quotes to absorb).
+func BuildCurveWithTurns(settlement time.Time, quotes market.Quotes, cal calendar.CalendarID, freqMonths int, turns []TurnAdjustment, unit ...QuoteUnit) *Curve {
+	parsed := parseQuotes(quotes, unit)
+	c := &Curve{
+		settlement:    settlement,
+		parQuotes:     parsed,
+		cal:           cal,
+		freqMonths:    freqMonths,
+		curveDayCount: defaultCurveDayCount(cal),
+		fixedLegDC:    FixedLegDayCountOIS,
+	}
+	c.paymentDates = c.generatePaymentDates()
+	jumps := c.resolveTurns(turns)
+
+	pillars := make(map[float64]time.Time, len(parsed))
+	for d, tenor := range c.paymentDatesToTenor() {
+		if _, ok := parsed[tenor]; ok {
+			pillars[tenor] = d
+		}
+	}
+	smooth := make(map[float64]float64, len(parsed))
+	for tenor, rate := range parsed {
+		smooth[tenor] = rate
+	}
+	const maxTurnIterations = 50
+	converged := false
+	for iter := 0; iter < maxTurnIterations && !converged; iter++ {
+		c.parQuotes = smooth
+		c.parRates = c.buildParCurve()
+		c.discountFactors = c.bootstrapDiscountFactors()
+		c.anchorSettlement()
+		c.applyTurns(jumps)
+
+		// Reprice each quote on the turned curve, as the bootstrap does: 1 = Σ α·par·DF + DF.
+		moved := 0.0
+		next := make(map[float64]float64, len(smooth))
+		for tenor, quote := range parsed {
+			annuity := 0.0
+			for _, cpn := range c.buildOISCoupons(pillars[tenor]) {
+				annuity += cpn.Accrual * c.DF(cpn.PaymentDate)
+			}
+			miss := quote - (1-c.DF(pillars[tenor]))/annuity*100
+			next[tenor] = smooth[tenor] + miss
+			moved = math.Max(moved, math.Abs(miss))
+		}
+		// Grid DFs are rounded to 12 digits, which floors the miss near 1e-11%.
+		converged = moved < 1e-9
+		smooth = next
+	}
+	if !converged {
+		panic(fmt.Sprintf("curve: turned curve does not reprice the quotes after %d iterations", maxTurnIterations))
+	}
+	c.parQuotes = parsed
+	c.zeros = c.buildZero()
+	c.enableDFCache()
+	return c
+}
+
+// resolveTurns maps each turn onto the grid period [d_i, d_i+1) containing its date.
+func (c *Curve) resolveTurns(turns []TurnAdjustment) []turnJump {
+	jumps := make([]turnJump, 0, len(turns))
+	for _, turn := range turns {
+		i, exact := utils.SearchDates(c.paymentDates, turn.Date)
+		if !exact {
+			i--
+		}
+		if turn.Date.Before(c.settlement) || i < 0 || i >= len(c.paymentDates)-1 {
+			panic(fmt.Sprintf("curve: turn %s outside curve grid %s to %s", turn.Date.Format("2006-01-02"),
+				c.settlement.Format("2006-01-02"), c.paymentDates[len(c.paymentDates)-1].Format("2006-01-02")))
+		}
+		start, end := c.paymentDates[i], c.paymentDates[i+1]
+		jumps = append(jumps, turnJump{
+			start: start,
+			end:   end,
+			jump:  turn.SizeBP / 10000.0 * utils.YearFraction(start, end, c.curveDayCount),
+		})
+	}
+	return jumps
+}
+
+// applyTurns multiplies the grid DFs by the turn factors: exp(−jump) on and after the end
+// of a turn's period. Grid DFs are log-linear between nodes, so the jump is spread evenly
+// across its period and nowhere else.
+func (c *Curve) applyTurns(jumps []turnJump) {
+	for d, v := range c.discountFactors {
+		exponent := 0.0
+		for _, tj := range jumps {
+			if !d.Before(tj.end) {
+				exponent += tj.jump
+			}
+		}
+		if exponent != 0 {
+			c.discountFactors[d] = utils.RoundTo(v*math.Exp(-exponent), 12)
+		}
+	}
+}
@@ -1,8 +1,9 @@
 package curve
 
 import (
-	"sort"
 	"time"
+
+	"github.com/meenmo/molib/utils"
 )
 
 // findBracket finds two adjacent dates in a sorted slice that bracket the target.
@@ -14,15 +15,12 @@ func findBracket(dates []time.Time, target time.Time) (d1, d2 time.Time, found b
 		return time.Time{}, time.Time{}, false
 	}
 
-	// Binary search for first date >= target
-	idx := sort.Search(len(dates), func(i int) bool {
-		return !dates[i].Before(target)
-	})
+	idx, exact := utils.SearchDates(dates, target)
 
 	// Handle boundary cases
 	if idx == 0 {
 		// target is before or equal to first date
-		if dates[0].Equal(target) && len(dates) > 1 {
+		if exact {
 			return dates[0], dates[1], true
 		}
 		return time.Time{}, time.Time{}, false
@@ -46,10 +44,7 @@ func findBracketOrBoundary(dates []time.Time, target time.Time) (d1, d2 time.Tim
 		panic("findBracketOrBoundary: need at least 2 dates")
 	}
 
-	// Binary search for first date >= target
-	idx := sort.Search(len(dates), func(i int) bool {
-		return !dates[i].Before(target)
-	})
+	idx, _ := utils.SearchDates(dates, target)
 
 	// Handle boundary cases
 	if idx <= 0 {
@@ -66,14 +61,6 @@ func findBracketOrBoundary(dates []time.Time, target time.Time) (d1, d2 time.Tim
 	return dates[idx-1], dates[idx]
 }
 
-// binarySearchDate finds the index of the first date >= target using binary search.
-// Returns len(dates) if all dates are before target.
-func binarySearchDate(dates []time.Time, target time.Time) int {
-	return sort.Search(len(dates), func(i int) bool {
-		return !dates[i].Before(target)
-	})
-}
-
 // findExactOrBracket searches for an exact match first, then falls back to bracket.
 // Returns (date, true, -1, time.Time{}) if exact match found at returned date.
 // Returns (time.Time{}, false, idx1, d2) if bracket found.
@@ -83,10 +70,10 @@ func findExactOrBracket(dates []time.Time, target time.Time) (exact time.Time, i
 		return time.Time{}, false, -1, time.Time{}
 	}
 
-	idx := binarySearchDate(dates, target)
+	idx, found := utils.SearchDates(dates, target)
 
 	// Check for exact match
-	if idx < len(dates) && dates[idx].Equal(target) {
+	if found {
 		return dates[idx], true, -1, time.Time{}
 	}
 
@@ -0,0 +1,71 @@
+package swap
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/meenmo/molib/calendar"
+	"github.com/meenmo/molib/swap/market"
+	"github.com/meenmo/molib/utils"
+)
+
+// ScheduleDiagnostic returns the per-period schedule, forwards and payment discount
+// factors for a floating leg, using the same schedule and forward logic as NPV.
+//
+// It is intended for reconciliation scripts (e.g. against Bloomberg SWPM cashflow
+// tables) so they do not need local copies of the schedule or forward code.
+func ScheduleDiagnostic(projCurve ProjectionCurve, discCurve DiscountCurve, effective, maturity time.Time, leg market.LegConvention) ([]PeriodDiagnostic, error) {
+	if isNilInterface(projCurve) || isNilInterface(discCurve) {
+		return nil, ErrNilCurve
+	}
+	if leg.LegType != market.LegFloating {
+		return nil, fmt.Errorf("ScheduleDiagnostic: leg must be floating, got %s", leg.LegType)
+	}
+
+	periods, err := GenerateSchedule(effective, maturity, leg)
+	if err != nil {
+		return nil, err
+	}
+
+	dayCount := string(leg.DayCount)
+	out := make([]PeriodDiagnostic, 0, len(periods))
+	for _, p := range periods {
+		fwd := periodForward(projCurve, leg, p)
+		if market.IsOvernight(leg.ReferenceIndex) && leg.ResetPosition == market.ResetInArrears {
+			if fwd, err = ProjectedOvernightRate(projCurve, leg, p.StartDate, p.EndDate); err != nil {
+				return nil, err
+			}
+		}
+		tenorEnd, tenorFwd := p.EndDate, fwd
+		if months := indexTenorMonths(leg); months > 0 {
+			tenorEnd = calendar.Adjust(leg.Calendar, utils.AddMonth(p.StartDate, months))
+			tenorFwd = forwardRate(projCurve, p.StartDate, tenorEnd, dayCount)
+		}
+		out = append(out, PeriodDiagnostic{
+			SchedulePeriod: p,
+			Accrual:        utils.YearFraction(p.StartDate, p.EndDate, dayCount),
+			Forward:        fwd,
+			TenorEndDate:   tenorEnd,
+			TenorForward:   tenorFwd,
+			PayDF:          discCurve.DF(p.PayDate),
+		})
+	}
+	return out, nil
+}
+
+// TenorForward returns the simple forward (decimal) for an IBOR fixing starting at
+// start and running for the index tenor (see market.IndexTenorMonths), with the end
+// date adjusted on the leg calendar. This is the fixing a stub period would see.
+func TenorForward(projCurve ProjectionCurve, start time.Time, leg market.LegConvention) float64 {
+	end := calendar.Adjust(leg.Calendar, utils.AddMonth(start, indexTenorMonths(leg)))
+	return forwardRate(projCurve, start, end, string(leg.DayCount))
+}
+
+// indexTenorMonths returns the tenor of the leg's reference index, falling back to the
+// reset frequency for indices that do not encode one. Overnight legs return 0.
+func indexTenorMonths(leg market.LegConvention) int {
+	if months, ok := market.IndexTenorMonths(leg.ReferenceIndex); ok {
+		return months
+	}
+	return int(leg.ResetFrequency)
+}
@@ -0,0 +1,92 @@
+package swap_test
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/meenmo/molib/calendar"
+	"github.com/meenmo/molib/instruments/swaps"
+	"github.com/meenmo/molib/swap"
+	"github.com/meenmo/molib/swap/curve"
+	"github.com/meenmo/molib/swap/market"
+	"github.com/meenmo/molib/utils"
+)
+
+// forwardRateLocal is the DF-ratio forward the EUR reconciliation scripts used to
+// carry locally; ScheduleDiagnostic must reproduce it.
+func forwardRateLocal(c swap.ProjectionCurve, start, end time.Time, dayCount string) float64 {
+	return (c.DF(start)/c.DF(end) - 1.0) / utils.YearFraction(start, end, dayCount)
+}
+
+func TestScheduleDiagnostic_MatchesLocalForwardsAndNPV(t *testing.T) {
+	t.Parallel()
+
+	effective := time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC)
+	maturity := time.Date(2026, 4, 2, 0, 0, 0, 0, time.UTC)
+	notional := 1_000_000.0
+
+	disc := curve.NewCurveFromDFs(effective, map[time.Time]float64{
+		effective: 1.0,
+		time.Date(2025, 7, 2, 0, 0, 0, 0, time.UTC): 0.985,
+		time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC): 0.968,
+		time.Date(2026, 7, 2, 0, 0, 0, 0, time.UTC): 0.950,
+	}, calendar.TARGET, 0)
+
+	floatLeg := swaps.EURIBOR6MFloating
+	floatLeg.IncludeInitialPrincipal = false
+	floatLeg.IncludeFinalPrincipal = false
+
+	diag, err := swap.ScheduleDiagnostic(disc, disc, effective, maturity, floatLeg)
+	if err != nil {
+		t.Fatalf("ScheduleDiagnostic: %v", err)
+	}
+	if len(diag) != 3 {
+		t.Fatalf("expected front stub plus two 6M periods, got %d periods", len(diag))
+	}
+
+	pv := 0.0
+	for i, d := range diag {
+		want := forwardRateLocal(disc, d.StartDate, d.EndDate, "ACT/360")
+		if math.Abs(d.Forward-want) > 1e-14 {
+			t.Fatalf("period %d forward %.12f, local %.12f", i, d.Forward, want)
+		}
+		wantTenor := forwardRateLocal(disc, d.StartDate, d.TenorEndDate, "ACT/360")
+		if math.Abs(d.TenorForward-wantTenor) > 1e-14 {
+			t.Fatalf("period %d tenor forward %.12f, local %.12f", i, d.TenorForward, wantTenor)
+		}
+		pv += notional * d.Accrual * d.Forward * d.PayDF
+	}
+
+	// The 3M front stub fixes on a 6M tenor, the regular periods on their own accrual.
+	if diag[0].TenorEndDate.Equal(diag[0].EndDate) {
+		t.Fatalf("expected stub tenor end beyond period end %s", diag[0].EndDate.Format("2006-01-02"))
+	}
+	if !diag[1].TenorEndDate.Equal(diag[1].EndDate) {
+		t.Fatalf("regular period tenor end %s, period end %s",
+			diag[1].TenorEndDate.Format("2006-01-02"), diag[1].EndDate.Format("2006-01-02"))
+	}
+	if got := swap.TenorForward(disc, diag[0].StartDate, floatLeg); got != diag[0].TenorForward {
+		t.Fatalf("TenorForward %.12f, diagnostic %.12f", got, diag[0].TenorForward)
+	}
+
+	zeroFixed := swaps.EURIBORFixed
+	spec := market.SwapSpec{
+		Notional:      notional,
+		EffectiveDate: effective,
+		MaturityDate:  maturity,
+		PayLeg:        zeroFixed,
+		RecLeg:        floatLeg,
+	}
+	npv, err := swap.NPV(spec, nil, disc, disc, effective)
+	if err != nil {
+		t.Fatalf("NPV: %v", err)
+	}
+	if math.Abs(npv-pv) > 1e-6 {
+		t.Fatalf("diagnostic coupon PV %.6f, NPV %.6f", pv, npv)
+	}
+
+	if _, err := swap.ScheduleDiagnostic(disc, disc, effective, maturity, zeroFixed); err == nil {
+		t.Fatalf("expected error for fixed leg")
+	}
+}
@@ -0,0 +1,367 @@
+package swap
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/meenmo/molib/calendar"
+	"github.com/meenmo/molib/swap/market"
+)
+
+// Stream directions in a TradeDocument, from the holder's perspective.
+const (
+	StreamPay     = "PAY"
+	StreamReceive = "RECEIVE"
+)
+
+// TradeDocument is the structured trade representation written by MarshalTrade. Its
+// layout loosely follows FpML's swap / swapStream (interestRateStream) so it can be
+// mapped onto trade-capture systems; it is party-agnostic, so each stream records a
+// direction instead of payer/receiver party references.
+type TradeDocument struct {
+	TradeDate       string               `json:"tradeDate"`
+	ValuationDate   string               `json:"valuationDate,omitempty"`
+	EffectiveDate   string               `json:"effectiveDate"`
+	TerminationDate string               `json:"terminationDate"`
+	ClearingHouse   ClearingHouse        `json:"clearingHouse,omitempty"`
+	DataSource      DataSource           `json:"dataSource,omitempty"`
+	Discounting     StreamTerms          `json:"discounting"`
+	Streams         []InterestRateStream `json:"swapStream"`
+
+	// FirstFloatingFixing is the contractual rate, as a decimal, of each floating
+	// stream's first period (SwapSpec.FirstFloatingFixing). Unlike the streams'
+	// initialRate it is not in percent.
+	FirstFloatingFixing *float64 `json:"firstFloatingFixing,omitempty"`
+
+	// InitialRateOnCurrentPeriod applies the streams' initialRate to the first period not
+	// yet paid instead of the first period (SwapSpec.FirstResetOnCurrentPeriod).
+	InitialRateOnCurrentPeriod bool `json:"initialRateOnCurrentPeriod,omitempty"`
+}
+
+// InterestRateStream describes one leg: its economics, conventions and generated schedule.
+type InterestRateStream struct {
+	Direction string  `json:"direction"` // StreamPay or StreamReceive
+	Notional  float64 `json:"notional"`
+
+	// NotionalSchedule lists amortization or accretion steps (SwapSpec.NotionalSchedule).
+	NotionalSchedule []StreamNotionalStep `json:"notionalStepSchedule,omitempty"`
+
+	// FixedRatePct is set for fixed streams; SpreadBP for floating streams.
+	FixedRatePct  *float64 `json:"fixedRate,omitempty"`
+	SpreadBP      *float64 `json:"spread,omitempty"`
+	FirstResetPct *float64 `json:"initialRate,omitempty"` // observed fixing for the first period, in percent
+
+	Terms   StreamTerms    `json:"calculationPeriodAmount"`
+	Periods []StreamPeriod `json:"paymentCalculationPeriods"`
+}
+
+// StreamTerms carries a leg's conventions with FpML-style field names.
+type StreamTerms struct {
+	LegType               market.LegType               `json:"legType"`
+	FloatingRateIndex     market.ReferenceIndex        `json:"floatingRateIndex,omitempty"`
+	DayCountFraction      market.DayCount              `json:"dayCountFraction"`
+	ResetFrequencyMonths  market.Frequency             `json:"resetFrequencyMonths"`
+	PaymentFrequency      market.Frequency             `json:"paymentFrequencyMonths"`
+	FixingLagDays         int                          `json:"fixingDateOffsetDays"`
+	PaymentDelayDays      int                          `json:"paymentDaysOffset"`
+	BusinessDayConvention market.BusinessDayAdjustment `json:"businessDayConvention,omitempty"`
+	RollConvention        market.RollConvention        `json:"rollConvention,omitempty"`
+	EndOfMonth            bool                         `json:"endOfMonth,omitempty"`
+	BusinessCenters       calendar.CalendarID          `json:"businessCenters"`
+	FixingBusinessCenters calendar.CalendarID          `json:"fixingBusinessCenters,omitempty"`
+	ResetPosition         market.ResetPosition         `json:"resetRelativeTo,omitempty"`
+	RateCutoffDays        int                          `json:"rateCutOffDaysOffset,omitempty"`
+	InitialExchange       bool                         `json:"initialExchange"`
+	FinalExchange         bool                         `json:"finalExchange"`
+	DelayFinalExchange    bool                         `json:"finalExchangeOnPaymentDate,omitempty"`
+	ScheduleDirection     market.ScheduleDirection     `json:"scheduleDirection,omitempty"`
+	StubConvention        market.StubConvention        `json:"stubConvention,omitempty"`
+	RoundCoupons          bool                         `json:"roundCoupons,omitempty"`
+	ForwardFromFixingDate bool                         `json:"forwardFromFixingDate,omitempty"`
+	ConvexityVol          float64                      `json:"convexityVolatility,omitempty"`
+	LookbackDays          int                          `json:"lookbackDays,omitempty"`
+	ObservationShift      bool                         `json:"observationShift,omitempty"`
+	Gearing               float64                      `json:"gearing,omitempty"`
+	CouponFloor           *float64                     `json:"floorRate,omitempty"`
+	CouponCap             *float64                     `json:"capRate,omitempty"`
+	ClampBeforeSpread     bool                         `json:"capFloorBeforeSpread,omitempty"`
+	SpreadSchedule        map[string]float64           `json:"spreadSchedule,omitempty"` // YYYY-MM-DD -> bp
+}
+
+// StreamNotionalStep is one notional step, its date as YYYY-MM-DD.
+type StreamNotionalStep struct {
+	StepDate string  `json:"stepDate"`
+	Notional float64 `json:"notional"`
+}
+
+// StreamPeriod is one generated calculation period, dates as YYYY-MM-DD.
+type StreamPeriod struct {
+	AdjustedStartDate   string `json:"adjustedStartDate"`
+	AdjustedEndDate     string `json:"adjustedEndDate"`
+	AdjustedPaymentDate string `json:"adjustedPaymentDate"`
+	FixingDate          string `json:"fixingDate,omitempty"`
+}
+
+// MarshalTrade writes the trade as an indented JSON TradeDocument. The schedule is
+// informational: UnmarshalTrade regenerates it from the conventions.
+func MarshalTrade(t *SwapTrade) ([]byte, error) {
+	if t == nil {
+		return nil, fmt.Errorf("MarshalTrade: trade is nil")
+	}
+	spec := t.Spec
+	if err := validateSwapSpec(spec); err != nil {
+		return nil, fmt.Errorf("MarshalTrade: %w", err)
+	}
+
+	pay, err := newStream(StreamPay, spec, spec.PayLeg, spec.PayLegSpreadBP, spec.PayLegFirstResetPct)
+	if err != nil {
+		return nil, fmt.Errorf("MarshalTrade: pay leg: %w", err)
+	}
+	rec, err := newStream(StreamReceive, spec, spec.RecLeg, spec.RecLegSpreadBP, spec.RecLegFirstResetPct)
+	if err != nil {
+		return nil, fmt.Errorf("MarshalTrade: receive leg: %w", err)
+	}
+
+	doc := TradeDocument{
+		TradeDate:       formatDate(t.TradeDate),
+		ValuationDate:   formatDate(t.ValuationDate),
+		EffectiveDate:   formatDate(spec.EffectiveDate),
+		TerminationDate: formatDate(spec.MaturityDate),
+		ClearingHouse:   t.ClearingHouse,
+		DataSource:      t.DataSource,
+		Discounting:     termsFromLeg(spec.DiscountingOIS),
+		Streams:         []InterestRateStream{pay, rec},
+
+		FirstFloatingFixing:        spec.FirstFloatingFixing,
+		InitialRateOnCurrentPeriod: spec.FirstResetOnCurrentPeriod,
+	}
+	return json.MarshalIndent(doc, "", "  ")
+}
+
+// UnmarshalTrade parses a TradeDocument written by MarshalTrade and returns the swap spec,
+// ready to price with NPV against the caller's curves.
+func UnmarshalTrade(data []byte) (market.SwapSpec, error) {
+	var doc TradeDocument
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return market.SwapSpec{}, fmt.Errorf("UnmarshalTrade: %w", err)
+	}
+
+	effective, err := time.Parse("2006-01-02", doc.EffectiveDate)
+	if err != nil {
+		return market.SwapSpec{}, fmt.Errorf("UnmarshalTrade: effectiveDate: %w", err)
+	}
+	maturity, err := time.Parse("2006-01-02", doc.TerminationDate)
+	if err != nil {
+		return market.SwapSpec{}, fmt.Errorf("UnmarshalTrade: terminationDate: %w", err)
+	}
+
+	discounting, err := doc.Discounting.leg()
+	if err != nil {
+		return market.SwapSpec{}, fmt.Errorf("UnmarshalTrade: discounting: %w", err)
+	}
+	spec := market.SwapSpec{
+		EffectiveDate:             effective,
+		MaturityDate:              maturity,
+		DiscountingOIS:            discounting,
+		FirstFloatingFixing:       doc.FirstFloatingFixing,
+		FirstResetOnCurrentPeriod: doc.InitialRateOnCurrentPeriod,
+	}
+	var seenPay, seenRec bool
+	for _, s := range doc.Streams {
+		leg, err := s.Terms.leg()
+		if err != nil {
+			return market.SwapSpec{}, fmt.Errorf("UnmarshalTrade: %s stream: %w", s.Direction, err)
+		}
+		spread := 0.0
+		switch {
+		case s.FixedRatePct != nil:
+			spread = *s.FixedRatePct * 100
+		case s.SpreadBP != nil:
+			spread = *s.SpreadBP
+		}
+
+		switch s.Direction {
+		case StreamPay:
+			if seenPay {
+				return market.SwapSpec{}, fmt.Errorf("UnmarshalTrade: duplicate %s stream", StreamPay)
+			}
+			seenPay = true
+			spec.PayLeg = leg
+			spec.PayLegSpreadBP = spread
+			spec.PayLegFirstResetPct = s.FirstResetPct
+		case StreamReceive:
+			if seenRec {
+				return market.SwapSpec{}, fmt.Errorf("UnmarshalTrade: duplicate %s stream", StreamReceive)
+			}
+			seenRec = true
+			spec.RecLeg = leg
+			spec.RecLegSpreadBP = spread
+			spec.RecLegFirstResetPct = s.FirstResetPct
+		default:
+			return market.SwapSpec{}, fmt.Errorf("UnmarshalTrade: unknown stream direction %q", s.Direction)
+		}
+
+		steps, err := s.notionalSteps()
+		if err != nil {
+			return market.SwapSpec{}, fmt.Errorf("UnmarshalTrade: %s stream: %w", s.Direction, err)
+		}
+		if spec.Notional == 0 && spec.NotionalSchedule == nil {
+			spec.Notional = s.Notional
+			spec.NotionalSchedule = steps
+		} else if s.Notional != spec.Notional || !slices.Equal(steps, spec.NotionalSchedule) {
+			return market.SwapSpec{}, fmt.Errorf("UnmarshalTrade: stream notionals differ")
+		}
+	}
+	if !seenPay || !seenRec {
+		return market.SwapSpec{}, fmt.Errorf("UnmarshalTrade: need one %s and one %s stream", StreamPay, StreamReceive)
+	}
+	if err := validateSwapSpec(spec); err != nil {
+		return market.SwapSpec{}, fmt.Errorf("UnmarshalTrade: %w", err)
+	}
+	return spec, nil
+}
+
+func newStream(direction string, spec market.SwapSpec, leg market.LegConvention, spreadBP float64, firstReset *float64) (InterestRateStream, error) {
+	periods, err := GenerateSchedule(spec.EffectiveDate, spec.MaturityDate, leg)
+	if err != nil {
+		return InterestRateStream{}, err
+	}
+
+	s := InterestRateStream{
+		Direction: direction,
+		Notional:  spec.Notional,
+		Terms:     termsFromLeg(leg),
+		Periods:   make([]StreamPeriod, 0, len(periods)),
+	}
+	for _, st := range spec.NotionalSchedule {
+		s.NotionalSchedule = append(s.NotionalSchedule, StreamNotionalStep{StepDate: formatDate(st.EffectiveDate), Notional: st.Notional})
+	}
+	// For fixed legs the spread is the coupon in bp.
+	if leg.LegType == market.LegFixed {
+		rate := spreadBP / 100
+		s.FixedRatePct = &rate
+	} else {
+		s.SpreadBP = &spreadBP
+		s.FirstResetPct = firstReset
+	}
+
+	for _, p := range periods {
+		sp := StreamPeriod{
+			AdjustedStartDate:   formatDate(p.StartDate),
+			AdjustedEndDate:     formatDate(p.EndDate),
+			AdjustedPaymentDate: formatDate(p.PayDate),
+		}
+		if leg.LegType == market.LegFloating {
+			sp.FixingDate = formatDate(p.FixingDate)
+		}
+		s.Periods = append(s.Periods, sp)
+	}
+	return s, nil
+}
+
+func (s InterestRateStream) notionalSteps() ([]market.NotionalStep, error) {
+	var steps []market.NotionalStep
+	for _, st := range s.NotionalSchedule {
+		d, err := time.Parse("2006-01-02", st.StepDate)
+		if err != nil {
+			return nil, fmt.Errorf("notionalStepSchedule: %w", err)
+		}
+		steps = append(steps, market.NotionalStep{EffectiveDate: d, Notional: st.Notional})
+	}
+	return steps, nil
+}
+
+func termsFromLeg(leg market.LegConvention) StreamTerms {
+	var spreadSchedule map[string]float64
+	if len(leg.SpreadSchedule) > 0 {
+		spreadSchedule = make(map[string]float64, len(leg.SpreadSchedule))
+		for d, bp := range leg.SpreadSchedule {
+			spreadSchedule[formatDate(d)] = bp
+		}
+	}
+	return StreamTerms{
+		LegType:               leg.LegType,
+		FloatingRateIndex:     leg.ReferenceIndex,
+		DayCountFraction:      leg.DayCount,
+		ResetFrequencyMonths:  leg.ResetFrequency,
+		PaymentFrequency:      leg.PayFrequency,
+		FixingLagDays:         leg.FixingLagDays,
+		PaymentDelayDays:      leg.PayDelayDays,
+		BusinessDayConvention: leg.BusinessDayAdjustment,
+		RollConvention:        leg.RollConvention,
+		EndOfMonth:            leg.EndOfMonth,
+		BusinessCenters:       leg.Calendar,
+		FixingBusinessCenters: leg.FixingCalendar,
+		ResetPosition:         leg.ResetPosition,
+		RateCutoffDays:        leg.RateCutoffDays,
+		InitialExchange:       leg.IncludeInitialPrincipal,
+		FinalExchange:         leg.IncludeFinalPrincipal,
+		DelayFinalExchange:    leg.DelayFinalPrincipal,
+		ScheduleDirection:     leg.ScheduleDirection,
+		StubConvention:        leg.StubConvention,
+		RoundCoupons:          leg.RoundCoupons,
+		ForwardFromFixingDate: leg.ForwardFromFixingDate,
+		ConvexityVol:          leg.ConvexityVol,
+		LookbackDays:          leg.LookbackDays,
+		ObservationShift:      leg.ObservationShift,
+		Gearing:               leg.Gearing,
+		CouponFloor:           leg.CouponFloor,
+		CouponCap:             leg.CouponCap,
+		ClampBeforeSpread:     leg.ClampBeforeSpread,
+		SpreadSchedule:        spreadSchedule,
+	}
+}
+
+func (s StreamTerms) leg() (market.LegConvention, error) {
+	var spreadSchedule map[time.Time]float64
+	if len(s.SpreadSchedule) > 0 {
+		spreadSchedule = make(map[time.Time]float64, len(s.SpreadSchedule))
+		for d, bp := range s.SpreadSchedule {
+			t, err := time.Parse("2006-01-02", d)
+			if err != nil {
+				return market.LegConvention{}, fmt.Errorf("spreadSchedule: %w", err)
+			}
+			spreadSchedule[t] = bp
+		}
+	}
+	return market.LegConvention{
+		LegType:                 s.LegType,
+		ReferenceIndex:          s.FloatingRateIndex,
+		DayCount:                s.DayCountFraction,
+		ResetFrequency:          s.ResetFrequencyMonths,
+		PayFrequency:            s.PaymentFrequency,
+		FixingLagDays:           s.FixingLagDays,
+		PayDelayDays:            s.PaymentDelayDays,
+		BusinessDayAdjustment:   s.BusinessDayConvention,
+		RollConvention:          s.RollConvention,
+		EndOfMonth:              s.EndOfMonth,
+		Calendar:                s.BusinessCenters,
+		FixingCalendar:          s.FixingBusinessCenters,
+		ResetPosition:           s.ResetPosition,
+		RateCutoffDays:          s.RateCutoffDays,
+		IncludeInitialPrincipal: s.InitialExchange,
+		IncludeFinalPrincipal:   s.FinalExchange,
+		DelayFinalPrincipal:     s.DelayFinalExchange,
+		ScheduleDirection:       s.ScheduleDirection,
+		StubConvention:          s.StubConvention,
+		RoundCoupons:            s.RoundCoupons,
+		ForwardFromFixingDate:   s.ForwardFromFixingDate,
+		ConvexityVol:            s.ConvexityVol,
+		LookbackDays:            s.LookbackDays,
+		ObservationShift:        s.ObservationShift,
+		Gearing:                 s.Gearing,
+		CouponFloor:             s.CouponFloor,
+		CouponCap:               s.CouponCap,
+		ClampBeforeSpread:       s.ClampBeforeSpread,
+		SpreadSchedule:          spreadSchedule,
+	}, nil
+}
+
+func formatDate(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format("2006-01-02")
+}
@@ -0,0 +1,139 @@
+package swap_test
+
+import (
+	"encoding/json"
+	"math"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/meenmo/molib/instruments/swaps"
+	"github.com/meenmo/molib/swap"
+	"github.com/meenmo/molib/swap/market"
+)
+
+func TestMarshalTrade_RoundTrip(t *testing.T) {
+	t.Parallel()
+
+	curveDate := time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)
+	estrQuotes := map[string]float64{
+		"1Y": 2.06795, "2Y": 2.153975, "3Y": 2.24, "5Y": 2.3495, "7Y": 2.484, "10Y": 2.6955,
+	}
+	euriborQuotes := map[string]float64{
+		"1Y": 2.25, "2Y": 2.35, "3Y": 2.44, "5Y": 2.56, "7Y": 2.70, "10Y": 2.90,
+	}
+	firstReset := 2.145
+
+	trade, err := swap.InterestRateSwap(swap.InterestRateSwapParams{
+		DataSource:          swap.DataSourceBGN,
+		ClearingHouse:       swap.ClearingHouseLCH,
+		CurveDate:           curveDate,
+		TradeDate:           curveDate,
+		SwapTenorYears:      5,
+		Notional:            10_000_000,
+		PayLeg:              swaps.EURIBORFixed,
+		RecLeg:              swaps.EURIBOR6MFloating,
+		DiscountingOIS:      swaps.ESTRFloating,
+		OISQuotes:           estrQuotes,
+		RecLegQuotes:        euriborQuotes,
+		PayLegSpreadBP:      255,
+		RecLegSpreadBP:      3,
+		RecLegFirstResetPct: &firstReset,
+	})
+	if err != nil {
+		t.Fatalf("InterestRateSwap: %v", err)
+	}
+
+	data, err := swap.MarshalTrade(trade)
+	if err != nil {
+		t.Fatalf("MarshalTrade: %v", err)
+	}
+
+	var doc swap.TradeDocument
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("decode document: %v", err)
+	}
+	if len(doc.Streams) != 2 {
+		t.Fatalf("got %d streams, want 2", len(doc.Streams))
+	}
+	fixed := doc.Streams[0]
+	if fixed.Direction != swap.StreamPay || fixed.FixedRatePct == nil || *fixed.FixedRatePct != 2.55 {
+		t.Fatalf("pay stream = %+v, want fixed 2.55%%", fixed)
+	}
+	if len(fixed.Periods) != 5 {
+		t.Fatalf("fixed stream has %d periods, want 5", len(fixed.Periods))
+	}
+	float := doc.Streams[1]
+	if float.SpreadBP == nil || *float.SpreadBP != 3 || len(float.Periods) != 10 || float.Periods[0].FixingDate == "" {
+		t.Fatalf("receive stream = %+v, want 3bp spread over 10 fixed periods", float)
+	}
+
+	spec, err := swap.UnmarshalTrade(data)
+	if err != nil {
+		t.Fatalf("UnmarshalTrade: %v", err)
+	}
+	// The fixed coupon is written in percent, so allow float noise on the way back to bp.
+	if math.Abs(spec.PayLegSpreadBP-trade.Spec.PayLegSpreadBP) > 1e-9 {
+		t.Fatalf("round-trip fixed coupon=%.12f bp, want %.12f", spec.PayLegSpreadBP, trade.Spec.PayLegSpreadBP)
+	}
+	spec.PayLegSpreadBP = trade.Spec.PayLegSpreadBP
+	if !reflect.DeepEqual(spec, trade.Spec) {
+		t.Fatalf("round-trip spec mismatch:\n got %+v\nwant %+v", spec, trade.Spec)
+	}
+
+	want, err := trade.NPV()
+	if err != nil {
+		t.Fatalf("NPV: %v", err)
+	}
+	got, err := swap.NPV(spec, trade.PayProjCurve, trade.RecProjCurve, trade.DiscountCurve, trade.ValuationDate)
+	if err != nil {
+		t.Fatalf("NPV of round-tripped spec: %v", err)
+	}
+	if got != want {
+		t.Fatalf("round-tripped NPV=%.6f, want %.6f", got, want)
+	}
+
+	// Notional steps travel on each stream.
+	trade.Spec.NotionalSchedule = []market.NotionalStep{
+		{EffectiveDate: time.Date(2028, 3, 13, 0, 0, 0, 0, time.UTC), Notional: 6_000_000},
+		{EffectiveDate: time.Date(2030, 3, 12, 0, 0, 0, 0, time.UTC), Notional: 2_000_000},
+	}
+	if data, err = swap.MarshalTrade(trade); err != nil {
+		t.Fatalf("MarshalTrade with notional schedule: %v", err)
+	}
+	if spec, err = swap.UnmarshalTrade(data); err != nil {
+		t.Fatalf("UnmarshalTrade with notional schedule: %v", err)
+	}
+	if !reflect.DeepEqual(spec.NotionalSchedule, trade.Spec.NotionalSchedule) {
+		t.Fatalf("round-trip notional schedule %+v, want %+v", spec.NotionalSchedule, trade.Spec.NotionalSchedule)
+	}
+
+	// The contractual first-period rate and the current-period reset flag travel on the
+	// document and reprice identically.
+	stubRate := 0.024
+	trade.Spec.FirstFloatingFixing = &stubRate
+	trade.Spec.FirstResetOnCurrentPeriod = true
+	if data, err = swap.MarshalTrade(trade); err != nil {
+		t.Fatalf("MarshalTrade with first-period rate: %v", err)
+	}
+	if spec, err = swap.UnmarshalTrade(data); err != nil {
+		t.Fatalf("UnmarshalTrade with first-period rate: %v", err)
+	}
+	if spec.FirstFloatingFixing == nil || *spec.FirstFloatingFixing != stubRate || !spec.FirstResetOnCurrentPeriod {
+		t.Fatalf("round-trip first-period rate %v, current-period resets %v", spec.FirstFloatingFixing, spec.FirstResetOnCurrentPeriod)
+	}
+	spec.PayLegSpreadBP = trade.Spec.PayLegSpreadBP // percent-to-bp float noise, as above
+	if want, err = trade.NPV(); err != nil {
+		t.Fatalf("NPV: %v", err)
+	}
+	if got, err = swap.NPV(spec, trade.PayProjCurve, trade.RecProjCurve, trade.DiscountCurve, trade.ValuationDate); err != nil {
+		t.Fatalf("NPV of round-tripped spec: %v", err)
+	}
+	if got != want {
+		t.Fatalf("round-tripped NPV with first-period rate=%.6f, want %.6f", got, want)
+	}
+
+	if _, err := swap.UnmarshalTrade([]byte(`{"effectiveDate":"2026-03-12","terminationDate":"2031-03-12","swapStream":[]}`)); err == nil {
+		t.Fatalf("expected error for a document without streams")
+	}
+}
@@ -0,0 +1,59 @@
+package swap
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/meenmo/molib/swap/market"
+	"github.com/meenmo/molib/utils"
+)
+
+// FRAResult holds the valuation of a forward rate agreement. Rates are decimals.
+type FRAResult struct {
+	ForwardRate    float64   // simple forward over [start, end] on the leg's day count
+	Accrual        float64   // year fraction of the period
+	SettlementDate time.Time // FRAs settle at the period start
+	PV             float64   // discounted settlement amount
+	DV01           float64   // PV change for a +1bp parallel shift of both curves
+}
+
+// ForwardRateAgreement values an FRA on the period [start, end] (adjusted dates) with the
+// leg's day count. A positive notional is the buyer: it receives the forward and pays
+// contractRate (decimal).
+//
+// Unlike a swap coupon, the FRA settles at the period start, so the amount is discounted
+// over the period at the forward itself, N * (F - K) * tau / (1 + F * tau), and then to the
+// curve's settlement with DF(start), as in NPV. DV01 bumps the projection and discount
+// zero rates by 1bp from valuationDate, as in SwapTrade.DV01. An FRA settling before
+// valuationDate returns ErrMatured.
+func ForwardRateAgreement(projCurve ProjectionCurve, discCurve DiscountCurve, start, end time.Time, leg market.LegConvention, notional, contractRate float64, valuationDate time.Time) (FRAResult, error) {
+	if isNilInterface(projCurve) || isNilInterface(discCurve) {
+		return FRAResult{}, ErrNilCurve
+	}
+	if !end.After(start) {
+		return FRAResult{}, fmt.Errorf("ForwardRateAgreement: end %s not after start %s", end.Format("2006-01-02"), start.Format("2006-01-02"))
+	}
+	if start.Before(valuationDate) {
+		return FRAResult{}, fmt.Errorf("ForwardRateAgreement: settlement %s before valuation %s: %w",
+			start.Format("2006-01-02"), valuationDate.Format("2006-01-02"), ErrMatured)
+	}
+
+	dayCount := string(leg.DayCount)
+	tau := utils.YearFraction(start, end, dayCount)
+	pv := func(proj, disc ProjectionCurve) float64 {
+		fwd := forwardRate(proj, start, end, dayCount)
+		return notional * (fwd - contractRate) * tau / (1 + fwd*tau) * disc.DF(start)
+	}
+
+	base := pv(projCurve, discCurve)
+	bumpedProj := shiftedCurve{base: projCurve, anchor: valuationDate, shiftBP: dv01ShiftBP}
+	bumpedDisc := shiftedCurve{base: discCurve, anchor: valuationDate, shiftBP: dv01ShiftBP}
+
+	return FRAResult{
+		ForwardRate:    forwardRate(projCurve, start, end, dayCount),
+		Accrual:        tau,
+		SettlementDate: start,
+		PV:             base,
+		DV01:           (pv(bumpedProj, bumpedDisc) - base) / dv01ShiftBP,
+	}, nil
+}
@@ -0,0 +1,65 @@
+package swap_test
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/meenmo/molib/calendar"
+	"github.com/meenmo/molib/instruments/swaps"
+	"github.com/meenmo/molib/swap"
+	"github.com/meenmo/molib/swap/curve"
+)
+
+func TestForwardRateAgreement_ParRateMatchesForwardRates(t *testing.T) {
+	t.Parallel()
+
+	settlement := time.Date(2026, 3, 12, 0, 0, 0, 0, time.UTC)
+	disc := curve.BuildCurve(settlement, map[string]float64{
+		"1Y": 2.07, "2Y": 2.15, "3Y": 2.24, "5Y": 2.35,
+	}, calendar.TARGET, 1)
+	proj := curve.BuildProjectionCurve(settlement, swaps.EURIBOR6MFloating, map[string]float64{
+		"1Y": 2.25, "2Y": 2.35, "3Y": 2.44, "5Y": 2.56,
+	}, disc)
+
+	// A 6x12 FRA: the second 6M EURIBOR period of a 1Y forward-starting schedule.
+	leg := swaps.EURIBOR6MFloating
+	fwds, err := swap.GetForwardRates(proj, settlement.AddDate(0, 6, 0), settlement.AddDate(1, 0, 0), leg)
+	if err != nil {
+		t.Fatalf("GetForwardRates: %v", err)
+	}
+	if len(fwds) != 1 {
+		t.Fatalf("expected one period, got %d", len(fwds))
+	}
+	p := fwds[0]
+
+	const notional = 100_000_000
+	par, err := swap.ForwardRateAgreement(proj, disc, p.StartDate, p.EndDate, leg, notional, p.Rate, settlement)
+	if err != nil {
+		t.Fatalf("ForwardRateAgreement: %v", err)
+	}
+	if math.Abs(par.ForwardRate-p.Rate) > 1e-14 {
+		t.Fatalf("FRA forward %.12f, GetForwardRates %.12f", par.ForwardRate, p.Rate)
+	}
+	if math.Abs(par.PV) > 1e-6 {
+		t.Fatalf("FRA at the forward rate has PV %.6f, want 0", par.PV)
+	}
+	if !par.SettlementDate.Equal(p.StartDate) {
+		t.Fatalf("settlement %s, want period start %s", par.SettlementDate.Format("2006-01-02"), p.StartDate.Format("2006-01-02"))
+	}
+
+	// 1bp below the forward: the buyer gains N * 1bp * tau, discounted over the period
+	// at the forward and then to settlement from the period start.
+	off, err := swap.ForwardRateAgreement(proj, disc, p.StartDate, p.EndDate, leg, notional, p.Rate-1e-4, settlement)
+	if err != nil {
+		t.Fatalf("ForwardRateAgreement: %v", err)
+	}
+	want := notional * 1e-4 * off.Accrual / (1 + p.Rate*off.Accrual) * disc.DF(p.StartDate)
+	if math.Abs(off.PV-want) > 1e-6 {
+		t.Fatalf("FRA PV %.6f, want %.6f", off.PV, want)
+	}
+	// The buyer gains when rates rise: roughly notional * tau * 1bp.
+	if off.DV01 <= 0 || math.Abs(off.DV01-notional*off.Accrual*1e-4)/(notional*off.Accrual*1e-4) > 0.05 {
+		t.Fatalf("FRA DV01 %.2f, want ~%.2f", off.DV01, notional*off.Accrual*1e-4)
+	}
+}
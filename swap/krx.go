@@ -0,0 +1,110 @@
+package swap
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/meenmo/molib/calendar"
+	"github.com/meenmo/molib/instruments/swaps"
+	krx "github.com/meenmo/molib/swap/clearinghouse/krx"
+	"github.com/meenmo/molib/swap/market"
+)
+
+// FromKRX translates a legacy KRX CD91 swap into a SwapTrade priced through the unified
+// engine, to help callers migrate off the krx package.
+//
+// The trade projects and discounts on the legacy bootstrapped curve
+// (krx.BootstrapCurve on irs.SwapQuotes), as the legacy engine does. The floating rate of
+// the period spanning the settlement date is taken from irs.ReferenceIndex one KR business
+// day before the period start. No principal is exchanged. Valuation is as of the settlement
+// date.
+func FromKRX(irs krx.InterestRateSwap) (*SwapTrade, error) {
+	settlement, err := calendar.ParseDate(irs.SettlementDate)
+	if err != nil {
+		return nil, fmt.Errorf("FromKRX: settlement: %w", err)
+	}
+	effective, err := calendar.ParseDate(irs.EffectiveDate)
+	if err != nil {
+		return nil, fmt.Errorf("FromKRX: effective: %w", err)
+	}
+	termination, err := calendar.ParseDate(irs.TerminationDate)
+	if err != nil {
+		return nil, fmt.Errorf("FromKRX: termination: %w", err)
+	}
+	if irs.Notional == 0 {
+		return nil, fmt.Errorf("FromKRX: notional is required")
+	}
+	if len(irs.SwapQuotes) == 0 {
+		return nil, fmt.Errorf("FromKRX: swap quotes are required")
+	}
+
+	fixedLeg := swaps.KRXCD91DFixed
+	floatLeg := swaps.KRXCD91DFloating
+	floatLeg.IncludeInitialPrincipal = false
+	floatLeg.IncludeFinalPrincipal = false
+
+	spec := market.SwapSpec{
+		Notional:       irs.Notional,
+		EffectiveDate:  effective,
+		MaturityDate:   termination,
+		DiscountingOIS: floatLeg,
+	}
+	fixedBP := irs.FixedRate * 100
+	switch strings.ToUpper(string(irs.Direction)) {
+	case string(krx.PositionPay):
+		spec.PayLeg, spec.RecLeg = fixedLeg, floatLeg
+		spec.PayLegSpreadBP = fixedBP
+	case string(krx.PositionReceive):
+		spec.PayLeg, spec.RecLeg = floatLeg, fixedLeg
+		spec.RecLegSpreadBP = fixedBP
+	default:
+		return nil, fmt.Errorf("FromKRX: invalid direction %q: must be REC or PAY", irs.Direction)
+	}
+
+	periods, err := GenerateSchedule(effective, termination, floatLeg)
+	if err != nil {
+		return nil, fmt.Errorf("FromKRX: %w", err)
+	}
+	for _, p := range periods {
+		if p.PayDate.Before(settlement) {
+			continue
+		}
+		if !p.StartDate.After(settlement) {
+			if irs.ReferenceIndex == nil {
+				return nil, fmt.Errorf("FromKRX: reference index is required for the current period fixing")
+			}
+			fixingDate := calendar.AddBusinessDays(calendar.KR, p.StartDate, -1)
+			rate, ok := irs.ReferenceIndex.RateOn(fixingDate)
+			if !ok {
+				return nil, fmt.Errorf("FromKRX: missing reference rate fixing on %s", fixingDate.Format("2006-01-02"))
+			}
+			spec.FirstResetOnCurrentPeriod = true
+			if spec.PayLeg.LegType == market.LegFloating {
+				spec.PayLegFirstResetPct = &rate
+			} else {
+				spec.RecLegFirstResetPct = &rate
+			}
+		}
+		break
+	}
+
+	crv := krx.BootstrapCurve(irs.SettlementDate, irs.SwapQuotes)
+	var projPay, projRec ProjectionCurve
+	if spec.PayLeg.LegType == market.LegFloating {
+		projPay = crv
+	} else {
+		projRec = crv
+	}
+
+	return &SwapTrade{
+		ClearingHouse: ClearingHouseKRX,
+		CurveDate:     settlement,
+		TradeDate:     effective,
+		ValuationDate: settlement,
+		SpotDate:      settlement,
+		Spec:          spec,
+		DiscountCurve: crv,
+		PayProjCurve:  projPay,
+		RecProjCurve:  projRec,
+	}, nil
+}
@@ -0,0 +1,103 @@
+package swap_test
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/meenmo/molib/swap"
+	krx "github.com/meenmo/molib/swap/clearinghouse/krx"
+)
+
+// flatFeed returns the same CD91 fixing for every date.
+type flatFeed float64
+
+func (f flatFeed) RateOn(time.Time) (float64, bool) { return float64(f), true }
+
+func TestFromKRX_MatchesLegacyNPV(t *testing.T) {
+	t.Parallel()
+
+	quotes := krx.ParSwapQuotes{
+		0:    2.5524458035,
+		0.25: 2.7600000000,
+		0.5:  2.7225000000,
+		0.75: 2.7225000000,
+		1:    2.7225000000,
+		1.5:  2.7571428571,
+		2:    2.8075000000,
+		3:    2.8882142857,
+		4:    2.9596428571,
+		5:    3.0189285714,
+		6:    3.0614285714,
+		7:    3.0889285714,
+		8:    3.1153571429,
+		9:    3.1357142857,
+		10:   3.1578571429,
+		12:   3.1910714286,
+		15:   3.1757142857,
+		20:   3.0946428571,
+	}
+
+	tests := []struct {
+		name string
+		irs  krx.InterestRateSwap
+	}{
+		{
+			name: "seasoned receiver",
+			irs: krx.InterestRateSwap{
+				EffectiveDate:   "2024-01-25",
+				TerminationDate: "2044-01-25",
+				SettlementDate:  "2025-11-21",
+				FixedRate:       3.24,
+				Notional:        10_000_000_000,
+				Direction:       krx.PositionReceive,
+			},
+		},
+		{
+			name: "seasoned payer",
+			irs: krx.InterestRateSwap{
+				EffectiveDate:   "2025-03-14",
+				TerminationDate: "2030-03-14",
+				SettlementDate:  "2025-11-21",
+				FixedRate:       2.85,
+				Notional:        5_000_000_000,
+				Direction:       krx.PositionPay,
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			irs := tc.irs
+			irs.SwapQuotes = quotes
+			irs.ReferenceIndex = flatFeed(2.81)
+
+			legacy := irs.NPV(krx.BootstrapCurve(irs.SettlementDate, irs.SwapQuotes))
+
+			trade, err := swap.FromKRX(irs)
+			if err != nil {
+				t.Fatalf("FromKRX: %v", err)
+			}
+			got, err := trade.NPV()
+			if err != nil {
+				t.Fatalf("NPV: %v", err)
+			}
+
+			// Within 1e-9 of notional.
+			if tol := 1e-9 * irs.Notional; math.Abs(got-legacy) > tol {
+				t.Fatalf("NPV mismatch: unified %.2f legacy %.2f (diff %.2f, tol %.2f)", got, legacy, got-legacy, tol)
+			}
+			t.Logf("unified %.2f legacy %.2f diff %.4f", got, legacy, got-legacy)
+		})
+	}
+
+	bad := tests[0].irs
+	bad.SwapQuotes = quotes
+	bad.Direction = "BUY"
+	if _, err := swap.FromKRX(bad); err == nil {
+		t.Fatalf("expected error for invalid direction")
+	}
+}
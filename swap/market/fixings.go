@@ -0,0 +1,33 @@
+package market
+
+import "time"
+
+// FixingRepo supplies published index fixings (in percent) by index and date.
+type FixingRepo interface {
+	Fixing(index ReferenceIndex, date time.Time) (float64, bool)
+}
+
+// MapFixingRepo is a map-backed FixingRepo keyed by index and YYYY-MM-DD date.
+type MapFixingRepo struct {
+	fixings map[ReferenceIndex]map[string]float64
+}
+
+// NewMapFixingRepo creates an empty map-backed fixing repository.
+func NewMapFixingRepo() *MapFixingRepo {
+	return &MapFixingRepo{fixings: make(map[ReferenceIndex]map[string]float64)}
+}
+
+// Add records the fixing (percent) published for index on date, replacing any existing one.
+func (m *MapFixingRepo) Add(index ReferenceIndex, date time.Time, pct float64) {
+	byDate, ok := m.fixings[index]
+	if !ok {
+		byDate = make(map[string]float64)
+		m.fixings[index] = byDate
+	}
+	byDate[date.Format("2006-01-02")] = pct
+}
+
+func (m *MapFixingRepo) Fixing(index ReferenceIndex, date time.Time) (float64, bool) {
+	val, ok := m.fixings[index][date.Format("2006-01-02")]
+	return val, ok
+}
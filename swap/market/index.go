@@ -1,5 +1,12 @@
 package market
 
+import (
+	"fmt"
+	"strings"
+
+	"github.com/meenmo/molib/calendar"
+)
+
 // ReferenceIndex enumerates supported floating benchmarks.
 type ReferenceIndex string
 
@@ -14,14 +21,88 @@ const (
 	TIBOR6M   ReferenceIndex = "TIBOR6M"
 	SOFR      ReferenceIndex = "SOFR"
 	CD91D     ReferenceIndex = "CD91D"
+	CORRA     ReferenceIndex = "CORRA"
+	EFFR      ReferenceIndex = "EFFR" // effective Fed Funds
 )
 
 // IsOvernight reports whether the reference rate is an overnight index used in OIS discounting/projection.
 func IsOvernight(r ReferenceIndex) bool {
 	switch r {
-	case ESTR, TONAR, SOFR, SONIA:
+	case ESTR, TONAR, SOFR, SONIA, CORRA, EFFR:
 		return true
 	default:
 		return false
 	}
 }
+
+// IndexTenorMonths returns the tenor in months encoded in a term reference index
+// (EURIBOR3M -> 3, TIBOR6M -> 6, CD91D -> 3). Overnight indices return 0.
+// The second result is false for an unknown index.
+func IndexTenorMonths(r ReferenceIndex) (int, bool) {
+	switch r {
+	case ESTR, TONAR, SOFR, SONIA, CORRA, EFFR:
+		return 0, true
+	case EURIBOR3M, TIBOR3M, HIBOR3M, CD91D:
+		return 3, true
+	case EURIBOR6M, TIBOR6M:
+		return 6, true
+	default:
+		return 0, false
+	}
+}
+
+// IndexProfile holds the market defaults for trading a reference index: where it fixes,
+// how trades on it settle, the fixed leg it is quoted against and the curve it is
+// discounted on.
+type IndexProfile struct {
+	Index       ReferenceIndex
+	Currency    string
+	Calendar    calendar.CalendarID
+	SpotLagDays int // business days from trade date to spot; 0 is same-day
+	TenorMonths int // as IndexTenorMonths; 0 for overnight indices
+
+	// FixedDayCount and FixedFrequency describe the standard fixed leg quoted against
+	// the index.
+	FixedDayCount  DayCount
+	FixedFrequency Frequency
+
+	// DiscountIndex is the overnight index trades are discounted on, or the index itself
+	// for a market without a liquid OIS curve (HIBOR3M, CD91D).
+	DiscountIndex ReferenceIndex
+}
+
+var indexProfiles = map[ReferenceIndex]IndexProfile{
+	SOFR:      {SOFR, "USD", calendar.FD, 2, 0, Act360, FreqAnnual, SOFR},
+	EFFR:      {EFFR, "USD", calendar.FD, 2, 0, Act360, FreqAnnual, SOFR},
+	ESTR:      {ESTR, "EUR", calendar.TARGET, 2, 0, Act360, FreqAnnual, ESTR},
+	EURIBOR3M: {EURIBOR3M, "EUR", calendar.TARGET, 2, 3, DayCount("30E/360"), FreqAnnual, ESTR},
+	EURIBOR6M: {EURIBOR6M, "EUR", calendar.TARGET, 2, 6, DayCount("30E/360"), FreqAnnual, ESTR},
+	SONIA:     {SONIA, "GBP", calendar.EN, 0, 0, Act365F, FreqAnnual, SONIA},
+	CORRA:     {CORRA, "CAD", calendar.CA, 2, 0, Act365F, FreqAnnual, CORRA},
+	TONAR:     {TONAR, "JPY", calendar.JP, 2, 0, Act365F, FreqAnnual, TONAR},
+	TIBOR3M:   {TIBOR3M, "JPY", calendar.JP, 2, 3, Act365F, FreqSemi, TONAR},
+	TIBOR6M:   {TIBOR6M, "JPY", calendar.JP, 2, 6, Act365F, FreqSemi, TONAR},
+	HIBOR3M:   {HIBOR3M, "HKD", calendar.HK, 2, 3, Act365F, FreqQuarterly, HIBOR3M},
+	CD91D:     {CD91D, "KRW", calendar.KR, 1, 3, Act365F, FreqQuarterly, CD91D},
+}
+
+// indexAliases maps alternative index names used in CLI inputs to a ReferenceIndex.
+var indexAliases = map[string]ReferenceIndex{
+	"CD91":     CD91D,
+	"KRXCD91D": CD91D,
+}
+
+// IndexDefaults returns the market defaults for an index name (e.g. "EURIBOR6M",
+// "TONAR"). Names are case-insensitive and surrounding whitespace is ignored.
+func IndexDefaults(name string) (IndexProfile, error) {
+	key := strings.ToUpper(strings.TrimSpace(name))
+	r := ReferenceIndex(key)
+	if alias, ok := indexAliases[key]; ok {
+		r = alias
+	}
+	p, ok := indexProfiles[r]
+	if !ok {
+		return IndexProfile{}, fmt.Errorf("IndexDefaults: unknown index %q", name)
+	}
+	return p, nil
+}
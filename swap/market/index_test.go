@@ -0,0 +1,74 @@
+package market_test
+
+import (
+	"testing"
+
+	"github.com/meenmo/molib/calendar"
+	"github.com/meenmo/molib/swap/market"
+)
+
+func TestIndexTenorMonths(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		index  market.ReferenceIndex
+		months int
+	}{
+		{market.ESTR, 0},
+		{market.TONAR, 0},
+		{market.SOFR, 0},
+		{market.SONIA, 0},
+		{market.EURIBOR3M, 3},
+		{market.EURIBOR6M, 6},
+		{market.TIBOR3M, 3},
+		{market.TIBOR6M, 6},
+		{market.HIBOR3M, 3},
+		{market.CD91D, 3},
+	}
+
+	for _, tc := range tests {
+		months, ok := market.IndexTenorMonths(tc.index)
+		if !ok {
+			t.Errorf("IndexTenorMonths(%s): not recognized", tc.index)
+			continue
+		}
+		if months != tc.months {
+			t.Errorf("IndexTenorMonths(%s) = %d, want %d", tc.index, months, tc.months)
+		}
+		if (months == 0) != market.IsOvernight(tc.index) {
+			t.Errorf("IndexTenorMonths(%s) = %d disagrees with IsOvernight", tc.index, months)
+		}
+	}
+
+	if _, ok := market.IndexTenorMonths("LIBOR3M"); ok {
+		t.Errorf("expected unknown index to be rejected")
+	}
+}
+
+func TestIndexDefaults(t *testing.T) {
+	t.Parallel()
+
+	p, err := market.IndexDefaults("EURIBOR6M")
+	if err != nil {
+		t.Fatalf("IndexDefaults: %v", err)
+	}
+	if p.Calendar != calendar.TARGET {
+		t.Errorf("calendar = %s, want TARGET", p.Calendar)
+	}
+	if p.SpotLagDays != 2 {
+		t.Errorf("spot lag = %d, want 2", p.SpotLagDays)
+	}
+	if p.DiscountIndex != market.ESTR {
+		t.Errorf("discount index = %s, want ESTR", p.DiscountIndex)
+	}
+	if p.TenorMonths != 6 || p.FixedFrequency != market.FreqAnnual {
+		t.Errorf("tenor %dM fixed frequency %d, want 6M and annual", p.TenorMonths, p.FixedFrequency)
+	}
+
+	if alias, err := market.IndexDefaults(" cd91 "); err != nil || alias.Index != market.CD91D {
+		t.Errorf("IndexDefaults(cd91) = %+v, %v; want CD91D", alias, err)
+	}
+	if _, err := market.IndexDefaults("LIBOR3M"); err == nil {
+		t.Errorf("expected unknown index to be rejected")
+	}
+}
@@ -1,6 +1,9 @@
 package market
 
 import (
+	"fmt"
+	"reflect"
+	"strings"
 	"time"
 
 	"github.com/meenmo/molib/calendar"
@@ -25,11 +28,13 @@ const (
 	FreqDaily     Frequency = 0
 )
 
-// BusinessDayAdjustment roll convention.
+// BusinessDayAdjustment roll convention. An empty value means ModifiedFollowing.
 type BusinessDayAdjustment string
 
 const (
 	ModifiedFollowing BusinessDayAdjustment = "MODIFIED_FOLLOWING"
+	Preceding         BusinessDayAdjustment = "PRECEDING"
+	ModifiedPreceding BusinessDayAdjustment = "MODIFIED_PRECEDING"
 )
 
 // RollConvention for month-end handling.
@@ -57,6 +62,20 @@ const (
 	ScheduleBackward ScheduleDirection = "BACKWARD" // Roll from maturity date (Bloomberg convention)
 )
 
+// StubConvention places the irregular period of a schedule whose dates do not divide
+// into whole pay periods. Front stubs roll regular dates back from maturity, back stubs
+// forward from the effective date. A short stub is the leftover fraction of a period; a
+// long stub merges it into the adjacent regular period.
+type StubConvention string
+
+const (
+	StubNone       StubConvention = "NONE"        // no stub: the dates must divide into whole periods
+	StubShortFront StubConvention = "SHORT_FRONT" // short first period
+	StubLongFront  StubConvention = "LONG_FRONT"  // first period spans the stub and one regular period
+	StubShortBack  StubConvention = "SHORT_BACK"  // short last period
+	StubLongBack   StubConvention = "LONG_BACK"   // last period spans one regular period and the stub
+)
+
 // DayCount enum.
 type DayCount string
 
@@ -74,17 +93,74 @@ type LegConvention struct {
 	DayCount                DayCount
 	ResetFrequency          Frequency
 	PayFrequency            Frequency
-	FixingLagDays           int
+	FixingLagDays           int // business days before the reference date the rate fixes; must be >= 0
 	PayDelayDays            int
 	BusinessDayAdjustment   BusinessDayAdjustment
 	RollConvention          RollConvention
+	EndOfMonth              bool // with a month-end effective date, every period end rolls to month end before adjustment
 	Calendar                calendar.CalendarID
 	FixingCalendar          calendar.CalendarID
 	ResetPosition           ResetPosition
-	RateCutoffDays          int
+	RateCutoffDays          int // in-arrears only: business days before accrual end the last fixing is taken
 	IncludeInitialPrincipal bool
 	IncludeFinalPrincipal   bool
 	ScheduleDirection       ScheduleDirection // FORWARD (default) or BACKWARD (Bloomberg convention)
+	StubConvention          StubConvention    // when set, overrides ScheduleDirection (see GenerateSchedule)
+	RoundCoupons            bool              // round each coupon to the currency's minor unit before discounting (cleared cashflows)
+
+	// ForwardFromFixingDate projects an in-advance period's rate over the interval from its
+	// fixing date to the fixing date of its accrual end (the next period's fixing) instead of
+	// over the accrual dates, for IBOR conventions that accrue from the fixing date. The
+	// coupon still accrues over the period. Ignored for in-arrears legs.
+	ForwardFromFixingDate bool
+
+	// ConvexityVol is the lognormal volatility (decimal, e.g. 0.20) of an in-arrears term
+	// index's forward. When positive, each projected forward F of an in-arrears IBOR period
+	// gets the timing adjustment F²σ²tδ/(1+Fδ), t the years to its fixing and δ its accrual.
+	// Ignored for overnight and in-advance legs; zero leaves forwards unadjusted.
+	ConvexityVol float64
+
+	// LookbackDays moves a compounded overnight period's observations back by that many
+	// business days on the fixing calendar. Without ObservationShift ("lookback without
+	// observation shift") each business day d of the interest period uses the fixing
+	// published for d minus LookbackDays, weighted by d's own calendar days, and the rate
+	// annualizes over the interest period. With ObservationShift the observation period
+	// is the interest period with both ends moved back: its business days supply the
+	// fixings and the day weights, and the rate annualizes over the observation period
+	// before accruing over the interest period. RateCutoffDays then applies to the last
+	// observations. In-arrears overnight legs only; must be >= 0.
+	LookbackDays     int
+	ObservationShift bool
+
+	// Gearing multiplies the floating rate before the spread is added, so a period pays
+	// Gearing·index + spread. Zero means 1. The spread is not geared, so the leg's spread
+	// PV01 is unaffected; the leg's sensitivity to the index scales with Gearing.
+	Gearing float64
+
+	// CouponFloor and CouponCap, when set, clamp each floating period's rate (decimals) to
+	// [floor, cap]: a deterministic clamp of the projected coupon, not an option value. The
+	// clamp applies to the all-in rate (geared index + spread), or with ClampBeforeSpread to
+	// the geared index before the spread is added. Ignored on fixed legs.
+	CouponFloor       *float64
+	CouponCap         *float64
+	ClampBeforeSpread bool
+
+	// DelayFinalPrincipal pays the final principal exchange with the last coupon, PayDelayDays
+	// business days after the adjusted maturity, instead of on the maturity date, so both
+	// cashflows discount at the same date (cleared OIS convention). No effect without a pay delay.
+	DelayFinalPrincipal bool
+
+	// SpreadSchedule steps the leg's spread (bp; the coupon for fixed legs) by date. A
+	// period uses the entry with the latest date on or before its accrual start, in place
+	// of the flat spread; periods starting before the first entry keep the flat spread.
+	// Par solvers still move only the flat spread.
+	SpreadSchedule map[time.Time]float64
+}
+
+// NotionalStep sets the notional in force from EffectiveDate on.
+type NotionalStep struct {
+	EffectiveDate time.Time
+	Notional      float64
 }
 
 // SwapSpec describes a basis swap trade.
@@ -104,4 +180,67 @@ type SwapSpec struct {
 	// Maps to Bloomberg SWPM's "Latest Index" field.
 	PayLegFirstResetPct *float64
 	RecLegFirstResetPct *float64
+
+	// FirstResetOnCurrentPeriod moves the first-reset overrides from the period starting
+	// at EffectiveDate to the first period not yet paid at the valuation date, so a
+	// seasoned trade can carry its current period's observed fixing (as FromKRX does).
+	FirstResetOnCurrentPeriod bool
+
+	// FirstFloatingFixing, when non-nil, is the contractual rate of the first period of
+	// each floating leg's schedule, the one starting at EffectiveDate, such as a
+	// pre-agreed stub rate. It is a decimal (0.03 for 3%), unlike the first-reset
+	// overrides above, which are in percent. It replaces the projected, compounded or
+	// published rate of that period whatever its fixing date, and takes precedence over
+	// the first-reset overrides; the leg spread is still added.
+	FirstFloatingFixing *float64
+
+	// NotionalSchedule, when non-nil, replaces Notional for amortizing or accreting trades.
+	// A period accrues on the step in force at its accrual start (the latest step dated on
+	// or before it; Notional before the first step), so steps should fall on adjusted
+	// period starts. With principal exchanges, the initial exchange is the notional at
+	// EffectiveDate, each step strictly inside the trade exchanges the change in notional
+	// on its date, and the final exchange returns what remains.
+	NotionalSchedule []NotionalStep
+
+	// Fixings supplies realized fixings. When set, an overnight leg's period started
+	// before the valuation date compounds the published fixings for elapsed days with
+	// the projected forward for the remainder, and a term-rate (IBOR) period whose fixing
+	// date is on or before the valuation date uses its published fixing. When nil, every
+	// period is projected from the curve.
+	Fixings FixingRepo
+}
+
+// String renders the leg's non-zero fields as "{Name: value, ...}" in declaration order,
+// for logging and convention debugging.
+func (l LegConvention) String() string {
+	v := reflect.ValueOf(l)
+	parts := make([]string, 0, v.NumField())
+	for i := 0; i < v.NumField(); i++ {
+		if f := v.Field(i); !f.IsZero() {
+			parts = append(parts, fmt.Sprintf("%s: %v", v.Type().Field(i).Name, fieldValue(f)))
+		}
+	}
+	return "{" + strings.Join(parts, ", ") + "}"
+}
+
+// DiffLegs lists the fields in which a and b differ, one "Name: a -> b" entry per field
+// in declaration order. It returns nil for identical conventions.
+func DiffLegs(a, b LegConvention) []string {
+	va, vb := reflect.ValueOf(a), reflect.ValueOf(b)
+	var diffs []string
+	for i := 0; i < va.NumField(); i++ {
+		if !reflect.DeepEqual(va.Field(i).Interface(), vb.Field(i).Interface()) {
+			diffs = append(diffs, fmt.Sprintf("%s: %v -> %v", va.Type().Field(i).Name, fieldValue(va.Field(i)), fieldValue(vb.Field(i))))
+		}
+	}
+	return diffs
+}
+
+// fieldValue returns f for printing, with a non-nil pointer replaced by the value it
+// points to (so CouponFloor prints its rate, not an address).
+func fieldValue(f reflect.Value) any {
+	if f.Kind() == reflect.Pointer && !f.IsNil() {
+		return f.Elem().Interface()
+	}
+	return f.Interface()
 }
@@ -0,0 +1,61 @@
+package market_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/meenmo/molib/instruments/swaps"
+	"github.com/meenmo/molib/swap/market"
+)
+
+func TestDiffLegs_TIBOR3MVs6M(t *testing.T) {
+	t.Parallel()
+
+	got := market.DiffLegs(swaps.TIBOR3MFloating, swaps.TIBOR6MFloating)
+	want := []string{
+		"ReferenceIndex: TIBOR3M -> TIBOR6M",
+		"ResetFrequency: 3 -> 6",
+		"PayFrequency: 3 -> 6",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("DiffLegs = %q, want %q", got, want)
+	}
+
+	// Both TIBOR legs accrue ACT/365F; a day-count change is reported like any other field.
+	act360 := swaps.TIBOR6MFloating
+	act360.DayCount = market.Act360
+	if got := market.DiffLegs(swaps.TIBOR6MFloating, act360); !reflect.DeepEqual(got, []string{"DayCount: ACT/365F -> ACT/360"}) {
+		t.Fatalf("DiffLegs day count = %q", got)
+	}
+
+	if got := market.DiffLegs(swaps.TIBOR3MFloating, swaps.TIBOR3MFloating); got != nil {
+		t.Fatalf("identical legs differ: %q", got)
+	}
+
+	// Pointer fields print the value they point to.
+	floored := swaps.TIBOR6MFloating
+	floor := 0.005
+	floored.CouponFloor = &floor
+	if got := market.DiffLegs(swaps.TIBOR6MFloating, floored); !reflect.DeepEqual(got, []string{"CouponFloor: <nil> -> 0.005"}) {
+		t.Fatalf("DiffLegs floor = %q", got)
+	}
+	if s := floored.String(); !strings.Contains(s, "CouponFloor: 0.005") {
+		t.Fatalf("String() = %s, want the floor's value", s)
+	}
+}
+
+func TestLegConvention_String(t *testing.T) {
+	t.Parallel()
+
+	s := swaps.TIBOR3MFloating.String()
+	for _, want := range []string{"ReferenceIndex: TIBOR3M", "DayCount: ACT/365F", "PayDelayDays: 2", "Calendar: JPN"} {
+		if !strings.Contains(s, want) {
+			t.Errorf("String() = %s, missing %q", s, want)
+		}
+	}
+	// Zero-valued fields are omitted.
+	if strings.Contains(s, "RateCutoffDays") {
+		t.Errorf("String() = %s, should omit zero RateCutoffDays", s)
+	}
+}
@@ -0,0 +1,115 @@
+package market
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Quotes holds par quotes keyed by tenor string ("1W", "3M", "10Y"). It is a map type, so
+// plain map[string]float64 literals and values are accepted wherever Quotes is expected
+// and vice versa; Add and Validate catch what a bare map cannot, such as "12M" and "1Y"
+// quoting the same point.
+type Quotes map[string]float64
+
+// QuotePoint is one par quote with its tenor parsed.
+type QuotePoint struct {
+	Tenor string  // as keyed in Quotes
+	Years float64 // tenor in years (see ParseTenor)
+	Rate  float64
+}
+
+// ParseTenor converts a tenor string to years: "nD" and "nW" on a 365-day year, "nM" as
+// n/12, "nY" as n. A bare number is taken as years. Case and surrounding space are ignored.
+func ParseTenor(tenor string) (float64, error) {
+	s := strings.TrimSpace(strings.ToUpper(tenor))
+	if s == "" {
+		return 0, fmt.Errorf("ParseTenor: empty tenor")
+	}
+	var scale float64
+	switch s[len(s)-1] {
+	case 'D':
+		scale = 1.0 / 365.0
+	case 'W':
+		scale = 7.0 / 365.0
+	case 'M':
+		scale = 1.0 / 12.0
+	case 'Y':
+		scale = 1.0
+	default:
+		v, err := strconv.ParseFloat(s, 64)
+		if err != nil || v < 0 {
+			return 0, fmt.Errorf("ParseTenor: invalid tenor %q", tenor)
+		}
+		return v, nil
+	}
+	n, err := strconv.Atoi(s[:len(s)-1])
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("ParseTenor: invalid tenor %q", tenor)
+	}
+	return float64(n) * scale, nil
+}
+
+// Add records a quote, rejecting an unparseable tenor, a non-finite rate, or a tenor
+// already quoted under any spelling. q must be non-nil.
+func (q Quotes) Add(tenor string, rate float64) error {
+	years, err := ParseTenor(tenor)
+	if err != nil {
+		return fmt.Errorf("Quotes.Add: %w", err)
+	}
+	if math.IsNaN(rate) || math.IsInf(rate, 0) {
+		return fmt.Errorf("Quotes.Add: %s rate is %v", tenor, rate)
+	}
+	for k := range q {
+		if y, err := ParseTenor(k); err == nil && y == years {
+			return fmt.Errorf("Quotes.Add: %s duplicates quoted tenor %s", tenor, k)
+		}
+	}
+	q[tenor] = rate
+	return nil
+}
+
+// Validate reports the first unparseable tenor, non-finite rate, or pair of tenors for
+// the same point, checked in tenor order.
+func (q Quotes) Validate() error {
+	_, err := q.points()
+	return err
+}
+
+// Sorted returns the quotes in increasing tenor order. It panics if q does not validate.
+func (q Quotes) Sorted() []QuotePoint {
+	pts, err := q.points()
+	if err != nil {
+		panic(err)
+	}
+	return pts
+}
+
+func (q Quotes) points() ([]QuotePoint, error) {
+	keys := make([]string, 0, len(q))
+	for k := range q {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys) // deterministic errors when map order varies
+
+	pts := make([]QuotePoint, 0, len(q))
+	for _, k := range keys {
+		years, err := ParseTenor(k)
+		if err != nil {
+			return nil, fmt.Errorf("Quotes: %w", err)
+		}
+		if r := q[k]; math.IsNaN(r) || math.IsInf(r, 0) {
+			return nil, fmt.Errorf("Quotes: %s rate is %v", k, r)
+		}
+		pts = append(pts, QuotePoint{Tenor: k, Years: years, Rate: q[k]})
+	}
+	sort.SliceStable(pts, func(i, j int) bool { return pts[i].Years < pts[j].Years })
+	for i := 1; i < len(pts); i++ {
+		if pts[i].Years == pts[i-1].Years {
+			return nil, fmt.Errorf("Quotes: %s and %s quote the same tenor", pts[i-1].Tenor, pts[i].Tenor)
+		}
+	}
+	return pts, nil
+}
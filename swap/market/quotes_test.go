@@ -0,0 +1,77 @@
+package market_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/meenmo/molib/swap/market"
+)
+
+func TestQuotes_AddAndSorted(t *testing.T) {
+	t.Parallel()
+
+	q := market.Quotes{}
+	for _, p := range []struct {
+		tenor string
+		rate  float64
+	}{{"10Y", 2.70}, {"6M", 2.04}, {"1w", 2.01}, {"2Y", 2.15}, {"18M", 2.10}} {
+		if err := q.Add(p.tenor, p.rate); err != nil {
+			t.Fatalf("Add(%s): %v", p.tenor, err)
+		}
+	}
+
+	got := q.Sorted()
+	want := []market.QuotePoint{
+		{Tenor: "1w", Years: 7.0 / 365.0, Rate: 2.01},
+		{Tenor: "6M", Years: 0.5, Rate: 2.04},
+		{Tenor: "18M", Years: 1.5, Rate: 2.10},
+		{Tenor: "2Y", Years: 2, Rate: 2.15},
+		{Tenor: "10Y", Years: 10, Rate: 2.70},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d points, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("point %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+
+	// A plain map literal is a Quotes.
+	var m map[string]float64 = q
+	if len(m) != 5 {
+		t.Fatalf("map view has %d entries, want 5", len(m))
+	}
+
+	for _, bad := range []string{"", "Y", "3X", "-1M", "abc"} {
+		if err := q.Add(bad, 1); err == nil {
+			t.Fatalf("Add(%q): expected error", bad)
+		}
+	}
+	if err := q.Add("30Y", math.NaN()); err == nil {
+		t.Fatalf("Add with NaN rate: expected error")
+	}
+}
+
+func TestQuotes_Duplicates(t *testing.T) {
+	t.Parallel()
+
+	q := market.Quotes{"1Y": 2.07}
+	if err := q.Add("12M", 2.08); err == nil {
+		t.Fatalf("Add(12M) after 1Y: expected duplicate error")
+	}
+	if err := q.Add(" 1y ", 2.08); err == nil {
+		t.Fatalf("Add(\" 1y \") after 1Y: expected duplicate error")
+	}
+	if q["1Y"] != 2.07 || len(q) != 1 {
+		t.Fatalf("rejected Add modified quotes: %v", q)
+	}
+
+	// Duplicates built directly as a map are caught by Validate.
+	if err := (market.Quotes{"1Y": 2.07, "12M": 2.08, "5Y": 2.35}).Validate(); err == nil {
+		t.Fatalf("Validate: expected duplicate error for 1Y and 12M")
+	}
+	if err := (market.Quotes{"1Y": 2.07, "5Y": 2.35}).Validate(); err != nil {
+		t.Fatalf("Validate: %v", err)
+	}
+}
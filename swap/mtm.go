@@ -0,0 +1,65 @@
+package swap
+
+import (
+	"time"
+
+	"github.com/meenmo/molib/swap/market"
+	"github.com/meenmo/molib/utils"
+)
+
+// MTMResult is a trade's mark-to-market at its contractual rates. Amounts are signed from
+// the holder's perspective, as PV: accrued interest on the pay leg is negative.
+type MTMResult struct {
+	ValuationDate time.Time
+	PV            PV // leg PVs; PV.TotalPV is the dirty NPV
+
+	DirtyNPV   float64
+	PayAccrued float64 // interest accrued on the pay leg's in-progress period
+	RecAccrued float64
+	Accrued    float64 // PayAccrued + RecAccrued
+	CleanNPV   float64 // DirtyNPV - Accrued
+}
+
+// MarkToMarket values the trade at valuationDate with each fixed leg at the trade's
+// contractual rate: the spread it was built with, whatever SolveParSpread has since set on
+// the spec. Accrued interest runs from the start of each leg's period in progress to
+// valuationDate at that period's coupon rate, and the clean NPV excludes it.
+func (t *SwapTrade) MarkToMarket(valuationDate time.Time) (MTMResult, error) {
+	spec := t.Spec
+	if t.params.Notional != 0 {
+		if spec.PayLeg.LegType == market.LegFixed {
+			spec.PayLegSpreadBP = t.params.PayLegSpreadBP
+		}
+		if spec.RecLeg.LegType == market.LegFixed {
+			spec.RecLegSpreadBP = t.params.RecLegSpreadBP
+		}
+	}
+
+	report := &CashflowReport{ValuationDate: valuationDate}
+	pv, err := pvByLeg("MarkToMarket", spec, t.PayProjCurve, t.RecProjCurve, t.DiscountCurve, t.DiscountCurve, valuationDate, report)
+	if err != nil {
+		return MTMResult{}, err
+	}
+	res := MTMResult{
+		ValuationDate: valuationDate,
+		PV:            pv,
+		DirtyNPV:      pv.TotalPV,
+		PayAccrued:    -accruedInterest(report.PayLeg, spec.PayLeg, valuationDate),
+		RecAccrued:    accruedInterest(report.RecLeg, spec.RecLeg, valuationDate),
+	}
+	res.Accrued = res.PayAccrued + res.RecAccrued
+	res.CleanNPV = res.DirtyNPV - res.Accrued
+	return res, nil
+}
+
+// accruedInterest returns the unsigned interest accrued at valuationDate on the coupon of
+// rows whose accrual period contains it.
+func accruedInterest(rows []CashflowRow, leg market.LegConvention, valuationDate time.Time) float64 {
+	accrued := 0.0
+	for _, r := range rows {
+		if r.Type == CashflowCoupon && r.AccrualStart.Before(valuationDate) && valuationDate.Before(r.AccrualEnd) {
+			accrued += r.Notional * r.Rate * utils.YearFraction(r.AccrualStart, valuationDate, string(leg.DayCount))
+		}
+	}
+	return accrued
+}
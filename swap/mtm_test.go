@@ -0,0 +1,74 @@
+package swap_test
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/meenmo/molib/instruments/swaps"
+	"github.com/meenmo/molib/swap"
+)
+
+func TestSwapTrade_MarkToMarketCleanIsDirtyLessAccrued(t *testing.T) {
+	t.Parallel()
+
+	// A 5Y EURIBOR swap struck 2025-12-12, valued three months (90 days) into its first
+	// fixed and floating periods.
+	valuation := time.Date(2026, 3, 12, 0, 0, 0, 0, time.UTC)
+	firstFixing := 2.1
+	floatLeg := swaps.EURIBOR6MFloating
+	floatLeg.IncludeInitialPrincipal = false
+	floatLeg.IncludeFinalPrincipal = false
+	trade, err := swap.InterestRateSwap(swap.InterestRateSwapParams{
+		DataSource:          swap.DataSourceBGN,
+		ClearingHouse:       swap.ClearingHouseOTC,
+		CurveDate:           valuation,
+		TradeDate:           time.Date(2025, 12, 10, 0, 0, 0, 0, time.UTC),
+		ValuationDate:       valuation,
+		EffectiveDate:       time.Date(2025, 12, 12, 0, 0, 0, 0, time.UTC),
+		MaturityDate:        time.Date(2030, 12, 12, 0, 0, 0, 0, time.UTC),
+		Notional:            10_000_000,
+		PayLeg:              swaps.EURIBORFixed,
+		RecLeg:              floatLeg,
+		DiscountingOIS:      swaps.ESTRFloating,
+		OISQuotes:           map[string]float64{"1Y": 2.07, "2Y": 2.15, "5Y": 2.35, "10Y": 2.70},
+		RecLegQuotes:        map[string]float64{"1Y": 2.25, "2Y": 2.35, "5Y": 2.56, "10Y": 2.90},
+		PayLegSpreadBP:      250,
+		RecLegFirstResetPct: &firstFixing,
+	})
+	if err != nil {
+		t.Fatalf("InterestRateSwap: %v", err)
+	}
+	// Solving the par rate overwrites the spec; the mark stays at the contractual 2.5%.
+	if _, _, err := trade.SolveParSpread(swap.SpreadTargetPayLeg); err != nil {
+		t.Fatalf("SolveParSpread: %v", err)
+	}
+
+	mtm, err := trade.MarkToMarket(valuation)
+	if err != nil {
+		t.Fatalf("MarkToMarket: %v", err)
+	}
+	if mtm.DirtyNPV == 0 || mtm.DirtyNPV != mtm.PV.TotalPV {
+		t.Fatalf("dirty NPV %.6f, leg PVs %+v", mtm.DirtyNPV, mtm.PV)
+	}
+
+	// ACT/360 on both legs: 90 days at 2.5% paid, at the 2.1% fixing received.
+	if want := -10_000_000 * 0.025 * 90 / 360; math.Abs(mtm.PayAccrued-want) > 1e-6 {
+		t.Fatalf("pay leg accrued %.6f, want %.6f", mtm.PayAccrued, want)
+	}
+	if want := 10_000_000 * 0.021 * 90 / 360; math.Abs(mtm.RecAccrued-want) > 1e-6 {
+		t.Fatalf("receive leg accrued %.6f, want %.6f", mtm.RecAccrued, want)
+	}
+	if math.Abs(mtm.DirtyNPV-mtm.Accrued-mtm.CleanNPV) > 1e-9 || mtm.Accrued != mtm.PayAccrued+mtm.RecAccrued {
+		t.Fatalf("dirty %.6f - accrued %.6f != clean %.6f", mtm.DirtyNPV, mtm.Accrued, mtm.CleanNPV)
+	}
+
+	// On a period start nothing has accrued.
+	start, err := trade.MarkToMarket(time.Date(2025, 12, 12, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("MarkToMarket at effective date: %v", err)
+	}
+	if start.Accrued != 0 || start.CleanNPV != start.DirtyNPV {
+		t.Fatalf("accrued %.6f at the effective date", start.Accrued)
+	}
+}
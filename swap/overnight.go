@@ -0,0 +1,202 @@
+package swap
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/meenmo/molib/calendar"
+	"github.com/meenmo/molib/swap/market"
+	"github.com/meenmo/molib/utils"
+)
+
+// overnightDayBasis returns the day basis each overnight fixing accrues on:
+// 360 for SOFR/EFFR/ESTR, 365 for TONAR/SONIA/CORRA.
+func overnightDayBasis(r market.ReferenceIndex) (float64, error) {
+	switch r {
+	case market.SOFR, market.EFFR, market.ESTR:
+		return 360, nil
+	case market.TONAR, market.SONIA, market.CORRA:
+		return 365, nil
+	default:
+		return 0, fmt.Errorf("overnightDayBasis: %s is not an overnight index", r)
+	}
+}
+
+// CompoundedOvernightRate compounds daily overnight fixings over [start, end) and returns
+// the period's simple rate as a decimal.
+//
+// Two day counts are involved and they are deliberately separate:
+//   - compounding: each fixing (percent) accrues over the calendar days to the next
+//     business day on the fixing calendar, divided by the index's own basis (ACT/360
+//     for SOFR/ESTR, ACT/365 for TONAR/SONIA), regardless of the leg convention;
+//   - annualization: the compounded growth is converted to a rate with leg.DayCount
+//     over the whole period, matching how legPV accrues the coupon.
+//
+// The leg's LookbackDays, ObservationShift and RateCutoffDays choose which fixing each
+// day observes and the days it accrues (see overnightObservations).
+//
+// fixing returns the published rate for a business day; a missing fixing is an error.
+func CompoundedOvernightRate(leg market.LegConvention, start, end time.Time, fixing func(time.Time) (float64, bool)) (float64, error) {
+	basis, err := overnightDayBasis(leg.ReferenceIndex)
+	if err != nil {
+		return 0, fmt.Errorf("CompoundedOvernightRate: %w", err)
+	}
+	if !end.After(start) {
+		return 0, fmt.Errorf("CompoundedOvernightRate: end %s not after start %s", end.Format("2006-01-02"), start.Format("2006-01-02"))
+	}
+	obs, obsStart, obsEnd := overnightObservations(leg, start, end)
+	growth := 1.0
+	for _, o := range obs {
+		g, err := o.realizedGrowth(leg, basis, fixing)
+		if err != nil {
+			return 0, fmt.Errorf("CompoundedOvernightRate: %w", err)
+		}
+		growth *= g
+	}
+	return (growth - 1.0) / utils.YearFraction(obsStart, obsEnd, string(leg.DayCount)), nil
+}
+
+// ProjectedOvernightRate compounds the overnight rates implied by projCurve day by day
+// over [start, end) and returns the period's simple rate (decimal, annualized with
+// leg.DayCount), with the leg's rate cutoff: the last RateCutoffDays business days of
+// the period reuse the rate of the business day before them. A lookback moves the
+// observed days as in CompoundedOvernightRate.
+//
+// Without a cutoff or lookback, daily growth factors DF(d)/DF(next) telescope to
+// DF(start)/DF(end), the simple forward; the cutoff is what makes a real in-arrears
+// coupon differ from it.
+func ProjectedOvernightRate(projCurve ProjectionCurve, leg market.LegConvention, start, end time.Time) (float64, error) {
+	if isNilInterface(projCurve) {
+		return 0, ErrNilCurve
+	}
+	if end.Before(start) {
+		return 0, fmt.Errorf("ProjectedOvernightRate: end %s before start %s", end.Format("2006-01-02"), start.Format("2006-01-02"))
+	}
+	if end.Equal(start) {
+		return 0, nil // empty period (e.g. a stub collapsed by adjustment), as forwardRate
+	}
+	obs, obsStart, obsEnd := overnightObservations(leg, start, end)
+	growth := 1.0
+	for _, o := range obs {
+		growth *= o.projectedGrowth(projCurve)
+	}
+	return (growth - 1.0) / utils.YearFraction(obsStart, obsEnd, string(leg.DayCount)), nil
+}
+
+// overnightObservation is one business day of a compounded overnight period: the rate
+// observed on rateDate (projected over [rateDate, rateEnd)) accrues over the calendar
+// days [accrualStart, accrualEnd).
+type overnightObservation struct {
+	rateDate, rateEnd        time.Time
+	accrualStart, accrualEnd time.Time
+}
+
+// overnightObservations lays out the daily observations of the overnight period
+// [start, end) and returns them with the interval the compounded rate annualizes over.
+// Business days step on the fixing calendar (falling back to the payment calendar).
+//
+//   - No lookback: each business day d of [start, end) observes its own rate and accrues
+//     to the next business day (the last one to end).
+//   - Lookback of L days without observation shift: the days and weights are those of
+//     the interest period, but day d observes the rate of d minus L business days.
+//   - Observation shift of L days: the days, rates and weights all come from the
+//     observation period [start - L, end - L), which is also the annualization interval.
+//
+// With a rate cutoff of C days, the last C observations reuse the rate of the one
+// before them while keeping their own weights.
+func overnightObservations(leg market.LegConvention, start, end time.Time) ([]overnightObservation, time.Time, time.Time) {
+	cal := overnightFixingCalendar(leg)
+	from, to := start, end
+	if leg.ObservationShift {
+		from, to = overnightObservationStart(leg, start), calendar.AddBusinessDays(cal, end, -leg.LookbackDays)
+	}
+
+	var obs []overnightObservation
+	for d := from; d.Before(to); {
+		next := calendar.AddBusinessDays(cal, d, 1)
+		if next.After(to) {
+			next = to
+		}
+		o := overnightObservation{rateDate: d, rateEnd: next, accrualStart: d, accrualEnd: next}
+		if leg.LookbackDays > 0 && !leg.ObservationShift {
+			o.rateDate = calendar.AddBusinessDays(cal, d, -leg.LookbackDays)
+			o.rateEnd = calendar.AddBusinessDays(cal, o.rateDate, 1)
+		}
+		obs = append(obs, o)
+		d = next
+	}
+
+	if leg.RateCutoffDays > 0 && len(obs) > 0 {
+		frozen := max(len(obs)-leg.RateCutoffDays-1, 0) // index of the last rate observed
+		for i := frozen + 1; i < len(obs); i++ {
+			obs[i].rateDate, obs[i].rateEnd = obs[frozen].rateDate, obs[frozen].rateEnd
+		}
+	}
+	return obs, from, to
+}
+
+// overnightObservationStart returns the first date whose fixing an overnight period
+// starting at start observes: start itself, or start moved back by the lookback.
+func overnightObservationStart(leg market.LegConvention, start time.Time) time.Time {
+	if leg.LookbackDays <= 0 {
+		return start
+	}
+	return calendar.AddBusinessDays(overnightFixingCalendar(leg), start, -leg.LookbackDays)
+}
+
+func overnightFixingCalendar(leg market.LegConvention) calendar.CalendarID {
+	if leg.FixingCalendar == "" {
+		return leg.Calendar
+	}
+	return leg.FixingCalendar
+}
+
+// realizedGrowth returns the observation's growth factor from its published fixing.
+func (o overnightObservation) realizedGrowth(leg market.LegConvention, basis float64, fixing func(time.Time) (float64, bool)) (float64, error) {
+	rate, ok := fixing(o.rateDate)
+	if !ok {
+		return 0, fmt.Errorf("missing %s fixing on %s", leg.ReferenceIndex, o.rateDate.Format("2006-01-02"))
+	}
+	return 1.0 + rate/100.0*utils.Days(o.accrualStart, o.accrualEnd)/basis, nil
+}
+
+// projectedGrowth returns the observation's growth factor from the curve's overnight
+// forward: the DF ratio itself when the rate and accrual intervals coincide, otherwise
+// the forward's simple rate per calendar day applied to the accrual days.
+func (o overnightObservation) projectedGrowth(projCurve ProjectionCurve) float64 {
+	ratio := projCurve.DF(o.rateDate) / projCurve.DF(o.rateEnd)
+	if o.rateDate.Equal(o.accrualStart) && o.rateEnd.Equal(o.accrualEnd) {
+		return ratio
+	}
+	return 1 + (ratio-1)/utils.Days(o.rateDate, o.rateEnd)*utils.Days(o.accrualStart, o.accrualEnd)
+}
+
+// blendedOvernightRate returns the simple rate (decimal) of an overnight period whose
+// observations started before valuationDate: observations with a rate date before
+// valuationDate compound their published fixings, the rest the projection curve's
+// overnight forwards, under the same lookback and rate cutoff layout as
+// CompoundedOvernightRate and ProjectedOvernightRate. A period that has ended but is not
+// yet paid (payment delay) is fully realized.
+func blendedOvernightRate(leg market.LegConvention, p SchedulePeriod, projCurve ProjectionCurve, valuationDate time.Time, fixings market.FixingRepo) (float64, error) {
+	basis, err := overnightDayBasis(leg.ReferenceIndex)
+	if err != nil {
+		return 0, err
+	}
+	fixing := func(d time.Time) (float64, bool) {
+		return fixings.Fixing(leg.ReferenceIndex, d)
+	}
+	obs, obsStart, obsEnd := overnightObservations(leg, p.StartDate, p.EndDate)
+	growth := 1.0
+	for _, o := range obs {
+		if !o.rateDate.Before(valuationDate) {
+			growth *= o.projectedGrowth(projCurve)
+			continue
+		}
+		g, err := o.realizedGrowth(leg, basis, fixing)
+		if err != nil {
+			return 0, err
+		}
+		growth *= g
+	}
+	return (growth - 1.0) / utils.YearFraction(obsStart, obsEnd, string(leg.DayCount)), nil
+}
@@ -0,0 +1,466 @@
+package swap_test
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/meenmo/molib/calendar"
+	"github.com/meenmo/molib/instruments/swaps"
+	"github.com/meenmo/molib/swap"
+	"github.com/meenmo/molib/swap/curve"
+	"github.com/meenmo/molib/swap/market"
+	"github.com/meenmo/molib/utils"
+)
+
+func TestCompoundedOvernightRate_PerDayBasis(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2025, 3, 5, 0, 0, 0, 0, time.UTC) // Wednesday
+	end := time.Date(2025, 3, 12, 0, 0, 0, 0, time.UTC)
+	friday := time.Date(2025, 3, 7, 0, 0, 0, 0, time.UTC)
+
+	fixing := func(d time.Time) (float64, bool) {
+		if d.Equal(friday) {
+			return 4.35, true
+		}
+		return 4.30, true
+	}
+
+	// Quote the coupon on ACT/365F so the per-day SOFR basis (ACT/360) and the
+	// coupon day count differ.
+	leg := swaps.SOFRFloating
+	leg.DayCount = market.Act365F
+
+	got, err := swap.CompoundedOvernightRate(leg, start, end, fixing)
+	if err != nil {
+		t.Fatalf("CompoundedOvernightRate: %v", err)
+	}
+
+	// Wed, Thu, Fri (3 days over the weekend), Mon, Tue.
+	days := []float64{1, 1, 3, 1, 1}
+	rates := []float64{4.30, 4.30, 4.35, 4.30, 4.30}
+	growth360, growth365 := 1.0, 1.0
+	for i := range days {
+		growth360 *= 1 + rates[i]/100*days[i]/360
+		growth365 *= 1 + rates[i]/100*days[i]/365
+	}
+	want := (growth360 - 1) / (7.0 / 365.0)
+	if math.Abs(got-want) > 1e-14 {
+		t.Fatalf("compounded rate %.14f, want %.14f (per-day ACT/360, coupon ACT/365F)", got, want)
+	}
+	if wrong := (growth365 - 1) / (7.0 / 365.0); math.Abs(got-wrong) < 1e-6 {
+		t.Fatalf("compounded rate should not use the coupon day count per day: %.10f vs %.10f", got, wrong)
+	}
+
+	if _, err := swap.CompoundedOvernightRate(leg, start, end, func(time.Time) (float64, bool) { return 0, false }); err == nil {
+		t.Fatalf("expected error for missing fixings")
+	}
+	if _, err := swap.CompoundedOvernightRate(swaps.EURIBOR3MFloating, start, end, fixing); err == nil {
+		t.Fatalf("expected error for a term index")
+	}
+}
+
+func TestNPV_BlendsRealizedOvernightFixings(t *testing.T) {
+	t.Parallel()
+
+	curveDate := time.Date(2026, 1, 9, 0, 0, 0, 0, time.UTC)
+	quotes := map[string]float64{"1Y": 3.48945, "2Y": 3.3717, "5Y": 3.49207, "10Y": 3.8005}
+	effective := time.Date(2026, 1, 13, 0, 0, 0, 0, time.UTC)
+	leg := swaps.SOFRFloating
+	valuation := calendar.AddBusinessDays(leg.FixingCalendar, effective, 10)
+
+	params := swap.InterestRateSwapParams{
+		DataSource:     swap.DataSourceBGN,
+		ClearingHouse:  swap.ClearingHouseOTC,
+		CurveDate:      curveDate,
+		TradeDate:      curveDate,
+		ValuationDate:  valuation,
+		EffectiveDate:  effective,
+		MaturityDate:   time.Date(2028, 1, 13, 0, 0, 0, 0, time.UTC),
+		Notional:       10_000_000,
+		PayLeg:         swaps.SOFRFixed,
+		RecLeg:         leg,
+		DiscountingOIS: leg,
+		OISQuotes:      quotes,
+		RecLegQuotes:   quotes,
+		PayLegSpreadBP: 345,
+	}
+	projected, err := swap.InterestRateSwap(params)
+	if err != nil {
+		t.Fatalf("InterestRateSwap: %v", err)
+	}
+	base, err := projected.NPV()
+	if err != nil {
+		t.Fatalf("NPV: %v", err)
+	}
+
+	// Ten realized fixings well above the curve for the elapsed business days.
+	repo := market.NewMapFixingRepo()
+	growth := 1.0
+	for d, n := effective, 0; d.Before(valuation); n++ {
+		next := calendar.AddBusinessDays(leg.FixingCalendar, d, 1)
+		repo.Add(market.SOFR, d, 5.0)
+		growth *= 1 + 0.05*utils.Days(d, next)/360
+		d = next
+	}
+	params.Fixings = repo
+	trade, err := swap.InterestRateSwap(params)
+	if err != nil {
+		t.Fatalf("InterestRateSwap: %v", err)
+	}
+	got, err := trade.NPV()
+	if err != nil {
+		t.Fatalf("NPV with fixings: %v", err)
+	}
+
+	periods, err := swap.GenerateSchedule(effective, params.MaturityDate, leg)
+	if err != nil {
+		t.Fatalf("GenerateSchedule: %v", err)
+	}
+	first := periods[0]
+	proj, disc := trade.RecProjCurve, trade.DiscountCurve
+	alpha := utils.YearFraction(first.StartDate, first.EndDate, string(leg.DayCount))
+	// The projected remainder keeps the leg's one-day rate cutoff on the period's last day.
+	rest, err := swap.ProjectedOvernightRate(proj, leg, valuation, first.EndDate)
+	if err != nil {
+		t.Fatalf("ProjectedOvernightRate: %v", err)
+	}
+	restGrowth := 1 + rest*utils.YearFraction(valuation, first.EndDate, string(leg.DayCount))
+	blended := (growth*restGrowth - 1) / alpha
+	forward, err := swap.ProjectedOvernightRate(proj, leg, first.StartDate, first.EndDate)
+	if err != nil {
+		t.Fatalf("ProjectedOvernightRate: %v", err)
+	}
+	want := base + params.Notional*alpha*(blended-forward)*disc.DF(first.PayDate)
+	if math.Abs(got-want) > 1e-6 {
+		t.Fatalf("NPV with fixings=%.6f, want %.6f (projected-only %.6f)", got, want, base)
+	}
+	if got <= base {
+		t.Fatalf("receiver NPV should rise with fixings above the curve: %.2f vs %.2f", got, base)
+	}
+
+	// Once the period has ended but not yet paid (2-day payment delay), it is fully realized.
+	periodEnd := first.EndDate
+	fullGrowth := 1.0
+	for d := effective; d.Before(first.EndDate); {
+		next := calendar.AddBusinessDays(leg.FixingCalendar, d, 1)
+		if next.After(first.EndDate) {
+			next = first.EndDate
+		}
+		repo.Add(market.SOFR, d, 5.0)
+		fullGrowth *= 1 + 0.05*utils.Days(d, next)/360
+		d = next
+	}
+	if !periodEnd.Before(first.PayDate) {
+		t.Fatalf("test setup: %s is not before pay date %s", periodEnd.Format("2006-01-02"), first.PayDate.Format("2006-01-02"))
+	}
+	params.ValuationDate = periodEnd
+	params.Fixings = nil
+	unfixed, err := swap.InterestRateSwap(params)
+	if err != nil {
+		t.Fatalf("InterestRateSwap: %v", err)
+	}
+	unfixedNPV, err := unfixed.NPV()
+	if err != nil {
+		t.Fatalf("NPV: %v", err)
+	}
+	params.Fixings = repo
+	if trade, err = swap.InterestRateSwap(params); err != nil {
+		t.Fatalf("InterestRateSwap: %v", err)
+	}
+	if got, err = trade.NPV(); err != nil {
+		t.Fatalf("NPV after period end: %v", err)
+	}
+	proj, disc = trade.RecProjCurve, trade.DiscountCurve
+	if forward, err = swap.ProjectedOvernightRate(proj, leg, first.StartDate, first.EndDate); err != nil {
+		t.Fatalf("ProjectedOvernightRate: %v", err)
+	}
+	want = unfixedNPV + params.Notional*((fullGrowth-1)/alpha-forward)*alpha*disc.DF(first.PayDate)
+	if math.Abs(got-want) > 1e-6 {
+		t.Fatalf("NPV after period end=%.6f, want %.6f", got, want)
+	}
+	params.ValuationDate = valuation
+
+	// A repo missing an elapsed day is an error rather than a silent projection.
+	sparse := market.NewMapFixingRepo()
+	sparse.Add(market.SOFR, effective, 5.0)
+	params.Fixings = sparse
+	trade, err = swap.InterestRateSwap(params)
+	if err != nil {
+		t.Fatalf("InterestRateSwap: %v", err)
+	}
+	if _, err := trade.NPV(); err == nil {
+		t.Fatalf("expected error for missing fixings")
+	}
+}
+
+func TestProjectedOvernightRate_RateCutoff(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2026, 1, 13, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2027, 1, 13, 0, 0, 0, 0, time.UTC)
+	leg := swaps.SOFRFloating // one-day rate cutoff
+	if leg.RateCutoffDays != 1 {
+		t.Fatalf("test assumes a one-day cutoff, got %d", leg.RateCutoffDays)
+	}
+	simple := func(c swap.ProjectionCurve) float64 {
+		return (c.DF(start)/c.DF(end) - 1) / utils.YearFraction(start, end, string(leg.DayCount))
+	}
+
+	// Flat curve: every day's rate is the same, so freezing the last one changes nothing.
+	tau := func(d time.Time) float64 { return utils.YearFraction(start, d, "ACT/365F") }
+	flat := curve.NewCurveFromDFs(start, map[time.Time]float64{
+		start: 1,
+		end:   math.Exp(-0.035 * tau(end)),
+	}, calendar.FD, 0)
+	got, err := swap.ProjectedOvernightRate(flat, leg, start, end)
+	if err != nil {
+		t.Fatalf("ProjectedOvernightRate: %v", err)
+	}
+	if math.Abs(got-simple(flat)) > 1e-8 {
+		t.Fatalf("flat curve: compounded %.10f, simple %.10f", got, simple(flat))
+	}
+
+	// Steep curve: overnight jumps from 3.5% to 10% on the period's last day. The cutoff
+	// replaces that day's 10% with the prior day's 3.5%, so the coupon falls below the
+	// simple forward by about 6.5% * 1/360.
+	lastDay := time.Date(2027, 1, 12, 0, 0, 0, 0, time.UTC)
+	steep := curve.NewCurveFromDFs(start, map[time.Time]float64{
+		start:   1,
+		lastDay: math.Exp(-0.035 * tau(lastDay)),
+		end:     math.Exp(-0.035*tau(lastDay) - 0.10/365),
+	}, calendar.FD, 0)
+	if got, err = swap.ProjectedOvernightRate(steep, leg, start, end); err != nil {
+		t.Fatalf("ProjectedOvernightRate: %v", err)
+	}
+	if diff := simple(steep) - got; diff < 1.5e-4 || diff > 2e-4 {
+		t.Fatalf("steep curve: simple %.8f minus compounded %.8f = %.3gbp, want about 1.8bp", simple(steep), got, diff*1e4)
+	}
+
+	// Without a cutoff the daily growth telescopes back to the simple forward.
+	leg.RateCutoffDays = 0
+	if got, err = swap.ProjectedOvernightRate(steep, leg, start, end); err != nil {
+		t.Fatalf("ProjectedOvernightRate: %v", err)
+	}
+	if math.Abs(got-simple(steep)) > 1e-12 {
+		t.Fatalf("no cutoff: compounded %.12f, simple %.12f", got, simple(steep))
+	}
+}
+
+func TestCompoundedOvernightRate_LookbackAndObservationShift(t *testing.T) {
+	t.Parallel()
+
+	// One fixing per business day, each a basis point above the last: Mon 24 Feb 2025
+	// 4.30% through Fri 7 Mar 4.39%, so every observed date is identifiable.
+	day := func(m time.Month, d int) time.Time { return time.Date(2025, m, d, 0, 0, 0, 0, time.UTC) }
+	series := map[time.Time]float64{
+		day(2, 24): 4.30, day(2, 25): 4.31, day(2, 26): 4.32, day(2, 27): 4.33, day(2, 28): 4.34,
+		day(3, 3): 4.35, day(3, 4): 4.36, day(3, 5): 4.37, day(3, 6): 4.38, day(3, 7): 4.39,
+	}
+	fixing := func(d time.Time) (float64, bool) {
+		r, ok := series[d]
+		return r, ok
+	}
+	start, end := day(3, 3), day(3, 10) // Monday to Monday
+
+	compound := func(rates, days []float64, annualDays float64) float64 {
+		growth := 1.0
+		for i := range rates {
+			growth *= 1 + rates[i]/100*days[i]/360
+		}
+		return (growth - 1) / (annualDays / 360)
+	}
+
+	tests := []struct {
+		name     string
+		lookback int
+		shift    bool
+		cutoff   int
+		want     float64
+	}{
+		// Mon-Fri of the interest period, Friday accruing over the weekend.
+		{"lookback 0", 0, false, 0, compound([]float64{4.35, 4.36, 4.37, 4.38, 4.39}, []float64{1, 1, 1, 1, 3}, 7)},
+		// Interest-period weights, fixings two business days earlier (Thu 27 Feb to Wed 5 Mar).
+		{"lookback 2", 2, false, 0, compound([]float64{4.33, 4.34, 4.35, 4.36, 4.37}, []float64{1, 1, 1, 1, 3}, 7)},
+		// Observation period Thu 27 Feb to Thu 6 Mar: the weekend now sits on Friday 28 Feb.
+		{"observation shift 2", 2, true, 0, compound([]float64{4.33, 4.34, 4.35, 4.36, 4.37}, []float64{1, 3, 1, 1, 1}, 7)},
+		// A whole week back, the two conventions coincide: Mon 24 Feb to Fri 28 Feb.
+		{"lookback 5", 5, false, 0, compound([]float64{4.30, 4.31, 4.32, 4.33, 4.34}, []float64{1, 1, 1, 1, 3}, 7)},
+		{"observation shift 5", 5, true, 0, compound([]float64{4.30, 4.31, 4.32, 4.33, 4.34}, []float64{1, 1, 1, 1, 3}, 7)},
+		// A two-day cutoff freezes the last two observations at the third-last's fixing.
+		{"lookback 2 cutoff 2", 2, false, 2, compound([]float64{4.33, 4.34, 4.35, 4.35, 4.35}, []float64{1, 1, 1, 1, 3}, 7)},
+		{"observation shift 2 cutoff 2", 2, true, 2, compound([]float64{4.33, 4.34, 4.35, 4.35, 4.35}, []float64{1, 3, 1, 1, 1}, 7)},
+	}
+	for _, tt := range tests {
+		leg := swaps.SOFRFloating
+		leg.LookbackDays, leg.ObservationShift, leg.RateCutoffDays = tt.lookback, tt.shift, tt.cutoff
+		got, err := swap.CompoundedOvernightRate(leg, start, end, fixing)
+		if err != nil {
+			t.Fatalf("%s: CompoundedOvernightRate: %v", tt.name, err)
+		}
+		if math.Abs(got-tt.want) > 1e-14 {
+			t.Errorf("%s: compounded rate %.14f, want %.14f", tt.name, got, tt.want)
+		}
+	}
+
+	// An observation shift across a holiday annualizes over the observation period, not
+	// the interest period: Tue 18 Feb to Tue 25 Feb shifted 2 days observes Thu 13 Feb to
+	// Fri 21 Feb, 8 calendar days around Presidents' Day (Mon 17 Feb).
+	leg := swaps.SOFRFloating
+	leg.LookbackDays, leg.ObservationShift, leg.RateCutoffDays = 2, true, 0
+	flat := func(time.Time) (float64, bool) { return 4.0, true }
+	got, err := swap.CompoundedOvernightRate(leg, day(2, 18), day(2, 25), flat)
+	if err != nil {
+		t.Fatalf("CompoundedOvernightRate: %v", err)
+	}
+	want := compound([]float64{4, 4, 4, 4, 4}, []float64{1, 4, 1, 1, 1}, 8)
+	if math.Abs(got-want) > 1e-14 {
+		t.Fatalf("shift over holiday: compounded rate %.14f, want %.14f", got, want)
+	}
+}
+
+func TestProjectedOvernightRate_LookbackMatchesImpliedFixings(t *testing.T) {
+	t.Parallel()
+
+	// A steep curve, so a shifted observation changes the rate.
+	start := time.Date(2026, 1, 13, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2026, 4, 13, 0, 0, 0, 0, time.UTC)
+	tau := func(d time.Time) float64 { return utils.YearFraction(start, d, "ACT/365F") }
+	anchor := start.AddDate(0, 0, -30)
+	proj := curve.NewCurveFromDFs(anchor, map[time.Time]float64{
+		anchor:               1,
+		start:                math.Exp(-0.02 * (tau(start) - tau(anchor))),
+		end:                  math.Exp(-0.02*(tau(start)-tau(anchor)) - 0.06*tau(end)),
+		end.AddDate(1, 0, 0): math.Exp(-0.02*(tau(start)-tau(anchor)) - 0.06*tau(end.AddDate(1, 0, 0))),
+	}, calendar.FD, 0)
+
+	// Publish the curve's own overnight forwards as fixings: compounding them must agree
+	// with projecting the same observations.
+	base := swaps.SOFRFloating
+	implied := func(d time.Time) (float64, bool) {
+		next := calendar.AddBusinessDays(base.FixingCalendar, d, 1)
+		return (proj.DF(d)/proj.DF(next) - 1) / utils.Days(d, next) * 360 * 100, true
+	}
+
+	rates := map[string]float64{}
+	for _, tt := range []struct {
+		name     string
+		lookback int
+		shift    bool
+	}{{"none", 0, false}, {"lookback 2", 2, false}, {"shift 2", 2, true}, {"lookback 5", 5, false}, {"shift 5", 5, true}} {
+		leg := base
+		leg.LookbackDays, leg.ObservationShift = tt.lookback, tt.shift
+		projected, err := swap.ProjectedOvernightRate(proj, leg, start, end)
+		if err != nil {
+			t.Fatalf("%s: ProjectedOvernightRate: %v", tt.name, err)
+		}
+		realized, err := swap.CompoundedOvernightRate(leg, start, end, implied)
+		if err != nil {
+			t.Fatalf("%s: CompoundedOvernightRate: %v", tt.name, err)
+		}
+		if math.Abs(projected-realized) > 1e-12 {
+			t.Errorf("%s: projected %.12f, compounded implied fixings %.12f", tt.name, projected, realized)
+		}
+		rates[tt.name] = projected
+	}
+	// Looking back into the 2% stretch before start lowers the coupon.
+	if !(rates["lookback 5"] < rates["lookback 2"] && rates["lookback 2"] < rates["none"]) {
+		t.Fatalf("lookback should observe the lower pre-start rates: %v", rates)
+	}
+}
+
+func TestGenerateSchedule_LookbackFixingDate(t *testing.T) {
+	t.Parallel()
+
+	effective := time.Date(2026, 1, 13, 0, 0, 0, 0, time.UTC)
+	maturity := time.Date(2028, 1, 13, 0, 0, 0, 0, time.UTC)
+	leg := swaps.SOFRFloating
+	leg.LookbackDays = 2
+	periods, err := swap.GenerateSchedule(effective, maturity, leg)
+	if err != nil {
+		t.Fatalf("GenerateSchedule: %v", err)
+	}
+	for _, p := range periods {
+		if want := calendar.AddBusinessDays(leg.FixingCalendar, p.EndDate, -(leg.RateCutoffDays + 2)); !p.FixingDate.Equal(want) {
+			t.Errorf("period ending %s fixes %s, want %s", p.EndDate.Format("2006-01-02"), p.FixingDate.Format("2006-01-02"), want.Format("2006-01-02"))
+		}
+		if want := calendar.AddBusinessDays(leg.Calendar, p.EndDate, leg.PayDelayDays); !p.PayDate.Equal(want) {
+			t.Errorf("period ending %s pays %s, want %s", p.EndDate.Format("2006-01-02"), p.PayDate.Format("2006-01-02"), want.Format("2006-01-02"))
+		}
+	}
+
+	leg.LookbackDays = -1
+	if _, err := swap.GenerateSchedule(effective, maturity, leg); err == nil {
+		t.Fatalf("expected error for a negative lookback")
+	}
+}
+
+func TestNPV_FullyFixedOvernightPeriodMatchesCompounded(t *testing.T) {
+	t.Parallel()
+
+	curveDate := time.Date(2026, 1, 9, 0, 0, 0, 0, time.UTC)
+	quotes := map[string]float64{"1Y": 3.48945, "2Y": 3.3717, "5Y": 3.49207, "10Y": 3.8005}
+	effective := time.Date(2026, 1, 13, 0, 0, 0, 0, time.UTC)
+	maturity := time.Date(2028, 1, 13, 0, 0, 0, 0, time.UTC)
+	leg := swaps.SOFRFloating // one-day rate cutoff
+	periods, err := swap.GenerateSchedule(effective, maturity, leg)
+	if err != nil {
+		t.Fatalf("GenerateSchedule: %v", err)
+	}
+	first := periods[0]
+	if !first.EndDate.Before(first.PayDate) {
+		t.Fatalf("test setup: %s is not before pay date %s", first.EndDate.Format("2006-01-02"), first.PayDate.Format("2006-01-02"))
+	}
+
+	// Distinct daily fixings, rising into the period end, so the cutoff matters.
+	repo := market.NewMapFixingRepo()
+	fixing := map[time.Time]float64{}
+	for d, n := effective, 0; d.Before(first.EndDate); n++ {
+		fixing[d] = 4.0 + 0.001*float64(n)
+		repo.Add(market.SOFR, d, fixing[d])
+		d = calendar.AddBusinessDays(leg.FixingCalendar, d, 1)
+	}
+	compounded, err := swap.CompoundedOvernightRate(leg, first.StartDate, first.EndDate, func(d time.Time) (float64, bool) {
+		r, ok := fixing[d]
+		return r, ok
+	})
+	if err != nil {
+		t.Fatalf("CompoundedOvernightRate: %v", err)
+	}
+
+	// Valued on the period end, the first coupon is fully fixed but not yet paid.
+	trade, err := swap.InterestRateSwap(swap.InterestRateSwapParams{
+		DataSource:     swap.DataSourceBGN,
+		ClearingHouse:  swap.ClearingHouseOTC,
+		CurveDate:      curveDate,
+		TradeDate:      curveDate,
+		ValuationDate:  first.EndDate,
+		EffectiveDate:  effective,
+		MaturityDate:   maturity,
+		Notional:       10_000_000,
+		PayLeg:         swaps.SOFRFixed,
+		RecLeg:         leg,
+		DiscountingOIS: leg,
+		OISQuotes:      quotes,
+		RecLegQuotes:   quotes,
+		Fixings:        repo,
+	})
+	if err != nil {
+		t.Fatalf("InterestRateSwap: %v", err)
+	}
+	report, err := trade.CashflowReport()
+	if err != nil {
+		t.Fatalf("CashflowReport: %v", err)
+	}
+	for _, row := range report.RecLeg {
+		if row.Type != swap.CashflowCoupon || !row.AccrualStart.Equal(first.StartDate) {
+			continue
+		}
+		if math.Abs(row.ForwardRate-compounded) > 1e-14 {
+			t.Fatalf("fixed period rate %.14f, CompoundedOvernightRate %.14f", row.ForwardRate, compounded)
+		}
+		return
+	}
+	t.Fatalf("first receive period missing from the cashflow report")
+}
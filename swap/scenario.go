@@ -0,0 +1,176 @@
+package swap
+
+import (
+	"fmt"
+
+	"github.com/meenmo/molib/swap/market"
+)
+
+// ScenarioPnL reprices the trade under each scenario of par-quote shifts and returns the
+// PnL (scenario NPV minus base NPV) per scenario.
+//
+// A scenario maps a quote tenor (e.g. "5Y") to a shift in bp, applied to that tenor in
+// every quote set the trade was built from (OIS, discount, pay leg and receive leg quotes). Curves
+// are re-bootstrapped per scenario; the trade's current spreads are kept. The trade must
+// have been built by InterestRateSwap.
+func ScenarioPnL(trade *SwapTrade, scenarios []map[string]float64) ([]float64, error) {
+	if trade == nil {
+		return nil, fmt.Errorf("ScenarioPnL: trade is nil")
+	}
+	if trade.params.OISQuotes == nil && trade.params.DiscountQuotes == nil {
+		return nil, fmt.Errorf("ScenarioPnL: trade was not built by InterestRateSwap")
+	}
+
+	base, err := trade.NPV()
+	if err != nil {
+		return nil, fmt.Errorf("ScenarioPnL: base NPV: %w", err)
+	}
+
+	pnl := make([]float64, len(scenarios))
+	for i, shifts := range scenarios {
+		params := trade.params
+		params.OISQuotes = shiftQuotes(params.OISQuotes, shifts)
+		params.DiscountQuotes = shiftQuotes(params.DiscountQuotes, shifts)
+		params.PayLegQuotes = shiftQuotes(params.PayLegQuotes, shifts)
+		params.RecLegQuotes = shiftQuotes(params.RecLegQuotes, shifts)
+		for tenor := range shifts {
+			_, inOIS := params.OISQuotes[tenor]
+			_, inDisc := params.DiscountQuotes[tenor]
+			_, inPay := params.PayLegQuotes[tenor]
+			_, inRec := params.RecLegQuotes[tenor]
+			if !inOIS && !inDisc && !inPay && !inRec {
+				return nil, fmt.Errorf("ScenarioPnL: scenario %d: tenor %q is not quoted", i, tenor)
+			}
+		}
+
+		shocked, err := trade.rebuild(params)
+		if err != nil {
+			return nil, fmt.Errorf("ScenarioPnL: scenario %d: %w", i, err)
+		}
+		npv, err := shocked.NPV()
+		if err != nil {
+			return nil, fmt.Errorf("ScenarioPnL: scenario %d: %w", i, err)
+		}
+		pnl[i] = npv - base
+	}
+	return pnl, nil
+}
+
+// BumpOIS returns the trade rebuilt with its discount quotes (DiscountQuotes, else
+// OISQuotes) shifted in parallel by bp. IBOR projection curves are re-bootstrapped
+// against the bumped discount curve from their unchanged quotes, so the forward-vs-
+// discount basis the quotes imply is preserved while both curves move; a leg projecting
+// off the discount curve itself moves with it. Spreads and the spec are kept.
+func (t *SwapTrade) BumpOIS(bp float64) (*SwapTrade, error) {
+	if err := t.checkRebuildable("BumpOIS"); err != nil {
+		return nil, err
+	}
+	params := t.params
+	if params.DiscountQuotes != nil {
+		params.DiscountQuotes = shiftAllQuotes(params.DiscountQuotes, bp)
+	} else {
+		params.OISQuotes = shiftAllQuotes(params.OISQuotes, bp)
+	}
+	if t.projectsOffDiscount(t.PayProjCurve) {
+		params.PayLegQuotes = shiftAllQuotes(params.PayLegQuotes, bp)
+	}
+	if t.projectsOffDiscount(t.RecProjCurve) {
+		params.RecLegQuotes = shiftAllQuotes(params.RecLegQuotes, bp)
+	}
+	out, err := t.rebuild(params)
+	if err != nil {
+		return nil, fmt.Errorf("BumpOIS: %w", err)
+	}
+	return out, nil
+}
+
+// BumpProjection returns the trade rebuilt with the target leg's projection quotes shifted
+// in parallel by bp. It is a pure projection bump: the discount curve (and so the OIS
+// quotes) is held fixed and only that leg's forwards move. A fixed leg, or a leg that
+// projects off the discount curve itself, cannot be bumped apart from discounting and is
+// an error; use BumpOIS for it.
+func (t *SwapTrade) BumpProjection(leg SpreadTarget, bp float64) (*SwapTrade, error) {
+	if err := t.checkRebuildable("BumpProjection"); err != nil {
+		return nil, err
+	}
+	params := t.params
+	var proj ProjectionCurve
+	switch leg {
+	case SpreadTargetPayLeg:
+		proj = t.PayProjCurve
+		params.PayLegQuotes = shiftAllQuotes(params.PayLegQuotes, bp)
+	case SpreadTargetRecLeg:
+		proj = t.RecProjCurve
+		params.RecLegQuotes = shiftAllQuotes(params.RecLegQuotes, bp)
+	default:
+		return nil, fmt.Errorf("BumpProjection: unknown leg %d", leg)
+	}
+	if isNilInterface(proj) {
+		return nil, fmt.Errorf("BumpProjection: leg has no projection curve")
+	}
+	if t.projectsOffDiscount(proj) {
+		return nil, fmt.Errorf("BumpProjection: leg projects off the discount curve; use BumpOIS")
+	}
+	out, err := t.rebuild(params)
+	if err != nil {
+		return nil, fmt.Errorf("BumpProjection: %w", err)
+	}
+	return out, nil
+}
+
+func (t *SwapTrade) checkRebuildable(name string) error {
+	if t == nil {
+		return fmt.Errorf("%s: trade is nil", name)
+	}
+	if t.params.OISQuotes == nil && t.params.DiscountQuotes == nil {
+		return fmt.Errorf("%s: trade was not built by InterestRateSwap", name)
+	}
+	return nil
+}
+
+// projectsOffDiscount reports whether proj is the trade's discount curve (an overnight
+// leg reusing it, or single-curve IBOR discounting).
+func (t *SwapTrade) projectsOffDiscount(proj ProjectionCurve) bool {
+	if isNilInterface(proj) || isNilInterface(t.DiscountCurve) {
+		return false
+	}
+	disc, ok := proj.(DiscountCurve)
+	return ok && disc == t.DiscountCurve
+}
+
+// rebuild re-runs InterestRateSwap on params for t's effective and maturity dates, and
+// keeps t's spec (including any spreads changed since it was built).
+func (t *SwapTrade) rebuild(params InterestRateSwapParams) (*SwapTrade, error) {
+	params.EffectiveDate = t.Spec.EffectiveDate
+	params.MaturityDate = t.Spec.MaturityDate
+	out, err := InterestRateSwap(params)
+	if err != nil {
+		return nil, err
+	}
+	out.Spec = t.Spec
+	return out, nil
+}
+
+// shiftAllQuotes returns a copy of quotes (percent) shifted in parallel by bp.
+func shiftAllQuotes(quotes market.Quotes, bp float64) market.Quotes {
+	if quotes == nil {
+		return nil
+	}
+	out := make(market.Quotes, len(quotes))
+	for tenor, q := range quotes {
+		out[tenor] = q + bp*1e-2
+	}
+	return out
+}
+
+// shiftQuotes returns a copy of quotes (percent) with shifts (bp) added per tenor.
+func shiftQuotes(quotes map[string]float64, shifts map[string]float64) map[string]float64 {
+	if quotes == nil {
+		return nil
+	}
+	out := make(map[string]float64, len(quotes))
+	for tenor, q := range quotes {
+		out[tenor] = q + shifts[tenor]*1e-2
+	}
+	return out
+}
@@ -0,0 +1,168 @@
+package swap_test
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/meenmo/molib/instruments/swaps"
+	"github.com/meenmo/molib/swap"
+)
+
+func TestScenarioPnL_LinearForSmallShifts(t *testing.T) {
+	t.Parallel()
+
+	curveDate := time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)
+	estrQuotes := map[string]float64{
+		"1Y": 2.06795, "2Y": 2.153975, "3Y": 2.24, "5Y": 2.3495, "7Y": 2.484, "10Y": 2.6955,
+	}
+	euriborQuotes := map[string]float64{
+		"1Y": 2.25, "2Y": 2.35, "3Y": 2.44, "5Y": 2.56, "7Y": 2.70, "10Y": 2.90,
+	}
+
+	trade, err := swap.InterestRateSwap(swap.InterestRateSwapParams{
+		DataSource:     swap.DataSourceBGN,
+		ClearingHouse:  swap.ClearingHouseOTC,
+		CurveDate:      curveDate,
+		TradeDate:      curveDate,
+		SwapTenorYears: 7,
+		Notional:       10_000_000,
+		PayLeg:         swaps.EURIBORFixed,
+		RecLeg:         swaps.EURIBOR6MFloating,
+		DiscountingOIS: swaps.ESTRFloating,
+		OISQuotes:      estrQuotes,
+		RecLegQuotes:   euriborQuotes,
+		PayLegSpreadBP: 265,
+	})
+	if err != nil {
+		t.Fatalf("InterestRateSwap: %v", err)
+	}
+
+	pnl, err := swap.ScenarioPnL(trade, []map[string]float64{
+		{},
+		{"5Y": 0.5},
+		{"5Y": 1.0},
+		{"7Y": 0.5},
+		{"5Y": 0.5, "7Y": 0.5},
+	})
+	if err != nil {
+		t.Fatalf("ScenarioPnL: %v", err)
+	}
+
+	if math.Abs(pnl[0]) > 1e-6 {
+		t.Fatalf("zero scenario should have zero PnL, got %.8f", pnl[0])
+	}
+	if pnl[2] <= 0 {
+		t.Fatalf("payer should gain when the 5Y rises, got %.4f", pnl[2])
+	}
+	if d := math.Abs(pnl[2] - 2*pnl[1]); d > 1e-3*math.Abs(pnl[2]) {
+		t.Fatalf("PnL not linear in shift size: 1bp=%.4f, 2x0.5bp=%.4f", pnl[2], 2*pnl[1])
+	}
+	if d := math.Abs(pnl[4] - (pnl[1] + pnl[3])); d > 1e-3*math.Abs(pnl[4]) {
+		t.Fatalf("PnL not additive across tenors: joint=%.4f, sum=%.4f", pnl[4], pnl[1]+pnl[3])
+	}
+
+	base, err := trade.NPV()
+	if err != nil {
+		t.Fatalf("NPV: %v", err)
+	}
+	if _, err := swap.ScenarioPnL(trade, []map[string]float64{{"4Y": 1}}); err == nil {
+		t.Fatalf("expected error for unquoted tenor")
+	}
+	if after, _ := trade.NPV(); after != base {
+		t.Fatalf("ScenarioPnL modified the trade: NPV %.6f -> %.6f", base, after)
+	}
+}
+
+func TestSwapTrade_BumpOISMovesDiscountAndProjection(t *testing.T) {
+	t.Parallel()
+
+	curveDate := time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)
+	trade, err := swap.InterestRateSwap(swap.InterestRateSwapParams{
+		DataSource:     swap.DataSourceBGN,
+		ClearingHouse:  swap.ClearingHouseOTC,
+		CurveDate:      curveDate,
+		TradeDate:      curveDate,
+		SwapTenorYears: 7,
+		Notional:       10_000_000,
+		PayLeg:         swaps.EURIBORFixed,
+		RecLeg:         swaps.EURIBOR6MFloating,
+		DiscountingOIS: swaps.ESTRFloating,
+		OISQuotes:      map[string]float64{"1Y": 2.07, "2Y": 2.15, "5Y": 2.35, "7Y": 2.48, "10Y": 2.70},
+		RecLegQuotes:   map[string]float64{"1Y": 2.25, "2Y": 2.35, "5Y": 2.56, "7Y": 2.70, "10Y": 2.90},
+		PayLegSpreadBP: 265,
+	})
+	if err != nil {
+		t.Fatalf("InterestRateSwap: %v", err)
+	}
+	at := trade.Spec.EffectiveDate.AddDate(5, 0, 0)
+
+	ois, err := trade.BumpOIS(1)
+	if err != nil {
+		t.Fatalf("BumpOIS: %v", err)
+	}
+	if ois.DiscountCurve.DF(at) >= trade.DiscountCurve.DF(at) {
+		t.Fatalf("BumpOIS did not lower the 5Y discount factor")
+	}
+	// The EURIBOR curve is re-bootstrapped on the bumped OIS curve from the same quotes,
+	// so its pseudo-DFs move too, though by far less than the discount curve.
+	projMove := ois.RecProjCurve.DF(at) - trade.RecProjCurve.DF(at)
+	discMove := ois.DiscountCurve.DF(at) - trade.DiscountCurve.DF(at)
+	if projMove == 0 {
+		t.Fatalf("BumpOIS left the projection curve unchanged")
+	}
+	if math.Abs(projMove) >= math.Abs(discMove) {
+		t.Fatalf("projection moved %.3g, discount %.3g: EURIBOR quotes should hold forwards nearly fixed", projMove, discMove)
+	}
+	if ois.Spec.PayLegSpreadBP != trade.Spec.PayLegSpreadBP {
+		t.Fatalf("BumpOIS changed the spec")
+	}
+
+	proj, err := trade.BumpProjection(swap.SpreadTargetRecLeg, 1)
+	if err != nil {
+		t.Fatalf("BumpProjection: %v", err)
+	}
+	if proj.DiscountCurve.DF(at) != trade.DiscountCurve.DF(at) {
+		t.Fatalf("BumpProjection moved the discount curve")
+	}
+	if proj.RecProjCurve.DF(at) >= trade.RecProjCurve.DF(at) {
+		t.Fatalf("BumpProjection did not raise EURIBOR forwards")
+	}
+	base, _ := trade.NPV()
+	if npv, _ := proj.NPV(); npv <= base {
+		t.Fatalf("receiver of EURIBOR should gain on a projection bump: %.2f vs %.2f", npv, base)
+	}
+	if _, err := trade.BumpProjection(swap.SpreadTargetPayLeg, 1); err == nil {
+		t.Fatalf("expected error bumping the fixed leg's projection")
+	}
+
+	// An OIS leg projecting off the discount curve moves with BumpOIS and keeps sharing it.
+	estr := map[string]float64{"1Y": 2.07, "2Y": 2.15, "5Y": 2.35}
+	oisTrade, err := swap.InterestRateSwap(swap.InterestRateSwapParams{
+		DataSource:     swap.DataSourceBGN,
+		ClearingHouse:  swap.ClearingHouseOTC,
+		CurveDate:      curveDate,
+		TradeDate:      curveDate,
+		SwapTenorYears: 5,
+		Notional:       10_000_000,
+		PayLeg:         swaps.ESTRFixed,
+		RecLeg:         swaps.ESTRFloating,
+		DiscountingOIS: swaps.ESTRFloating,
+		OISQuotes:      estr,
+		RecLegQuotes:   estr,
+		PayLegSpreadBP: 235,
+	})
+	if err != nil {
+		t.Fatalf("InterestRateSwap OIS: %v", err)
+	}
+	bumped, err := oisTrade.BumpOIS(1)
+	if err != nil {
+		t.Fatalf("BumpOIS OIS: %v", err)
+	}
+	if bumped.RecProjCurve.DF(at) != bumped.DiscountCurve.DF(at) || bumped.DiscountCurve.DF(at) >= oisTrade.DiscountCurve.DF(at) {
+		t.Fatalf("OIS leg should keep projecting off the bumped discount curve")
+	}
+	if _, err := oisTrade.BumpProjection(swap.SpreadTargetRecLeg, 1); err == nil {
+		t.Fatalf("expected error for a pure projection bump of a leg on the discount curve")
+	}
+}
@@ -0,0 +1,99 @@
+package swap
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/meenmo/molib/utils"
+)
+
+// SwaptionResult holds a European swaption's value and sensitivities, in the trade's
+// currency for the trade's notional.
+type SwaptionResult struct {
+	ForwardRate float64 // forward par rate of the underlying swap, decimal
+	Annuity     float64 // fixed leg annuity per unit notional
+	Expiry      float64 // ACT/365F years from valuation to option expiry
+	Premium     float64
+	Delta       float64 // dPremium/dForwardRate, per unit (decimal) rate
+	Vega        float64 // dPremium/dVol, per unit of vol
+}
+
+// Swaption values a European option to enter the trade's underlying swap at strikeBP
+// (the fixed rate in bp), using the forward par rate and annuity from ForwardParRate.
+// isPayer selects the right to pay fixed.
+//
+// With normal false the premium is Black-76 and vol is lognormal (e.g. 0.25); the forward
+// and strike must then be positive. With normal true it is Bachelier and vol is a normal
+// rate vol in decimal (e.g. 0.0080 for 80bp), which prices negative rates. A zero vol, or
+// an expiry on the valuation date, returns the intrinsic value.
+func Swaption(trade *SwapTrade, strikeBP float64, vol float64, optionExpiry time.Time, isPayer bool, normal bool) (SwaptionResult, error) {
+	if trade == nil {
+		return SwaptionResult{}, fmt.Errorf("Swaption: nil trade")
+	}
+	if vol < 0 {
+		return SwaptionResult{}, fmt.Errorf("Swaption: negative vol %g", vol)
+	}
+	if optionExpiry.Before(trade.ValuationDate) {
+		return SwaptionResult{}, fmt.Errorf("Swaption: expiry %s before valuation %s: %w",
+			optionExpiry.Format("2006-01-02"), trade.ValuationDate.Format("2006-01-02"), ErrMatured)
+	}
+	if optionExpiry.After(trade.Spec.EffectiveDate) {
+		return SwaptionResult{}, fmt.Errorf("Swaption: expiry %s after swap effective date %s",
+			optionExpiry.Format("2006-01-02"), trade.Spec.EffectiveDate.Format("2006-01-02"))
+	}
+
+	fwd, annuity, err := trade.forwardParAnnuity()
+	if err != nil {
+		return SwaptionResult{}, fmt.Errorf("Swaption: %w", err)
+	}
+	strike := strikeBP / 10000.0
+	expiry := utils.YearFraction(trade.ValuationDate, optionExpiry, "ACT/365F")
+	if !normal && (fwd <= 0 || strike <= 0) {
+		return SwaptionResult{}, fmt.Errorf("Swaption: Black-76 needs a positive forward (%g) and strike (%g)", fwd, strike)
+	}
+
+	omega := 1.0 // +1 payer, -1 receiver
+	if !isPayer {
+		omega = -1.0
+	}
+
+	var value, delta, vega float64
+	stdDev := vol * math.Sqrt(expiry)
+	switch {
+	case stdDev == 0:
+		value = math.Max(omega*(fwd-strike), 0)
+		if omega*(fwd-strike) > 0 {
+			delta = omega
+		}
+	case normal:
+		d := (fwd - strike) / stdDev
+		value = omega*(fwd-strike)*normCDF(omega*d) + stdDev*normPDF(d)
+		delta = omega * normCDF(omega*d)
+		vega = math.Sqrt(expiry) * normPDF(d)
+	default:
+		d1 := (math.Log(fwd/strike) + 0.5*stdDev*stdDev) / stdDev
+		d2 := d1 - stdDev
+		value = omega * (fwd*normCDF(omega*d1) - strike*normCDF(omega*d2))
+		delta = omega * normCDF(omega*d1)
+		vega = fwd * math.Sqrt(expiry) * normPDF(d1)
+	}
+
+	scale := trade.Spec.Notional * annuity
+	return SwaptionResult{
+		ForwardRate: fwd,
+		Annuity:     annuity,
+		Expiry:      expiry,
+		Premium:     value * scale,
+		Delta:       delta * scale,
+		Vega:        vega * scale,
+	}, nil
+}
+
+func normCDF(x float64) float64 {
+	return 0.5 * math.Erfc(-x/math.Sqrt2)
+}
+
+func normPDF(x float64) float64 {
+	return math.Exp(-0.5*x*x) / math.Sqrt(2*math.Pi)
+}
@@ -0,0 +1,115 @@
+package swap_test
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/meenmo/molib/instruments/swaps"
+	"github.com/meenmo/molib/swap"
+)
+
+// swaptionUnderlying is a 1Y forward 5Y EURIBOR 6M swap paying fixedBP, without
+// principal exchanges so its NPV is the annuity times (forward - fixed rate).
+func swaptionUnderlying(t *testing.T, fixedBP float64) *swap.SwapTrade {
+	t.Helper()
+
+	curveDate := time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)
+	floatLeg := swaps.EURIBOR6MFloating
+	floatLeg.IncludeInitialPrincipal = false
+	floatLeg.IncludeFinalPrincipal = false
+	trade, err := swap.InterestRateSwap(swap.InterestRateSwapParams{
+		DataSource:        swap.DataSourceBGN,
+		ClearingHouse:     swap.ClearingHouseOTC,
+		CurveDate:         curveDate,
+		TradeDate:         curveDate,
+		ForwardTenorYears: 1,
+		SwapTenorYears:    5,
+		Notional:          10_000_000,
+		PayLeg:            swaps.EURIBORFixed,
+		RecLeg:            floatLeg,
+		DiscountingOIS:    swaps.ESTRFloating,
+		OISQuotes:         map[string]float64{"1Y": 2.06795, "2Y": 2.153975, "5Y": 2.3495, "10Y": 2.6955},
+		RecLegQuotes:      map[string]float64{"1Y": 2.25, "2Y": 2.35, "5Y": 2.56, "10Y": 2.90},
+		PayLegSpreadBP:    fixedBP,
+	})
+	if err != nil {
+		t.Fatalf("InterestRateSwap: %v", err)
+	}
+	return trade
+}
+
+func TestSwaption_ATMPutCallParity(t *testing.T) {
+	t.Parallel()
+
+	trade := swaptionUnderlying(t, 0)
+	fwdPct, err := trade.ForwardParRate()
+	if err != nil {
+		t.Fatalf("ForwardParRate: %v", err)
+	}
+	expiry := trade.ValuationDate.AddDate(1, 0, 0)
+
+	for _, tc := range []struct {
+		name   string
+		vol    float64
+		normal bool
+	}{
+		{"Black", 0.25, false},
+		{"Normal", 0.0080, true},
+	} {
+		payer, err := swap.Swaption(trade, fwdPct*100, tc.vol, expiry, true, tc.normal)
+		if err != nil {
+			t.Fatalf("%s payer: %v", tc.name, err)
+		}
+		receiver, err := swap.Swaption(trade, fwdPct*100, tc.vol, expiry, false, tc.normal)
+		if err != nil {
+			t.Fatalf("%s receiver: %v", tc.name, err)
+		}
+		if payer.Premium <= 0 {
+			t.Fatalf("%s: ATM premium %.2f should be positive", tc.name, payer.Premium)
+		}
+		if math.Abs(payer.Premium-receiver.Premium) > 1e-6 {
+			t.Errorf("%s: ATM payer %.6f != receiver %.6f", tc.name, payer.Premium, receiver.Premium)
+		}
+		// Payer minus receiver delta is the annuity-weighted notional.
+		if got, want := payer.Delta-receiver.Delta, trade.Spec.Notional*payer.Annuity; math.Abs(got-want) > 1e-6 {
+			t.Errorf("%s: payer-receiver delta %.6f, want %.6f", tc.name, got, want)
+		}
+	}
+}
+
+func TestSwaption_ZeroVolIsForwardSwapIntrinsic(t *testing.T) {
+	t.Parallel()
+
+	base := swaptionUnderlying(t, 0)
+	fwdPct, err := base.ForwardParRate()
+	if err != nil {
+		t.Fatalf("ForwardParRate: %v", err)
+	}
+	expiry := base.ValuationDate.AddDate(1, 0, 0)
+
+	// Struck 25bp below the forward: the payer is in the money by the forward swap NPV
+	// (pay fixed at the strike), the receiver is worthless.
+	strikeBP := fwdPct*100 - 25
+	trade := swaptionUnderlying(t, strikeBP)
+	npv, err := trade.NPV()
+	if err != nil {
+		t.Fatalf("NPV: %v", err)
+	}
+	for _, normal := range []bool{false, true} {
+		payer, err := swap.Swaption(trade, strikeBP, 0, expiry, true, normal)
+		if err != nil {
+			t.Fatalf("payer: %v", err)
+		}
+		if math.Abs(payer.Premium-npv) > 1e-4 {
+			t.Errorf("normal=%v: zero-vol payer %.6f, forward swap NPV %.6f", normal, payer.Premium, npv)
+		}
+		receiver, err := swap.Swaption(trade, strikeBP, 0, expiry, false, normal)
+		if err != nil {
+			t.Fatalf("receiver: %v", err)
+		}
+		if receiver.Premium != 0 {
+			t.Errorf("normal=%v: out-of-the-money zero-vol receiver %.6f, want 0", normal, receiver.Premium)
+		}
+	}
+}
@@ -2,20 +2,91 @@ package swap
 
 import (
 	"errors"
+	"fmt"
 	"time"
+
+	"github.com/meenmo/molib/swap/curve"
+	"github.com/meenmo/molib/swap/market"
 )
 
 var (
 	// ErrNilCurve is returned when a required curve argument is nil.
 	ErrNilCurve = errors.New("nil curve")
+	// ErrMatured is returned when the valuation date is after the swap's maturity and
+	// every cashflow has been paid, so the trade can be dropped from the book.
+	ErrMatured = errors.New("trade matured")
+	// ErrCurrencyMismatch is returned when a floating leg is discounted on a curve in
+	// another currency without opting in to it.
+	ErrCurrencyMismatch = errors.New("currency mismatch")
+	// ErrCalendarMismatch is returned when a floating leg rolls on a different calendar
+	// from the discounting leg without opting in to it.
+	ErrCalendarMismatch = errors.New("calendar mismatch")
+	// ErrMissingProjectionQuotes is returned when a floating leg has no quotes to build its
+	// projection curve from.
+	ErrMissingProjectionQuotes = errors.New("missing projection quotes")
+	// ErrMissingOISQuotes is returned when there are no quotes to build the discount curve
+	// from.
+	ErrMissingOISQuotes = errors.New("missing OIS quotes")
+	// ErrNotConverged is returned when a par solver reaches its iteration cap before the
+	// NPV tolerance is met.
+	ErrNotConverged = errors.New("did not converge")
 )
 
+// MissingQuotesError reports the index whose curve could not be built for lack of quotes.
+// It wraps ErrMissingProjectionQuotes or ErrMissingOISQuotes, so errors.Is matches either
+// and errors.As recovers Index.
+type MissingQuotesError struct {
+	Index market.ReferenceIndex
+	Err   error // ErrMissingProjectionQuotes or ErrMissingOISQuotes
+}
+
+func (e *MissingQuotesError) Error() string {
+	return fmt.Sprintf("%v for %s", e.Err, e.Index)
+}
+
+func (e *MissingQuotesError) Unwrap() error {
+	return e.Err
+}
+
 // DiscountCurve provides discount factors and zero rates for valuation.
 type DiscountCurve interface {
 	DF(t time.Time) float64
 	ZeroRateAt(t time.Time) float64
 }
 
+// ZeroShifter is an optional DiscountCurve capability: a native parallel shift of the
+// curve's zero rates, used by ParallelDV01 in place of wrapping the curve. *curve.Curve
+// is shifted natively as well (its ShiftZero returns the concrete type).
+type ZeroShifter interface {
+	ShiftZero(bumpBP float64) DiscountCurve
+}
+
+// curveZeroShifter adapts *curve.Curve to ZeroShifter.
+type curveZeroShifter struct{ c *curve.Curve }
+
+func (s curveZeroShifter) ShiftZero(bumpBP float64) DiscountCurve {
+	return s.c.ShiftZero(bumpBP)
+}
+
+// asZeroShifter returns c's native zero shift, if it has one.
+func asZeroShifter(c ProjectionCurve) (ZeroShifter, bool) {
+	switch v := c.(type) {
+	case ZeroShifter:
+		return v, true
+	case *curve.Curve:
+		return curveZeroShifter{v}, true
+	}
+	return nil, false
+}
+
+// NodeSensitivityCurve is an optional DiscountCurve capability: analytic partials of DF at
+// given dates with respect to each curve node's DF (see curve.Curve.NodeSensitivities), from
+// which SwapTrade.DiscountNodeDeltas assembles a risk ladder without rebuilding the curve.
+// A nil map means the curve cannot provide them.
+type NodeSensitivityCurve interface {
+	NodeSensitivities(dates []time.Time) map[float64][]float64
+}
+
 // ProjectionCurve provides discount factors used to infer forward rates.
 type ProjectionCurve interface {
 	DF(t time.Time) float64
@@ -52,6 +123,20 @@ type ForwardRate struct {
 	Rate       float64
 }
 
+// PeriodDiagnostic breaks down one floating-leg period as priced by legPV.
+//
+// Rates are decimals. TenorForward is the forward over the index tenor starting at
+// StartDate (see market.IndexTenorMonths; adjusted on the leg calendar), which
+// differs from Forward for stub periods; overnight legs report Forward.
+type PeriodDiagnostic struct {
+	SchedulePeriod
+	Accrual      float64
+	Forward      float64
+	TenorEndDate time.Time
+	TenorForward float64
+	PayDF        float64
+}
+
 // PV contains present values for each leg and the net sum.
 type PV struct {
 	PayLegPV float64
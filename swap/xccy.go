@@ -0,0 +1,129 @@
+package swap
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/meenmo/molib/swap/market"
+)
+
+// XCcyLeg is one leg of a cross-currency swap, with its own notional and curves.
+type XCcyLeg struct {
+	Currency   string // ISO code, informational
+	Notional   float64
+	Leg        market.LegConvention
+	SpreadBP   float64 // for fixed legs, the coupon in bp
+	Projection ProjectionCurve
+	Discount   DiscountCurve
+
+	// NotionalResets makes this the mark-to-market leg: each period's notional resets to
+	// the other leg's notional converted at the FX forward for the period start, and the
+	// change in notional is exchanged on that date (with IncludeFinalPrincipal).
+	// At most one leg may reset.
+	NotionalResets bool
+}
+
+// XCcyParams defines a cross-currency swap valued leg by leg in each leg's currency.
+type XCcyParams struct {
+	EffectiveDate time.Time
+	MaturityDate  time.Time
+	ValuationDate time.Time
+
+	PayLeg XCcyLeg
+	RecLeg XCcyLeg
+
+	// FXSpot is the price of one unit of the pay-leg currency in the receive-leg
+	// currency (e.g. 1.08 for a EUR pay leg against a USD receive leg).
+	FXSpot float64
+}
+
+// XCcyPV holds each leg's PV in its own currency and the total in the receive-leg currency.
+type XCcyPV struct {
+	PayLegPV float64 // pay-leg currency
+	RecLegPV float64 // receive-leg currency
+	TotalPV  float64 // receive-leg currency, pay leg converted at FXSpot
+}
+
+// CrossCurrencySwap values a cross-currency swap. Each leg is priced with legPV on its own
+// projection and discount curves, in its own currency; the pay leg is then converted to
+// the receive-leg currency at params.FXSpot.
+//
+// For a mark-to-market leg (see XCcyLeg.NotionalResets), the FX forward for date t is
+// FXSpot * DF_pay(t) / DF_rec(t) on the legs' discount curves, and the resets are priced
+// as a notional schedule stepping on each period's adjusted start.
+func CrossCurrencySwap(params XCcyParams) (XCcyPV, error) {
+	if params.FXSpot <= 0 {
+		return XCcyPV{}, fmt.Errorf("CrossCurrencySwap: FX spot must be positive, got %g", params.FXSpot)
+	}
+	if params.PayLeg.NotionalResets && params.RecLeg.NotionalResets {
+		return XCcyPV{}, errors.New("CrossCurrencySwap: only one leg can reset its notional")
+	}
+	if isNilInterface(params.PayLeg.Discount) || isNilInterface(params.RecLeg.Discount) {
+		return XCcyPV{}, ErrNilCurve
+	}
+
+	fxForward := func(t time.Time) float64 {
+		return params.FXSpot * params.PayLeg.Discount.DF(t) / params.RecLeg.Discount.DF(t)
+	}
+
+	pay, err := xccyLegPV(params, params.PayLeg, true, func(t time.Time) float64 {
+		return params.RecLeg.Notional / fxForward(t)
+	})
+	if err != nil {
+		return XCcyPV{}, fmt.Errorf("CrossCurrencySwap: pay leg: %w", err)
+	}
+	rec, err := xccyLegPV(params, params.RecLeg, false, func(t time.Time) float64 {
+		return params.PayLeg.Notional * fxForward(t)
+	})
+	if err != nil {
+		return XCcyPV{}, fmt.Errorf("CrossCurrencySwap: receive leg: %w", err)
+	}
+
+	return XCcyPV{
+		PayLegPV: pay,
+		RecLegPV: rec,
+		TotalPV:  rec + pay*params.FXSpot,
+	}, nil
+}
+
+// xccyLegPV prices one leg in its own currency. resetNotional converts the other leg's
+// notional into this leg's currency at date t, for a mark-to-market leg.
+func xccyLegPV(params XCcyParams, leg XCcyLeg, isPayLeg bool, resetNotional func(time.Time) float64) (float64, error) {
+	spec := market.SwapSpec{
+		Notional:      leg.Notional,
+		EffectiveDate: params.EffectiveDate,
+		MaturityDate:  params.MaturityDate,
+		PayLeg:        leg.Leg,
+		RecLeg:        leg.Leg,
+	}
+	if err := validateSwapSpec(spec); err != nil {
+		return 0, err
+	}
+	if done, err := matured(spec, params.ValuationDate); err != nil {
+		return 0, err
+	} else if done {
+		return 0, fmt.Errorf("valuation %s after maturity %s: %w",
+			params.ValuationDate.Format("2006-01-02"), params.MaturityDate.Format("2006-01-02"), ErrMatured)
+	}
+
+	if leg.NotionalResets {
+		periods, err := GenerateSchedule(params.EffectiveDate, params.MaturityDate, leg.Leg)
+		if err != nil {
+			return 0, err
+		}
+		for i, p := range periods {
+			// The first period's notional is fixed at the effective date.
+			start := p.StartDate
+			if i == 0 {
+				start = params.EffectiveDate
+			}
+			spec.NotionalSchedule = append(spec.NotionalSchedule, market.NotionalStep{
+				EffectiveDate: start,
+				Notional:      resetNotional(start),
+			})
+		}
+	}
+
+	return legPV(spec, leg.Leg, leg.Projection, leg.Discount, params.ValuationDate, leg.SpreadBP, isPayLeg)
+}
@@ -0,0 +1,95 @@
+package swap_test
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/meenmo/molib/calendar"
+	"github.com/meenmo/molib/instruments/swaps"
+	"github.com/meenmo/molib/swap"
+	"github.com/meenmo/molib/swap/curve"
+	"github.com/meenmo/molib/swap/market"
+)
+
+func TestCrossCurrencySwap_ReducesToBasisSwap(t *testing.T) {
+	t.Parallel()
+
+	settlement := time.Date(2026, 3, 12, 0, 0, 0, 0, time.UTC)
+	disc := curve.BuildCurve(settlement, map[string]float64{
+		"1Y": 2.07, "2Y": 2.15, "3Y": 2.24, "5Y": 2.35, "7Y": 2.48,
+	}, calendar.TARGET, 1)
+	proj3M := curve.BuildProjectionCurve(settlement, swaps.EURIBOR3MFloating, map[string]float64{
+		"1Y": 2.15, "2Y": 2.25, "3Y": 2.34, "5Y": 2.46, "7Y": 2.60,
+	}, disc)
+	proj6M := curve.BuildProjectionCurve(settlement, swaps.EURIBOR6MFloating, map[string]float64{
+		"1Y": 2.25, "2Y": 2.35, "3Y": 2.44, "5Y": 2.56, "7Y": 2.70,
+	}, disc)
+
+	const notional = 10_000_000
+	maturity := time.Date(2031, 3, 12, 0, 0, 0, 0, time.UTC)
+	spec := market.SwapSpec{
+		Notional:       notional,
+		EffectiveDate:  settlement,
+		MaturityDate:   maturity,
+		PayLeg:         swaps.EURIBOR6MFloating,
+		RecLeg:         swaps.EURIBOR3MFloating,
+		RecLegSpreadBP: 9.5,
+	}
+	want, err := swap.PVByLeg(spec, proj6M, proj3M, disc, settlement)
+	if err != nil {
+		t.Fatalf("PVByLeg: %v", err)
+	}
+
+	params := swap.XCcyParams{
+		EffectiveDate: settlement,
+		MaturityDate:  maturity,
+		ValuationDate: settlement,
+		PayLeg: swap.XCcyLeg{
+			Currency:   "EUR",
+			Notional:   notional,
+			Leg:        swaps.EURIBOR6MFloating,
+			Projection: proj6M,
+			Discount:   disc,
+		},
+		RecLeg: swap.XCcyLeg{
+			Currency:   "USD",
+			Notional:   notional,
+			Leg:        swaps.EURIBOR3MFloating,
+			SpreadBP:   9.5,
+			Projection: proj3M,
+			Discount:   disc,
+		},
+		FXSpot: 1,
+	}
+	got, err := swap.CrossCurrencySwap(params)
+	if err != nil {
+		t.Fatalf("CrossCurrencySwap: %v", err)
+	}
+	if math.Abs(got.PayLegPV-want.PayLegPV) > 1e-6 || math.Abs(got.RecLegPV-want.RecLegPV) > 1e-6 ||
+		math.Abs(got.TotalPV-want.TotalPV) > 1e-6 {
+		t.Fatalf("xccy PV %+v, basis swap PV %+v", got, want)
+	}
+
+	// On identical curves at FX 1 the FX forward stays 1, so resets change nothing.
+	params.RecLeg.NotionalResets = true
+	mtm, err := swap.CrossCurrencySwap(params)
+	if err != nil {
+		t.Fatalf("CrossCurrencySwap(MTM): %v", err)
+	}
+	if math.Abs(mtm.TotalPV-want.TotalPV) > 1e-6 {
+		t.Fatalf("MTM xccy PV %.6f, basis swap PV %.6f", mtm.TotalPV, want.TotalPV)
+	}
+
+	// Doubling the FX spot doubles the converted pay leg's contribution.
+	params.RecLeg.NotionalResets = false
+	params.FXSpot = 2
+	params.RecLeg.Notional = 2 * notional
+	scaled, err := swap.CrossCurrencySwap(params)
+	if err != nil {
+		t.Fatalf("CrossCurrencySwap(FX 2): %v", err)
+	}
+	if math.Abs(scaled.TotalPV-2*want.TotalPV) > 1e-6 {
+		t.Fatalf("FX 2 xccy PV %.6f, want %.6f", scaled.TotalPV, 2*want.TotalPV)
+	}
+}
@@ -21,6 +21,12 @@ func AdjacentDates(target time.Time, dates []time.Time) (time.Time, time.Time) {
 	return calendar.AdjacentDates(target, dates)
 }
 
+// SearchDates returns the index of the first date in sorted on or after target
+// and whether that date equals target. It delegates to calendar.SearchDates.
+func SearchDates(sorted []time.Time, target time.Time) (idx int, exact bool) {
+	return calendar.SearchDates(sorted, target)
+}
+
 // DateParser converts YYYY-MM-DD to time.Time or panics on error.
 // Deprecated: Use calendar.MustParseDate or calendar.ParseDate instead.
 func DateParser(strDate string) time.Time {
@@ -0,0 +1,44 @@
+package utils_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/meenmo/molib/utils"
+)
+
+func TestSearchDates(t *testing.T) {
+	t.Parallel()
+
+	sorted := []time.Time{
+		time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC),
+		time.Date(2025, 4, 2, 0, 0, 0, 0, time.UTC),
+		time.Date(2025, 7, 2, 0, 0, 0, 0, time.UTC),
+	}
+
+	tests := []struct {
+		name      string
+		target    time.Time
+		wantIdx   int
+		wantExact bool
+	}{
+		{name: "before first", target: time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC), wantIdx: 0},
+		{name: "exact first", target: sorted[0], wantIdx: 0, wantExact: true},
+		{name: "interior", target: time.Date(2025, 5, 15, 0, 0, 0, 0, time.UTC), wantIdx: 2},
+		{name: "exact interior", target: sorted[1], wantIdx: 1, wantExact: true},
+		{name: "exact last", target: sorted[2], wantIdx: 2, wantExact: true},
+		{name: "after last", target: time.Date(2025, 12, 31, 0, 0, 0, 0, time.UTC), wantIdx: 3},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			idx, exact := utils.SearchDates(sorted, tc.target)
+			if idx != tc.wantIdx || exact != tc.wantExact {
+				t.Fatalf("SearchDates(%s) = (%d, %v), want (%d, %v)",
+					tc.target.Format("2006-01-02"), idx, exact, tc.wantIdx, tc.wantExact)
+			}
+		})
+	}
+}
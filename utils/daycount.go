@@ -2,34 +2,29 @@ package utils
 
 import (
 	"time"
+
+	"github.com/meenmo/molib/calendar"
+	"github.com/meenmo/molib/daycount"
 )
 
-// YearFraction computes year fraction between two dates using the specified day count convention.
-// Supported conventions: ACT/360, ACT/365F, 30E/360, 30/360
+// YearFraction computes year fraction between two dates using the day count convention
+// registered under convention in the daycount package (ACT/360, ACT/365F, 30E/360,
+// 30/360, ACT/ACT ISDA, ...). Unknown names fall back to ACT/365F. No reference period is
+// passed, so use daycount.ActActICMA directly for bond accruals.
 func YearFraction(start, end time.Time, convention string) float64 {
-	switch convention {
-	case "ACT/360":
-		days := end.Sub(start).Hours() / 24
-		return days / 360.0
-	case "ACT/365F":
-		days := end.Sub(start).Hours() / 24
-		return days / 365.0
-	case "30E/360", "30/360":
-		// 30E/360 ISDA (Eurobond basis)
-		// D1 and D2 are capped at 30
-		d1 := start.Day()
-		if d1 > 30 {
-			d1 = 30
-		}
-		d2 := end.Day()
-		if d2 > 30 {
-			d2 = 30
-		}
-		y1, m1 := start.Year(), int(start.Month())
-		y2, m2 := end.Year(), int(end.Month())
-		return float64(360*(y2-y1)+30*(m2-m1)+(d2-d1)) / 360.0
-	default:
-		days := end.Sub(start).Hours() / 24
-		return days / 365.0
+	dc, ok := daycount.Lookup(convention)
+	if !ok {
+		dc = daycount.Act365F
+	}
+	return dc.YearFraction(start, end, time.Time{}, time.Time{})
+}
+
+// YearFractionCal is YearFraction for conventions that need a holiday calendar: "BUS/252"
+// counts the business days of cal in (start, end] over 252 (BRL, some KRW money-market
+// conventions). Other conventions ignore cal.
+func YearFractionCal(cal calendar.CalendarID, start, end time.Time, dc string) float64 {
+	if dc == "BUS/252" {
+		return daycount.Business252{Calendar: cal}.YearFraction(start, end, time.Time{}, time.Time{})
 	}
+	return YearFraction(start, end, dc)
 }
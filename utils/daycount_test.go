@@ -0,0 +1,38 @@
+package utils_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/meenmo/molib/calendar"
+	"github.com/meenmo/molib/utils"
+)
+
+func TestYearFractionCal_Bus252KoreanCalendar(t *testing.T) {
+	t.Parallel()
+
+	// October 2025 has 23 weekdays; National Foundation Day (3rd), Chuseok with its
+	// substitute (6th-8th) and Hangul Day (9th) leave 18 KRW business days.
+	start := time.Date(2025, 9, 30, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2025, 10, 31, 0, 0, 0, 0, time.UTC)
+	if n := calendar.BusinessDaysBetween(calendar.KR, start, end); n != 18 {
+		t.Fatalf("BusinessDaysBetween = %d, want 18", n)
+	}
+	if n := calendar.BusinessDaysBetween(calendar.KR, end, start); n != -18 {
+		t.Fatalf("reversed BusinessDaysBetween = %d, want -18", n)
+	}
+	if got := calendar.AddBusinessDays(calendar.KR, start, 18); !got.Equal(end) {
+		t.Fatalf("AddBusinessDays(18) = %s, want %s", got.Format("2006-01-02"), end.Format("2006-01-02"))
+	}
+
+	if got, want := utils.YearFractionCal(calendar.KR, start, end, "BUS/252"), 18.0/252; got != want {
+		t.Fatalf("BUS/252 = %.15f, want %.15f", got, want)
+	}
+	// Without holidays only weekends are skipped; other conventions ignore the calendar.
+	if got, want := utils.YearFraction(start, end, "BUS/252"), 23.0/252; got != want {
+		t.Fatalf("BUS/252 without calendar = %.15f, want %.15f", got, want)
+	}
+	if got, want := utils.YearFractionCal(calendar.KR, start, end, "ACT/365F"), 31.0/365; got != want {
+		t.Fatalf("ACT/365F = %.15f, want %.15f", got, want)
+	}
+}
@@ -0,0 +1,44 @@
+package utils
+
+import "sort"
+
+// Interp1D linearly interpolates ys over the ascending grid xs at x.
+//
+// The bracketing segment is located by binary search. When x lies outside
+// [xs[0], xs[len(xs)-1]], the boundary value is held flat if extrapolate is
+// true; otherwise the nearest end segment is extended linearly.
+func Interp1D(xs, ys []float64, x float64, extrapolate bool) float64 {
+	n := len(xs)
+	if n == 0 || n != len(ys) {
+		panic("Interp1D: xs and ys must be non-empty and of equal length")
+	}
+	if n == 1 {
+		return ys[0]
+	}
+	if extrapolate {
+		if x <= xs[0] {
+			return ys[0]
+		}
+		if x >= xs[n-1] {
+			return ys[n-1]
+		}
+	}
+
+	// First index with xs[i] >= x.
+	i := sort.SearchFloat64s(xs, x)
+	if i < n && xs[i] == x {
+		return ys[i]
+	}
+	if i <= 0 {
+		i = 1
+	} else if i >= n {
+		i = n - 1
+	}
+
+	x0, x1 := xs[i-1], xs[i]
+	y0, y1 := ys[i-1], ys[i]
+	if x1 == x0 {
+		return y0
+	}
+	return y0 + (y1-y0)*(x-x0)/(x1-x0)
+}
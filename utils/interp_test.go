@@ -0,0 +1,51 @@
+package utils_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/meenmo/molib/utils"
+)
+
+func TestInterp1D(t *testing.T) {
+	t.Parallel()
+
+	xs := []float64{1, 2, 5, 10}
+	ys := []float64{3.0, 3.5, 4.0, 5.0}
+
+	tests := []struct {
+		name        string
+		x           float64
+		extrapolate bool
+		want        float64
+	}{
+		{name: "interior", x: 3.5, want: 3.75},
+		{name: "interior last segment", x: 7.5, want: 4.5},
+		{name: "exact node", x: 2, want: 3.5},
+		{name: "first boundary", x: 1, want: 3.0},
+		{name: "last boundary", x: 10, want: 5.0},
+		{name: "before first flat", x: 0, extrapolate: true, want: 3.0},
+		{name: "after last flat", x: 20, extrapolate: true, want: 5.0},
+		{name: "before first linear", x: 0, want: 2.5},
+		{name: "after last linear", x: 15, want: 6.0},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			got := utils.Interp1D(xs, ys, tc.x, tc.extrapolate)
+			if math.Abs(got-tc.want) > 1e-12 {
+				t.Fatalf("Interp1D(%.2f, extrapolate=%v) = %.12f, want %.12f", tc.x, tc.extrapolate, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestInterp1D_SinglePoint(t *testing.T) {
+	t.Parallel()
+
+	if got := utils.Interp1D([]float64{1}, []float64{2.5}, 7, false); got != 2.5 {
+		t.Fatalf("single-point grid: got %.12f want 2.5", got)
+	}
+}